@@ -20,20 +20,20 @@ func (b BoundingBox) MarshalJSON() ([]byte, error) {
 		}
 
 		return json.Marshal(&position{
-			b.BottomLeft.pos.Lng.Degrees(),
-			b.BottomLeft.pos.Lat.Degrees(),
+			b.BottomLeft.lng,
+			b.BottomLeft.lat,
 			*b.BottomLeft.elevation,
-			b.TopRight.pos.Lng.Degrees(),
-			b.TopRight.pos.Lat.Degrees(),
+			b.TopRight.lng,
+			b.TopRight.lat,
 			*b.TopRight.elevation,
 		})
 	}
 
 	return json.Marshal(&position{
-		b.BottomLeft.pos.Lng.Degrees(),
-		b.BottomLeft.pos.Lat.Degrees(),
-		b.TopRight.pos.Lng.Degrees(),
-		b.TopRight.pos.Lat.Degrees(),
+		b.BottomLeft.lng,
+		b.BottomLeft.lat,
+		b.TopRight.lng,
+		b.TopRight.lat,
 	})
 }
 
@@ -62,9 +62,9 @@ func (b BoundingBox) Validate() error {
 	if (b.BottomLeft.elevation != nil && b.TopRight.elevation != nil) ||
 		(b.BottomLeft.elevation == nil && b.TopRight.elevation == nil) {
 		return fmt.Errorf("bounding box positions must be in the same dimension")
-	} else if !b.BottomLeft.pos.IsValid() {
+	} else if !b.BottomLeft.LatLng().IsValid() {
 		return fmt.Errorf("bottom left is invalid")
-	} else if !b.TopRight.pos.IsValid() {
+	} else if !b.TopRight.LatLng().IsValid() {
 		return fmt.Errorf("top right is invalid")
 	}
 	return nil
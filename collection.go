@@ -19,12 +19,16 @@ const (
 	GeometryCollectionType      GeometryType = "GeometryCollection"
 )
 
-// GeometryCollection is a heterogeneous collection of Geometry objects.
-type GeometryCollection []Geometry
+// GeometryCollection is a heterogeneous collection of Geometry objects, which is empty if there are none.
+// Unlike a Feature, the geometries in a collection can't be null.
+type GeometryCollection struct {
+	geometries []Geometry
+	foreign    map[string]json.RawMessage
+}
 
 // NewGeometryCollection returns a GeometryCollection Feature.
 func NewGeometryCollection(geometries ...Geometry) *GeometryCollection {
-	return (*GeometryCollection)(&geometries)
+	return &GeometryCollection{geometries: geometries}
 }
 
 // Type returns the geometry type.
@@ -32,17 +36,49 @@ func (c GeometryCollection) Type() GeometryType {
 	return GeometryCollectionType
 }
 
+// Geometries returns the geometries in the collection.
+func (c GeometryCollection) Geometries() []Geometry {
+	return c.geometries
+}
+
+// ForeignMembers returns a copy of the members of the collection that aren't defined by RFC 7946, or nil if there are none.
+func (c GeometryCollection) ForeignMembers() map[string]json.RawMessage {
+	return copyForeignMembers(c.foreign)
+}
+
+// WithForeignMember returns a copy of c with the supplied foreign member, which replaces any existing member with the same name.
+// The name must not be one of the members defined by RFC 7946 for a GeometryCollection.
+func (c GeometryCollection) WithForeignMember(name string, value json.RawMessage) *GeometryCollection {
+	c.foreign = withForeignMember(c.foreign, name, value)
+	return &c
+}
+
 // Validate the GeometryCollection.
 func (c GeometryCollection) Validate() error {
+	for _, geo := range c.geometries {
+		if isNilGeometry(geo) {
+			return fmt.Errorf("geometry collection must not contain null geometries")
+		}
+	}
 	return nil
 }
 
 // MarshalJSON returns the JSON encoding of the GeometryCollection.
+// Foreign members follow the members defined by RFC 7946, sorted by name.
 func (c GeometryCollection) MarshalJSON() ([]byte, error) {
-	return json.Marshal(geometryCollection{
+	geometries := c.geometries
+	if geometries == nil {
+		geometries = []Geometry{}
+	}
+
+	data, err := json.Marshal(geometryCollection{
 		Type:       GeometryCollectionType,
-		Geometries: c,
+		Geometries: geometries,
 	})
+	if err != nil {
+		return nil, err
+	}
+	return marshalForeignMembers(data, c.foreign, geometryCollectionMembers)
 }
 
 // UnmarshalJSON parses the JSON-encoded data and stores the result.
@@ -55,14 +91,21 @@ func (c *GeometryCollection) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
-	*c = make(GeometryCollection, len(collection.Geometries))
+	foreign, err := unmarshalForeignMembers(data, geometryCollectionMembers)
+	if err != nil {
+		return err
+	}
+
+	geometries := make([]Geometry, len(collection.Geometries))
 	for i, data := range collection.Geometries {
 		geo, err := unmarshalGeometry(data)
 		if err != nil {
 			return err
 		}
-		(*c)[i] = geo
+		geometries[i] = geo
 	}
+
+	*c = GeometryCollection{geometries: geometries, foreign: foreign}
 	return nil
 }
 
@@ -101,6 +144,18 @@ func unmarshalGeometry(data json.RawMessage) (Geometry, error) {
 	return geo, nil
 }
 
+// marshalGeometry returns the JSON encoding of a geometry with the supplied coordinates, followed by its foreign members.
+func marshalGeometry(typ GeometryType, coordinates interface{}, foreign map[string]json.RawMessage) ([]byte, error) {
+	data, err := json.Marshal(geometry{
+		Type:        typ,
+		Coordinates: coordinates,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return marshalForeignMembers(data, foreign, geometryMembers)
+}
+
 type geometry struct {
 	Type        GeometryType `json:"type"`
 	Coordinates interface{}  `json:"coordinates"`
@@ -76,3 +76,72 @@ func TestGeometryCollection(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, feature, unmarshalled)
 }
+
+func TestGeometryCollectionEmpty(t *testing.T) {
+	data, err := json.Marshal(geojson.GeometryCollection{})
+	require.NoError(t, err)
+	require.JSONEq(t, `{"type": "GeometryCollection", "geometries": []}`, string(data))
+
+	require.NoError(t, geojson.GeometryCollection{}.Validate())
+	require.Error(t, geojson.NewGeometryCollection(geojson.NewPoint(1, 2), nil).Validate())
+}
+
+func TestGeometryForeignMembers(t *testing.T) {
+	data := []byte(`
+		{
+			"type": "GeometryCollection",
+			"geometries": [
+				{"type": "Point", "coordinates": [1, 2], "title": "point"},
+				{"type": "MultiPoint", "coordinates": [[1, 2]], "title": "multipoint"},
+				{"type": "LineString", "coordinates": [[1, 2], [3, 4]], "title": "linestring"},
+				{"type": "MultiLineString", "coordinates": [[[1, 2], [3, 4]]], "title": "multilinestring"},
+				{"type": "Polygon", "coordinates": [], "title": "polygon"},
+				{"type": "MultiPolygon", "coordinates": [], "title": "multipolygon"},
+				{"type": "GeometryCollection", "geometries": [], "title": "nested"}
+			],
+			"links": [{"href":"https://example.com"}]
+		}`)
+
+	var collection geojson.GeometryCollection
+	err := json.Unmarshal(data, &collection)
+	require.NoError(t, err)
+	require.Equal(t, map[string]json.RawMessage{
+		"links": json.RawMessage(`[{"href":"https://example.com"}]`),
+	}, collection.ForeignMembers())
+
+	type foreignMembers interface {
+		ForeignMembers() map[string]json.RawMessage
+	}
+	for i, name := range []string{"point", "multipoint", "linestring", "multilinestring", "polygon", "multipolygon", "nested"} {
+		require.Equal(t, map[string]json.RawMessage{
+			"title": json.RawMessage(`"` + name + `"`),
+		}, collection.Geometries()[i].(foreignMembers).ForeignMembers())
+	}
+
+	marshalled, err := json.Marshal(collection)
+	require.NoError(t, err)
+	require.JSONEq(t, string(data), string(marshalled))
+
+	var unmarshalled geojson.GeometryCollection
+	err = json.Unmarshal(marshalled, &unmarshalled)
+	require.NoError(t, err)
+	require.Equal(t, collection, unmarshalled)
+
+	t.Run("copy", func(t *testing.T) {
+		point := geojson.NewPoint(2, 1).WithForeignMember("title", json.RawMessage(`"a"`))
+		moved := point.WithForeignMember("title", json.RawMessage(`"b"`))
+		require.Equal(t, json.RawMessage(`"a"`), point.ForeignMembers()["title"])
+		require.Equal(t, json.RawMessage(`"b"`), moved.ForeignMembers()["title"])
+
+		point.ForeignMembers()["title"] = json.RawMessage(`"c"`)
+		require.Equal(t, json.RawMessage(`"a"`), point.ForeignMembers()["title"])
+	})
+
+	t.Run("reserved", func(t *testing.T) {
+		_, err := json.Marshal(geojson.NewPoint(2, 1).WithForeignMember("coordinates", json.RawMessage(`[]`)))
+		require.Error(t, err)
+
+		_, err = json.Marshal(geojson.NewGeometryCollection().WithForeignMember("geometries", json.RawMessage(`[]`)))
+		require.Error(t, err)
+	})
+}
@@ -0,0 +1,331 @@
+package geojson
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// CSVOptions configures the columns of a CSV file that contain geometry.
+type CSVOptions struct {
+	// Latitude and Longitude are the names of the columns containing the coordinates of Points, which default to "lat" and "lon".
+	Latitude, Longitude string
+	// Elevation is the name of an optional column containing the elevation of Points.
+	Elevation string
+	// WKT is the name of a column containing geometries in Well-Known Text, which is used instead of the coordinate columns if set.
+	WKT string
+	// Comma is the field delimiter, which defaults to ','.
+	Comma rune
+}
+
+const (
+	csvDefaultLatitude  = "lat"
+	csvDefaultLongitude = "lon"
+)
+
+func (o *CSVOptions) setDefaults() {
+	if o.Latitude == "" {
+		o.Latitude = csvDefaultLatitude
+	}
+	if o.Longitude == "" {
+		o.Longitude = csvDefaultLongitude
+	}
+	if o.Comma == 0 {
+		o.Comma = ','
+	}
+}
+
+// geometryColumns returns the names of the columns that contain geometry.
+func (o CSVOptions) geometryColumns() []string {
+	if o.WKT != "" {
+		return []string{o.WKT}
+	} else if o.Elevation != "" {
+		return []string{o.Latitude, o.Longitude, o.Elevation}
+	}
+	return []string{o.Latitude, o.Longitude}
+}
+
+// ReadCSV reads a CSV file with a header row, returning a feature for each subsequent row.
+// Geometry is read from the columns specified by opts, and the other columns become properties.
+// Rows in which the geometry columns are empty become features without a geometry.
+//
+// The type of each property column is inferred from its values, which are int64 if every value is an integer,
+// float64 if every value is a number, bool if every value is "true" or "false", and string otherwise.
+// Integers with leading zeros, such as identifiers and postcodes, are treated as strings. Empty values are omitted.
+func ReadCSV(r io.Reader, opts CSVOptions) (FeatureCollection, error) {
+	opts.setDefaults()
+
+	cr := csv.NewReader(r)
+	cr.Comma = opts.Comma
+
+	header, err := cr.Read()
+	if err != nil {
+		return FeatureCollection{}, err
+	}
+
+	columns := make(map[string]int, len(header))
+	for i, name := range header {
+		if _, ok := columns[name]; ok {
+			return FeatureCollection{}, fmt.Errorf("duplicate column '%s'", name)
+		}
+		columns[name] = i
+	}
+
+	geoColumns := opts.geometryColumns()
+	geoIndices := make(map[int]bool, len(geoColumns))
+	for _, name := range geoColumns {
+		i, ok := columns[name]
+		if !ok {
+			return FeatureCollection{}, fmt.Errorf("column '%s' not found", name)
+		}
+		geoIndices[i] = true
+	}
+
+	var geometries []Geometry
+	var records [][]string
+	for row := 1; ; row++ {
+		record, err := cr.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return FeatureCollection{}, err
+		}
+
+		geo, err := readCSVGeometry(record, columns, opts)
+		if err != nil {
+			return FeatureCollection{}, fmt.Errorf("row %d: %w", row, err)
+		}
+		geometries = append(geometries, geo)
+		records = append(records, record)
+	}
+
+	types := make([]csvType, len(header))
+	for i := range header {
+		if !geoIndices[i] {
+			types[i] = inferCSVType(records, i)
+		}
+	}
+
+	features := make([]Feature[Geometry], len(records))
+	for i, record := range records {
+		var props []Property
+		for j, value := range record {
+			if geoIndices[j] || value == "" {
+				continue
+			}
+			props = append(props, Property{Name: header[j], Value: types[j].parse(value)})
+		}
+		features[i] = NewFeature(geometries[i], props...)
+	}
+	return NewFeatureCollection(features...), nil
+}
+
+// readCSVGeometry returns the geometry of the record, or nil if the geometry columns are empty.
+func readCSVGeometry(record []string, columns map[string]int, opts CSVOptions) (Geometry, error) {
+	empty := true
+	for _, name := range opts.geometryColumns() {
+		if strings.TrimSpace(record[columns[name]]) != "" {
+			empty = false
+		}
+	}
+	if empty {
+		return nil, nil
+	}
+
+	if opts.WKT != "" {
+		return UnmarshalWKT(record[columns[opts.WKT]])
+	}
+
+	var ordinates [3]float64
+	for i, name := range opts.geometryColumns() {
+		value := strings.TrimSpace(record[columns[name]])
+		if value == "" && name == opts.Elevation {
+			return NewPoint(ordinates[0], ordinates[1]), nil
+		}
+
+		v, err := strconv.ParseFloat(value, 64)
+		if err != nil {
+			return nil, fmt.Errorf("column '%s': %w", name, err)
+		}
+		ordinates[i] = v
+	}
+
+	if opts.Elevation != "" {
+		return NewPointWithElevation(ordinates[0], ordinates[1], ordinates[2]), nil
+	}
+	return NewPoint(ordinates[0], ordinates[1]), nil
+}
+
+type csvType int
+
+const (
+	csvString csvType = iota
+	csvBool
+	csvInt
+	csvFloat
+)
+
+// inferCSVType returns the most specific type that can represent every non-empty value in a column.
+func inferCSVType(records [][]string, column int) csvType {
+	isBool, isInt, isFloat := true, true, true
+	var hasValues bool
+	for _, record := range records {
+		value := record[column]
+		if value == "" {
+			continue
+		}
+		hasValues = true
+
+		isBool = isBool && (strings.EqualFold(value, "true") || strings.EqualFold(value, "false"))
+		if !isInt && !isFloat {
+			continue
+		}
+
+		// Leading zeros are significant in values such as postcodes. Only decimal forms are numbers, since strconv also
+		// accepts values such as "NaN", "Inf" and "0x1p-2", which are likely to be text and can't all be marshaled.
+		digits := strings.TrimLeft(value, "+-")
+		if strings.Trim(value, "0123456789+-.eE") != "" || len(digits) > 1 && digits[0] == '0' && digits[1] != '.' {
+			isInt, isFloat = false, false
+			continue
+		}
+
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			isInt = false
+		}
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			isFloat = false
+		}
+	}
+
+	switch {
+	case !hasValues:
+		return csvString
+	case isBool:
+		return csvBool
+	case isInt:
+		return csvInt
+	case isFloat:
+		return csvFloat
+	default:
+		return csvString
+	}
+}
+
+// parse returns the value as the type, which must have been inferred from it.
+func (t csvType) parse(value string) interface{} {
+	switch t {
+	case csvBool:
+		return strings.EqualFold(value, "true")
+	case csvInt:
+		v, _ := strconv.ParseInt(value, 10, 64)
+		return v
+	case csvFloat:
+		v, _ := strconv.ParseFloat(value, 64)
+		return v
+	default:
+		return value
+	}
+}
+
+// WriteCSV writes the features as a CSV file with a header row.
+// Geometry is written to the columns specified by opts, and it is an error for features to have geometry other than Points
+// unless a WKT column is specified. The geometry columns of features without a geometry are empty. Each property becomes a column, in the order in which they first appear,
+// with values that are not strings encoded as JSON.
+func WriteCSV(w io.Writer, c FeatureCollection, opts CSVOptions) error {
+	opts.setDefaults()
+
+	header := opts.geometryColumns()
+	columns := make(map[string]int)
+	for _, name := range header {
+		columns[name] = -1
+	}
+
+	for _, f := range c.features {
+		for _, prop := range f.properties {
+			if i, ok := columns[prop.Name]; !ok {
+				columns[prop.Name] = len(header)
+				header = append(header, prop.Name)
+			} else if i == -1 {
+				return fmt.Errorf("property '%s' has the same name as a geometry column", prop.Name)
+			}
+		}
+	}
+
+	cw := csv.NewWriter(w)
+	cw.Comma = opts.Comma
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, f := range c.features {
+		record := make([]string, len(header))
+		if f.HasGeometry() {
+			if err := writeCSVGeometry(record, f.geometry, opts); err != nil {
+				return err
+			}
+		}
+
+		for _, prop := range f.properties {
+			value, err := formatCSVValue(prop.Value)
+			if err != nil {
+				return fmt.Errorf("property '%s': %w", prop.Name, err)
+			}
+			record[columns[prop.Name]] = value
+		}
+
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeCSVGeometry sets the geometry columns at the start of the record.
+func writeCSVGeometry(record []string, geo Geometry, opts CSVOptions) error {
+	if opts.WKT != "" {
+		text, err := MarshalWKT(geo)
+		if err != nil {
+			return err
+		}
+		record[0] = text
+		return nil
+	}
+
+	point, ok := geo.(*Point)
+	if !ok {
+		return fmt.Errorf("unsupported geometry type '%T'", geo)
+	}
+
+	record[0] = strconv.FormatFloat(point.pos.lat, 'f', -1, 64)
+	record[1] = strconv.FormatFloat(point.pos.lng, 'f', -1, 64)
+	if opts.Elevation != "" && point.pos.elevation != nil {
+		record[2] = strconv.FormatFloat(*point.pos.elevation, 'f', -1, 64)
+	}
+	return nil
+}
+
+func formatCSVValue(value interface{}) (string, error) {
+	switch v := value.(type) {
+	case nil:
+		return "", nil
+	case string:
+		return v, nil
+	}
+
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+
+	// Values that are encoded as JSON strings, such as times, are written without quotes.
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		return s, nil
+	}
+	return string(data), nil
+}
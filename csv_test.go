@@ -0,0 +1,153 @@
+package geojson_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	geojson "github.com/everystreet/go-geojson/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadCSV(t *testing.T) {
+	c, err := geojson.ReadCSV(strings.NewReader(`name,lat,lon,count,score,visited,postcode,notes
+Milan,45.4642035,9.189982,3,1.5,true,20121,
+Rome,41.9,12.5,4,2,FALSE,00118,capital
+`), geojson.CSVOptions{})
+	require.NoError(t, err)
+	require.Equal(t, geojson.NewFeatureCollection(
+		geojson.NewFeature[geojson.Geometry](
+			geojson.NewPoint(45.4642035, 9.189982),
+			geojson.Property{Name: "name", Value: "Milan"},
+			geojson.Property{Name: "count", Value: int64(3)},
+			geojson.Property{Name: "score", Value: 1.5},
+			geojson.Property{Name: "visited", Value: true},
+			geojson.Property{Name: "postcode", Value: "20121"},
+		),
+		geojson.NewFeature[geojson.Geometry](
+			geojson.NewPoint(41.9, 12.5),
+			geojson.Property{Name: "name", Value: "Rome"},
+			geojson.Property{Name: "count", Value: int64(4)},
+			geojson.Property{Name: "score", Value: 2.0},
+			geojson.Property{Name: "visited", Value: false},
+			geojson.Property{Name: "postcode", Value: "00118"},
+			geojson.Property{Name: "notes", Value: "capital"},
+		),
+	), c)
+}
+
+func TestReadCSVColumns(t *testing.T) {
+	c, err := geojson.ReadCSV(strings.NewReader(`Y;X;Z
+45.5;9.25;125
+41.9;12.5;
+`), geojson.CSVOptions{Latitude: "Y", Longitude: "X", Elevation: "Z", Comma: ';'})
+	require.NoError(t, err)
+	require.Equal(t, geojson.NewFeatureCollection(
+		geojson.NewFeature[geojson.Geometry](geojson.NewPointWithElevation(45.5, 9.25, 125)),
+		geojson.NewFeature[geojson.Geometry](geojson.NewPoint(41.9, 12.5)),
+	), c)
+}
+
+func TestReadCSVNonNumeric(t *testing.T) {
+	c, err := geojson.ReadCSV(strings.NewReader(`name,code,size,lat,lon
+Nan,0x1F,1_000,1,2
+Inf,12,2,3,4
+`), geojson.CSVOptions{})
+	require.NoError(t, err)
+	require.Equal(t, geojson.NewFeatureCollection(
+		geojson.NewFeature[geojson.Geometry](
+			geojson.NewPoint(1, 2),
+			geojson.Property{Name: "name", Value: "Nan"},
+			geojson.Property{Name: "code", Value: "0x1F"},
+			geojson.Property{Name: "size", Value: "1_000"},
+		),
+		geojson.NewFeature[geojson.Geometry](
+			geojson.NewPoint(3, 4),
+			geojson.Property{Name: "name", Value: "Inf"},
+			geojson.Property{Name: "code", Value: "12"},
+			geojson.Property{Name: "size", Value: "2"},
+		),
+	), c)
+
+	_, err = json.Marshal(c)
+	require.NoError(t, err)
+}
+
+func TestCSVWKT(t *testing.T) {
+	c := geojson.NewFeatureCollection(
+		geojson.NewFeature[geojson.Geometry](
+			geojson.NewLineString(
+				geojson.MakePosition(12, 34),
+				geojson.MakePosition(56, 78),
+			),
+			geojson.Property{Name: "name", Value: "a, b"},
+		),
+		geojson.NewFeature[geojson.Geometry](
+			geojson.NewPoint(45.4642035, 9.189982),
+			geojson.Property{Name: "id", Value: int64(2)},
+		),
+		geojson.NewFeature[geojson.Geometry](
+			nil,
+			geojson.Property{Name: "name", Value: "unlocated"},
+		),
+	)
+
+	var buf bytes.Buffer
+	require.NoError(t, geojson.WriteCSV(&buf, c, geojson.CSVOptions{WKT: "geometry"}))
+	require.Equal(t, `geometry,name,id
+"LINESTRING (34 12, 78 56)","a, b",
+POINT (9.189982 45.4642035),,2
+,unlocated,
+`, buf.String())
+
+	read, err := geojson.ReadCSV(&buf, geojson.CSVOptions{WKT: "geometry"})
+	require.NoError(t, err)
+	require.Equal(t, c, read)
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, geojson.WriteCSV(&buf, geojson.NewFeatureCollection(
+		geojson.NewFeature[geojson.Geometry](
+			geojson.NewPointWithElevation(45.5, 9.25, 125),
+			geojson.Property{Name: "tags", Value: []string{"a", "b"}},
+			geojson.Property{Name: "empty", Value: nil},
+		),
+		geojson.NewFeature[geojson.Geometry](
+			geojson.NewPoint(41.9, 12.5),
+			geojson.Property{Name: "visited", Value: true},
+		),
+	), geojson.CSVOptions{Elevation: "ele"}))
+	require.Equal(t, `lat,lon,ele,tags,empty,visited
+45.5,9.25,125,"[""a"",""b""]",,
+41.9,12.5,,,,true
+`, buf.String())
+}
+
+func TestCSVInvalid(t *testing.T) {
+	for name, data := range map[string]string{
+		"missing column":     "lat,x\n1,2\n",
+		"invalid coordinate": "lat,lon\n1,a\n",
+		"duplicate column":   "lat,lon,lat\n1,2,3\n",
+		"empty":              "",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := geojson.ReadCSV(strings.NewReader(data), geojson.CSVOptions{})
+			require.Error(t, err)
+		})
+	}
+
+	err := geojson.WriteCSV(&bytes.Buffer{}, geojson.NewFeatureCollection(
+		geojson.NewFeature[geojson.Geometry](geojson.NewMultiPoint(geojson.MakePosition(1, 2))),
+	), geojson.CSVOptions{})
+	require.Error(t, err)
+
+	err = geojson.WriteCSV(&bytes.Buffer{}, geojson.NewFeatureCollection(
+		geojson.NewFeature[geojson.Geometry](
+			geojson.NewPoint(1, 2),
+			geojson.Property{Name: "lat", Value: 1},
+		),
+	), geojson.CSVOptions{})
+	require.Error(t, err)
+}
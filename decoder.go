@@ -0,0 +1,164 @@
+package geojson
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Decoder reads the features of a FeatureCollection from an input stream one at a time,
+// without holding the whole collection in memory.
+// The members of the collection may appear in any order, so features are returned as they are read,
+// and the "type" member is checked once it has been read, which may be after the features.
+type Decoder struct {
+	dec     *json.Decoder
+	typ     string
+	box     *BoundingBox
+	foreign map[string]json.RawMessage
+	state   decoderState
+	err     error
+}
+
+type decoderState int
+
+const (
+	decoderStart decoderState = iota
+	decoderMembers
+	decoderFeatures
+	decoderDone
+)
+
+// NewDecoder returns a new Decoder that reads from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{
+		dec:     json.NewDecoder(r),
+		foreign: map[string]json.RawMessage{},
+	}
+}
+
+// Decode returns the next Feature in the collection.
+// It returns io.EOF once the end of the collection has been reached.
+func (d *Decoder) Decode() (Feature[Geometry], error) {
+	if d.err != nil {
+		return Feature[Geometry]{}, d.err
+	}
+
+	feature, err := d.decode()
+	if err != nil {
+		d.err = err
+	}
+	return feature, err
+}
+
+// BoundingBox returns the bounding box of the collection.
+// As the members of a JSON object are unordered, the bounding box is only guaranteed
+// to have been read once Decode has returned io.EOF.
+func (d *Decoder) BoundingBox() *BoundingBox {
+	return d.box
+}
+
+// ForeignMembers returns the members of the collection that aren't defined by RFC 7946.
+// As with BoundingBox, the result is only complete once Decode has returned io.EOF.
+// It is a copy, or nil if there are none.
+func (d *Decoder) ForeignMembers() map[string]json.RawMessage {
+	return copyForeignMembers(d.foreign)
+}
+
+func (d *Decoder) decode() (Feature[Geometry], error) {
+	for {
+		switch d.state {
+		case decoderStart:
+			if err := d.expectDelim('{'); err != nil {
+				return Feature[Geometry]{}, err
+			}
+			d.state = decoderMembers
+
+		case decoderMembers:
+			if !d.dec.More() {
+				if err := d.expectDelim('}'); err != nil {
+					return Feature[Geometry]{}, err
+				} else if d.typ == "" {
+					return Feature[Geometry]{}, fmt.Errorf("type is missing, expecting '%s'", TypePropFeatureCollection)
+				}
+				d.state = decoderDone
+				continue
+			}
+
+			if err := d.decodeMember(); err != nil {
+				return Feature[Geometry]{}, err
+			}
+
+		case decoderFeatures:
+			if !d.dec.More() {
+				if err := d.expectDelim(']'); err != nil {
+					return Feature[Geometry]{}, err
+				}
+				d.state = decoderMembers
+				continue
+			}
+
+			var feature Feature[Geometry]
+			if err := d.dec.Decode(&feature); err != nil {
+				return Feature[Geometry]{}, fmt.Errorf("failed to decode feature: %w", err)
+			}
+			return feature, nil
+
+		case decoderDone:
+			return Feature[Geometry]{}, io.EOF
+		}
+	}
+}
+
+func (d *Decoder) decodeMember() error {
+	tok, err := d.dec.Token()
+	if err != nil {
+		return err
+	}
+
+	key, ok := tok.(string)
+	if !ok {
+		return fmt.Errorf("unexpected token '%v'", tok)
+	}
+
+	switch key {
+	case "type":
+		if err := d.dec.Decode(&d.typ); err != nil {
+			return fmt.Errorf("failed to decode type: %w", err)
+		} else if d.typ != TypePropFeatureCollection {
+			return fmt.Errorf("type is '%s', expecting '%s'", d.typ, TypePropFeatureCollection)
+		}
+	case "bbox":
+		if err := d.dec.Decode(&d.box); err != nil {
+			return fmt.Errorf("failed to decode bbox: %w", err)
+		}
+	case "features":
+		tok, err := d.dec.Token()
+		if err != nil {
+			return err
+		} else if tok == nil {
+			return nil
+		} else if delim, ok := tok.(json.Delim); !ok || delim != '[' {
+			return fmt.Errorf("features must be an array")
+		}
+		d.state = decoderFeatures
+	default:
+		var raw json.RawMessage
+		if err := d.dec.Decode(&raw); err != nil {
+			return fmt.Errorf("failed to decode member '%s': %w", key, err)
+		}
+		d.foreign[key] = raw
+	}
+	return nil
+}
+
+func (d *Decoder) expectDelim(delim json.Delim) error {
+	tok, err := d.dec.Token()
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	} else if err != nil {
+		return err
+	} else if tok != delim {
+		return fmt.Errorf("unexpected token '%v', expecting '%v'", tok, delim)
+	}
+	return nil
+}
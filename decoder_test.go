@@ -0,0 +1,166 @@
+package geojson_test
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	geojson "github.com/everystreet/go-geojson/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecoder(t *testing.T) {
+	dec := geojson.NewDecoder(strings.NewReader(`
+		{
+			"type": "FeatureCollection",
+			"features": [
+				{
+					"type": "Feature",
+					"geometry": {
+						"type": "Point",
+						"coordinates": [9.189982, 45.4642035]
+					}
+				},
+				{
+					"type": "Feature",
+					"geometry": {
+						"type": "LineString",
+						"coordinates": [
+							[34, 12],
+							[78, 56]
+						]
+					},
+					"properties": {
+						"name": "road"
+					}
+				}
+			],
+			"bbox": [
+				7.1827761,  43.7032932,
+				11.2387051, 47.2856026
+			],
+			"title": "example"
+		}`))
+
+	feature, err := dec.Decode()
+	require.NoError(t, err)
+	require.Equal(t, geojson.NewPoint(45.4642035, 9.189982), feature.Geometry())
+
+	feature, err = dec.Decode()
+	require.NoError(t, err)
+	require.Equal(t, geojson.NewLineString(
+		geojson.MakePosition(12, 34),
+		geojson.MakePosition(56, 78),
+	), feature.Geometry())
+	require.Equal(t, geojson.PropertyList{{Name: "name", Value: "road"}}, feature.Properties())
+
+	_, err = dec.Decode()
+	require.Equal(t, io.EOF, err)
+
+	require.Equal(t, &geojson.BoundingBox{
+		BottomLeft: geojson.MakePosition(43.7032932, 7.1827761),
+		TopRight:   geojson.MakePosition(47.2856026, 11.2387051),
+	}, dec.BoundingBox())
+	require.Equal(t, map[string]json.RawMessage{
+		"title": json.RawMessage(`"example"`),
+	}, dec.ForeignMembers())
+}
+
+func TestDecoderFeaturesBeforeType(t *testing.T) {
+	dec := geojson.NewDecoder(strings.NewReader(`
+		{
+			"features": [
+				{"type": "Feature", "geometry": {"type": "Point", "coordinates": [9.189982, 45.4642035]}},
+				{"type": "Feature", "geometry": null}
+			],
+			"bbox": [9.189982, 45.4642035, 9.189982, 45.4642035],
+			"type": "FeatureCollection"
+		}`))
+
+	var features []geojson.Feature[geojson.Geometry]
+	for {
+		feature, err := dec.Decode()
+		if err == io.EOF {
+			break
+		}
+		require.NoError(t, err)
+		features = append(features, feature)
+	}
+
+	require.Equal(t, []geojson.Feature[geojson.Geometry]{
+		geojson.NewFeature[geojson.Geometry](geojson.NewPoint(45.4642035, 9.189982)),
+		geojson.NewFeature[geojson.Geometry](nil),
+	}, features)
+	require.NotNil(t, dec.BoundingBox())
+}
+
+func TestDecoderErrors(t *testing.T) {
+	t.Run("wrong type", func(t *testing.T) {
+		dec := geojson.NewDecoder(strings.NewReader(`{"type": "Feature", "features": []}`))
+
+		_, err := dec.Decode()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "expecting 'FeatureCollection'")
+	})
+
+	t.Run("wrong type before features", func(t *testing.T) {
+		dec := geojson.NewDecoder(strings.NewReader(`{"type": "Feature", "features": [{"type": "Feature", "geometry": null}]}`))
+
+		_, err := dec.Decode()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "expecting 'FeatureCollection'")
+	})
+
+	t.Run("wrong type after features", func(t *testing.T) {
+		dec := geojson.NewDecoder(strings.NewReader(`{"features": [{"type": "Feature", "geometry": null}], "type": "Feature"}`))
+
+		_, err := dec.Decode()
+		require.NoError(t, err)
+
+		_, err = dec.Decode()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "expecting 'FeatureCollection'")
+	})
+
+	t.Run("missing type after features", func(t *testing.T) {
+		dec := geojson.NewDecoder(strings.NewReader(`{"features": []}`))
+
+		_, err := dec.Decode()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "type is missing")
+	})
+
+	t.Run("missing type", func(t *testing.T) {
+		dec := geojson.NewDecoder(strings.NewReader(`{"bbox": [1, 2, 3, 4]}`))
+
+		_, err := dec.Decode()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "type is missing")
+	})
+
+	t.Run("invalid feature", func(t *testing.T) {
+		dec := geojson.NewDecoder(strings.NewReader(`
+			{
+				"type": "FeatureCollection",
+				"features": [
+					{
+						"type": "Feature",
+						"geometry": {"type": "Triangle"}
+					}
+				]
+			}`))
+
+		_, err := dec.Decode()
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "unknown geometry type")
+	})
+
+	t.Run("truncated", func(t *testing.T) {
+		dec := geojson.NewDecoder(strings.NewReader(`{"type": "FeatureCollection", "features": [`))
+
+		_, err := dec.Decode()
+		require.Error(t, err)
+		require.NotEqual(t, io.EOF, err)
+	})
+}
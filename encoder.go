@@ -0,0 +1,110 @@
+package geojson
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Encoder writes a FeatureCollection to an output stream one Feature at a time,
+// without holding the whole collection in memory.
+// The output is identical to that of FeatureCollection.MarshalJSON.
+type Encoder[G Geometry] struct {
+	w       io.Writer
+	box     *BoundingBox
+	foreign map[string]json.RawMessage
+	count   int
+	closed  bool
+	err     error
+}
+
+// NewEncoder returns a new Encoder that writes to w.
+func NewEncoder[G Geometry](w io.Writer) *Encoder[G] {
+	return &Encoder[G]{
+		w: w,
+	}
+}
+
+// NewEncoderWithBoundingBox returns a new Encoder that writes to w, including the supplied bounding box.
+func NewEncoderWithBoundingBox[G Geometry](w io.Writer, box BoundingBox) *Encoder[G] {
+	return &Encoder[G]{
+		w:   w,
+		box: &box,
+	}
+}
+
+// Encode writes the feature to the stream.
+func (e *Encoder[G]) Encode(feature Feature[G]) error {
+	if e.err != nil {
+		return e.err
+	} else if e.closed {
+		return fmt.Errorf("encoder is closed")
+	}
+
+	data, err := json.Marshal(feature)
+	if err != nil {
+		return err
+	}
+
+	if e.count == 0 {
+		e.writeHeader()
+	} else {
+		e.write([]byte{','})
+	}
+	e.write(data)
+
+	e.count++
+	return e.err
+}
+
+// SetForeignMembers sets the members of the collection that aren't defined by RFC 7946.
+// They are written after the features, so they may be set at any time before Close, such as once a Decoder has returned io.EOF.
+func (e *Encoder[G]) SetForeignMembers(foreign map[string]json.RawMessage) {
+	e.foreign = foreign
+}
+
+// Close terminates the collection. It does not close the underlying writer.
+func (e *Encoder[G]) Close() error {
+	if e.err != nil {
+		return e.err
+	} else if e.closed {
+		return nil
+	}
+
+	if e.count == 0 {
+		e.writeHeader()
+	}
+
+	end, err := marshalForeignMembers([]byte("]}"), e.foreign, featureCollectionMembers)
+	if err != nil {
+		e.err = err
+		return err
+	}
+	e.write(end)
+
+	e.closed = true
+	return e.err
+}
+
+func (e *Encoder[G]) writeHeader() {
+	e.write([]byte(`{"type":"` + TypePropFeatureCollection + `"`))
+
+	if e.box != nil {
+		data, err := json.Marshal(e.box)
+		if err != nil {
+			e.err = err
+			return
+		}
+		e.write([]byte(`,"bbox":`))
+		e.write(data)
+	}
+
+	e.write([]byte(`,"features":[`))
+}
+
+func (e *Encoder[G]) write(data []byte) {
+	if e.err != nil {
+		return
+	}
+	_, e.err = e.w.Write(data)
+}
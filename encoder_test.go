@@ -0,0 +1,90 @@
+package geojson_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	geojson "github.com/everystreet/go-geojson/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncoder(t *testing.T) {
+	features := []geojson.Feature[geojson.Geometry]{
+		geojson.NewFeature[geojson.Geometry](
+			geojson.NewPoint(45.4642035, 9.189982),
+			geojson.Property{Name: "city", Value: "Milan"},
+		),
+		geojson.NewFeature[geojson.Geometry](
+			geojson.NewLineString(
+				geojson.MakePosition(12, 34),
+				geojson.MakePosition(56, 78),
+			),
+		),
+	}
+
+	var buf bytes.Buffer
+	enc := geojson.NewEncoder[geojson.Geometry](&buf)
+	for _, feature := range features {
+		err := enc.Encode(feature)
+		require.NoError(t, err)
+	}
+	err := enc.Close()
+	require.NoError(t, err)
+
+	expected, err := json.Marshal(geojson.NewFeatureCollection(features...))
+	require.NoError(t, err)
+	require.Equal(t, string(expected), buf.String())
+}
+
+func TestEncoderWithBoundingBox(t *testing.T) {
+	box := geojson.BoundingBox{
+		BottomLeft: geojson.MakePosition(43.7032932, 7.1827761),
+		TopRight:   geojson.MakePosition(47.2856026, 11.2387051),
+	}
+
+	var buf bytes.Buffer
+	enc := geojson.NewEncoderWithBoundingBox[*geojson.Point](&buf, box)
+	err := enc.Encode(geojson.NewFeature(geojson.NewPoint(45.4642035, 9.189982)))
+	require.NoError(t, err)
+	err = enc.Close()
+	require.NoError(t, err)
+
+	expected, err := json.Marshal(geojson.NewFeatureCollectionWithBoundingBox(
+		box,
+		geojson.NewFeature[geojson.Geometry](geojson.NewPoint(45.4642035, 9.189982)),
+	))
+	require.NoError(t, err)
+	require.Equal(t, string(expected), buf.String())
+
+	err = enc.Encode(geojson.NewFeature(geojson.NewPoint(45.4642035, 9.189982)))
+	require.Error(t, err)
+}
+
+func TestEncoderForeignMembers(t *testing.T) {
+	feature := geojson.NewFeature[geojson.Geometry](geojson.NewPoint(45.4642035, 9.189982))
+
+	var buf bytes.Buffer
+	enc := geojson.NewEncoder[geojson.Geometry](&buf)
+	err := enc.Encode(feature)
+	require.NoError(t, err)
+	enc.SetForeignMembers(map[string]json.RawMessage{"title": json.RawMessage(`"example"`)})
+	err = enc.Close()
+	require.NoError(t, err)
+
+	expected, err := json.Marshal(geojson.NewFeatureCollection(feature).WithForeignMember("title", json.RawMessage(`"example"`)))
+	require.NoError(t, err)
+	require.Equal(t, string(expected), buf.String())
+}
+
+func TestEncoderEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	enc := geojson.NewEncoder[geojson.Geometry](&buf)
+	err := enc.Close()
+	require.NoError(t, err)
+
+	expected, err := json.Marshal(geojson.NewFeatureCollection())
+	require.NoError(t, err)
+	require.Equal(t, string(expected), buf.String())
+	require.Equal(t, `{"type":"FeatureCollection","features":[]}`, buf.String())
+}
@@ -1,8 +1,12 @@
 package geojson
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"reflect"
+	"sort"
 )
 
 // "type" properties.
@@ -28,14 +32,26 @@ func NewFeatureWithBoundingBox[G Geometry](geometry G, box BoundingBox, properti
 	}
 }
 
+// NewFeatureWithID creates a new feature with the supplied ID.
+func NewFeatureWithID[G Geometry](geometry G, id FeatureID, properties ...Property) Feature[G] {
+	return Feature[G]{
+		geometry:   geometry,
+		id:         &id,
+		properties: PropertyList(properties),
+	}
+}
+
 // Feature consists of a specific geometry type and a list of properties.
 type Feature[G Geometry] struct {
 	geometry   G
+	id         *FeatureID
 	box        *BoundingBox
 	properties PropertyList
+	foreign    map[string]json.RawMessage
 }
 
 // Geometry contains the points represented by a particular geometry type.
+// Each geometry type keeps the members that aren't defined by RFC 7946, which are available from its ForeignMembers method.
 type Geometry interface {
 	json.Marshaler
 	json.Unmarshaler
@@ -43,16 +59,33 @@ type Geometry interface {
 	Validate() error
 }
 
-// Validate the feature geometry.
+// Validate the feature geometry. Features without a geometry are valid.
 func (f Feature[G]) Validate() error {
+	if !f.HasGeometry() {
+		return nil
+	}
 	return f.geometry.Validate()
 }
 
-// Geometry returns the stored geometry.
+// Geometry returns the stored geometry, or nil if the feature has no geometry.
 func (f Feature[G]) Geometry() Geometry {
+	if !f.HasGeometry() {
+		return nil
+	}
 	return f.geometry
 }
 
+// HasGeometry reports whether the feature has a geometry.
+// Unlocated features have a null geometry, which is represented by the zero value of G, such as a nil Geometry or *Point.
+func (f Feature[G]) HasGeometry() bool {
+	return !isNilGeometry(f.geometry)
+}
+
+// ID returns the stored ID, or nil if the feature has no ID.
+func (f Feature[G]) ID() *FeatureID {
+	return f.id
+}
+
 // BoundingBox returns the stored bounding box.
 func (f Feature[G]) BoundingBox() *BoundingBox {
 	return f.box
@@ -63,33 +96,57 @@ func (f Feature[G]) Properties() PropertyList {
 	return f.properties
 }
 
+// ForeignMembers returns a copy of the members of the feature that aren't defined by RFC 7946, or nil if there are none.
+func (f Feature[G]) ForeignMembers() map[string]json.RawMessage {
+	return copyForeignMembers(f.foreign)
+}
+
+// WithID returns a copy of f with the supplied ID.
+func (f Feature[G]) WithID(id FeatureID) Feature[G] {
+	f.id = &id
+	return f
+}
+
 // WithProperties returns a copy of f with the supplied properties appended.
 func (f Feature[G]) WithProperties(properties ...Property) Feature[G] {
-	if f.box == nil {
-		return NewFeature(f.geometry, append(f.properties, properties...)...)
-	}
-	return NewFeatureWithBoundingBox(f.geometry, *f.box, append(f.properties, properties...)...)
+	f.properties = append(f.properties[:len(f.properties):len(f.properties)], properties...)
+	return f
+}
+
+// WithForeignMember returns a copy of f with the supplied foreign member, which replaces any existing member with the same name.
+// The name must not be one of the members defined by RFC 7946 for a Feature.
+func (f Feature[G]) WithForeignMember(name string, value json.RawMessage) Feature[G] {
+	f.foreign = withForeignMember(f.foreign, name, value)
+	return f
 }
 
 // MarshalJSON returns the JSON encoding of the Feature.
+// Foreign members follow the members defined by RFC 7946, sorted by name.
 func (f Feature[G]) MarshalJSON() ([]byte, error) {
-	return json.Marshal(struct {
+	data, err := json.Marshal(struct {
 		Type       string       `json:"type"`
+		ID         *FeatureID   `json:"id,omitempty"`
 		Box        *BoundingBox `json:"bbox,omitempty"`
 		Geometry   Geometry     `json:"geometry"`
 		Properties PropertyList `json:"properties,omitempty"`
 	}{
 		Type:       TypePropFeature,
+		ID:         f.id,
 		Box:        f.box,
-		Geometry:   f.geometry,
+		Geometry:   f.Geometry(),
 		Properties: f.properties,
 	})
+	if err != nil {
+		return nil, err
+	}
+	return marshalForeignMembers(data, f.foreign, featureMembers)
 }
 
 // UnmarshalJSON parses the JSON-encoded data and stores the result.
 func (f *Feature[G]) UnmarshalJSON(data []byte) error {
 	var feature struct {
 		Type       string          `json:"type"`
+		ID         *FeatureID      `json:"id,omitempty"`
 		Box        *BoundingBox    `json:"bbox,omitempty"`
 		Geometry   json.RawMessage `json:"geometry"`
 		Properties PropertyList    `json:"properties,omitempty"`
@@ -101,17 +158,42 @@ func (f *Feature[G]) UnmarshalJSON(data []byte) error {
 		return fmt.Errorf("type is '%s', expecting '%s'", feature.Type, TypePropFeature)
 	}
 
+	foreign, err := unmarshalForeignMembers(data, featureMembers)
+	if err != nil {
+		return err
+	}
+
+	f.id = feature.ID
 	f.box = feature.Box
 	f.properties = feature.Properties
+	f.foreign = foreign
 
-	geo, err := unmarshalGeometry(feature.Geometry)
+	geo, err := unmarshalFeatureGeometry(feature.Geometry)
 	if err != nil {
 		return err
 	}
-	f.geometry = geo.(G)
+
+	var ok bool
+	if f.geometry, ok = geo.(G); !ok && geo != nil {
+		return fmt.Errorf("geometry type '%s' is not supported by the feature", geo.Type())
+	}
 	return nil
 }
 
+// unmarshalFeatureGeometry returns the geometry of a feature, which is nil if it is null or missing.
+// As permitted by RFC 7946, a Point with empty coordinates is also interpreted as a null geometry.
+func unmarshalFeatureGeometry(data json.RawMessage) (Geometry, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, nil
+	}
+
+	geo, err := unmarshalGeometry(data)
+	if errors.Is(err, errEmptyPoint) {
+		return nil, nil
+	}
+	return geo, err
+}
+
 // NewFeatureCollection creates a new feature collection.
 func NewFeatureCollection(features ...Feature[Geometry]) FeatureCollection {
 	return FeatureCollection{
@@ -131,15 +213,39 @@ func NewFeatureCollectionWithBoundingBox(box BoundingBox, features ...Feature[Ge
 type FeatureCollection struct {
 	features []Feature[Geometry]
 	box      *BoundingBox
+	foreign  map[string]json.RawMessage
+}
+
+// ForeignMembers returns a copy of the members of the collection that aren't defined by RFC 7946, or nil if there are none.
+func (c FeatureCollection) ForeignMembers() map[string]json.RawMessage {
+	return copyForeignMembers(c.foreign)
+}
+
+// WithForeignMember returns a copy of c with the supplied foreign member, which replaces any existing member with the same name.
+// The name must not be one of the members defined by RFC 7946 for a FeatureCollection.
+func (c FeatureCollection) WithForeignMember(name string, value json.RawMessage) FeatureCollection {
+	c.foreign = withForeignMember(c.foreign, name, value)
+	return c
 }
 
 // MarshalJSON returns the JSON encoding of the FeatureCollection.
+// Foreign members follow the members defined by RFC 7946, sorted by name.
 func (c FeatureCollection) MarshalJSON() ([]byte, error) {
-	return json.Marshal(&featureCollection{
+	features := c.features
+	if features == nil {
+		// RFC 7946 requires features to be an array, even if it is empty.
+		features = []Feature[Geometry]{}
+	}
+
+	data, err := json.Marshal(&featureCollection{
 		Type:     TypePropFeatureCollection,
 		Box:      c.box,
-		Features: c.features,
+		Features: features,
 	})
+	if err != nil {
+		return nil, err
+	}
+	return marshalForeignMembers(data, c.foreign, featureCollectionMembers)
 }
 
 // UnmarshalJSON parses the JSON-encoded data and stores the result.
@@ -153,8 +259,14 @@ func (c *FeatureCollection) UnmarshalJSON(data []byte) error {
 		return fmt.Errorf("type is '%s', expecting '%s'", col.Type, TypePropFeatureCollection)
 	}
 
+	foreign, err := unmarshalForeignMembers(data, featureCollectionMembers)
+	if err != nil {
+		return err
+	}
+
 	c.box = col.Box
 	c.features = col.Features
+	c.foreign = foreign
 	return nil
 }
 
@@ -163,3 +275,97 @@ type featureCollection struct {
 	Box      *BoundingBox        `json:"bbox,omitempty"`
 	Features []Feature[Geometry] `json:"features"`
 }
+
+var errNoGeometry = fmt.Errorf("feature has no geometry")
+
+// isNilGeometry reports whether geo is nil, or a nil pointer to a geometry.
+func isNilGeometry(geo Geometry) bool {
+	if geo == nil {
+		return true
+	}
+	v := reflect.ValueOf(geo)
+	return v.Kind() == reflect.Ptr && v.IsNil()
+}
+
+// Members defined by RFC 7946, which can't be used as foreign members.
+var (
+	featureMembers            = []string{"type", "id", "bbox", "geometry", "properties"}
+	featureCollectionMembers  = []string{"type", "bbox", "features"}
+	geometryMembers           = []string{"type", "bbox", "coordinates"}
+	geometryCollectionMembers = []string{"type", "bbox", "geometries"}
+)
+
+// withForeignMember returns a copy of the members with the supplied member added.
+func withForeignMember(foreign map[string]json.RawMessage, name string, value json.RawMessage) map[string]json.RawMessage {
+	members := make(map[string]json.RawMessage, len(foreign)+1)
+	for k, v := range foreign {
+		members[k] = v
+	}
+	members[name] = value
+	return members
+}
+
+// copyForeignMembers returns a copy of the foreign members, so that callers can't modify those of an immutable value.
+func copyForeignMembers(foreign map[string]json.RawMessage) map[string]json.RawMessage {
+	if len(foreign) == 0 {
+		return nil
+	}
+
+	members := make(map[string]json.RawMessage, len(foreign))
+	for k, v := range foreign {
+		members[k] = v
+	}
+	return members
+}
+
+// marshalForeignMembers appends the foreign members, sorted by name, to the JSON object in data.
+func marshalForeignMembers(data []byte, foreign map[string]json.RawMessage, reserved []string) ([]byte, error) {
+	if len(foreign) == 0 {
+		return data, nil
+	}
+
+	names := make([]string, 0, len(foreign))
+	for name := range foreign {
+		if containsString(reserved, name) {
+			return nil, fmt.Errorf("foreign member '%s' is reserved", name)
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	buf := bytes.NewBuffer(data[:len(data)-1])
+	for _, name := range names {
+		key, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := json.Marshal(foreign[name])
+		if err != nil {
+			return nil, fmt.Errorf("foreign member '%s': %w", name, err)
+		}
+
+		buf.WriteByte(',')
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// unmarshalForeignMembers returns the members of the JSON object in data that aren't reserved, or nil if there are none.
+func unmarshalForeignMembers(data []byte, reserved []string) (map[string]json.RawMessage, error) {
+	var members map[string]json.RawMessage
+	if err := json.Unmarshal(data, &members); err != nil {
+		return nil, err
+	}
+
+	for _, name := range reserved {
+		delete(members, name)
+	}
+	if len(members) == 0 {
+		return nil, nil
+	}
+	return members, nil
+}
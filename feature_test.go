@@ -95,6 +95,166 @@ func TestFeatureWithProperties(t *testing.T) {
 	require.Equal(t, feature.Properties(), unmarshalled.Properties())
 }
 
+func TestFeatureWithID(t *testing.T) {
+	feature := geojson.NewFeatureWithID(
+		geojson.NewPoint(45.4642035, 9.189982),
+		geojson.UintFeatureID(18446744073709551615),
+	)
+
+	data, err := json.Marshal(feature)
+	require.NoError(t, err)
+	require.JSONEq(t, `
+		{
+			"type": "Feature",
+			"id": 18446744073709551615,
+			"geometry": {
+				"type": "Point",
+				"coordinates": [9.189982, 45.4642035]
+			}
+		}`, string(data))
+
+	var unmarshalled geojson.Feature[geojson.Geometry]
+	err = json.Unmarshal(data, &unmarshalled)
+	require.NoError(t, err)
+	require.Equal(t, feature.ID(), unmarshalled.ID())
+	require.Equal(t, feature.Geometry(), unmarshalled.Geometry())
+
+	err = json.Unmarshal([]byte(`{"type": "Feature", "id": "a", "geometry": {"type": "Point", "coordinates": [1, 2]}}`), &unmarshalled)
+	require.NoError(t, err)
+	require.Equal(t, geojson.StringFeatureID("a"), *unmarshalled.ID())
+
+	err = json.Unmarshal([]byte(`{"type": "Feature", "geometry": {"type": "Point", "coordinates": [1, 2]}}`), &unmarshalled)
+	require.NoError(t, err)
+	require.Nil(t, unmarshalled.ID())
+
+	err = json.Unmarshal([]byte(`{"type": "Feature", "id": true, "geometry": {"type": "Point", "coordinates": [1, 2]}}`), &unmarshalled)
+	require.Error(t, err)
+}
+
+func TestFeatureWith(t *testing.T) {
+	feature := geojson.NewFeature(
+		geojson.NewPoint(45.4642035, 9.189982),
+		geojson.Property{Name: "city", Value: "Milan"},
+	).WithID(geojson.IntFeatureID(1))
+
+	a := feature.WithProperties(geojson.Property{Name: "a", Value: 1})
+	b := feature.WithProperties(geojson.Property{Name: "b", Value: 2})
+	require.Equal(t, geojson.IntFeatureID(1), *a.ID())
+	require.Equal(t, geojson.PropertyList{{Name: "city", Value: "Milan"}, {Name: "a", Value: 1}}, a.Properties())
+	require.Equal(t, geojson.PropertyList{{Name: "city", Value: "Milan"}, {Name: "b", Value: 2}}, b.Properties())
+	require.Equal(t, geojson.PropertyList{{Name: "city", Value: "Milan"}}, feature.Properties())
+}
+
+func TestFeatureForeignMembers(t *testing.T) {
+	feature := geojson.NewFeature(geojson.NewPoint(45.4642035, 9.189982)).
+		WithForeignMember("title", json.RawMessage(`"Milan"`)).
+		WithForeignMember("links", json.RawMessage(`[{"href": "https://example.com"}]`))
+
+	data, err := json.Marshal(feature)
+	require.NoError(t, err)
+	require.Equal(t,
+		`{"type":"Feature","geometry":{"type":"Point","coordinates":[9.189982,45.4642035]},`+
+			`"links":[{"href":"https://example.com"}],"title":"Milan"}`,
+		string(data))
+
+	var unmarshalled geojson.Feature[geojson.Geometry]
+	err = json.Unmarshal(data, &unmarshalled)
+	require.NoError(t, err)
+	require.Equal(t, map[string]json.RawMessage{
+		"links": json.RawMessage(`[{"href":"https://example.com"}]`),
+		"title": json.RawMessage(`"Milan"`),
+	}, unmarshalled.ForeignMembers())
+
+	err = json.Unmarshal([]byte(`{"type": "Feature", "geometry": {"type": "Point", "coordinates": [1, 2]}}`), &unmarshalled)
+	require.NoError(t, err)
+	require.Nil(t, unmarshalled.ForeignMembers())
+
+	t.Run("copy", func(t *testing.T) {
+		a := feature.WithForeignMember("title", json.RawMessage(`"Rome"`))
+		require.Equal(t, json.RawMessage(`"Rome"`), a.ForeignMembers()["title"])
+		require.Equal(t, json.RawMessage(`"Milan"`), feature.ForeignMembers()["title"])
+
+		feature.ForeignMembers()["title"] = json.RawMessage(`"Turin"`)
+		require.Equal(t, json.RawMessage(`"Milan"`), feature.ForeignMembers()["title"])
+	})
+
+	t.Run("geometry", func(t *testing.T) {
+		data := `{"type":"Feature","geometry":{"type":"Point","coordinates":[1,2],"title":"a"}}`
+		err := json.Unmarshal([]byte(data), &unmarshalled)
+		require.NoError(t, err)
+		require.Nil(t, unmarshalled.ForeignMembers())
+		require.Equal(t, map[string]json.RawMessage{
+			"title": json.RawMessage(`"a"`),
+		}, unmarshalled.Geometry().(*geojson.Point).ForeignMembers())
+
+		marshalled, err := json.Marshal(unmarshalled)
+		require.NoError(t, err)
+		require.Equal(t, data, string(marshalled))
+	})
+
+	t.Run("reserved", func(t *testing.T) {
+		_, err := json.Marshal(feature.WithForeignMember("properties", json.RawMessage(`{}`)))
+		require.Error(t, err)
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		_, err := json.Marshal(feature.WithForeignMember("title", json.RawMessage(`{`)))
+		require.Error(t, err)
+	})
+}
+
+func TestFeatureNullGeometry(t *testing.T) {
+	feature := geojson.NewFeature[geojson.Geometry](nil, geojson.Property{Name: "city", Value: "Milan"})
+	require.False(t, feature.HasGeometry())
+	require.Nil(t, feature.Geometry())
+	require.NoError(t, feature.Validate())
+
+	data, err := json.Marshal(feature)
+	require.NoError(t, err)
+	require.JSONEq(t, `
+		{
+			"type": "Feature",
+			"geometry": null,
+			"properties": {
+				"city": "Milan"
+			}
+		}`, string(data))
+
+	var unmarshalled geojson.Feature[geojson.Geometry]
+	err = json.Unmarshal(data, &unmarshalled)
+	require.NoError(t, err)
+	require.Equal(t, feature, unmarshalled)
+
+	for name, data := range map[string]string{
+		"missing":     `{"type": "Feature"}`,
+		"empty point": `{"type": "Feature", "geometry": {"type": "Point", "coordinates": []}}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			var unmarshalled geojson.Feature[*geojson.Point]
+			err := json.Unmarshal([]byte(data), &unmarshalled)
+			require.NoError(t, err)
+			require.False(t, unmarshalled.HasGeometry())
+			require.Nil(t, unmarshalled.Geometry())
+		})
+	}
+
+	t.Run("typed", func(t *testing.T) {
+		feature := geojson.NewFeature[*geojson.Point](nil)
+		require.False(t, feature.HasGeometry())
+		require.NoError(t, feature.Validate())
+
+		data, err := json.Marshal(feature)
+		require.NoError(t, err)
+		require.JSONEq(t, `{"type": "Feature", "geometry": null}`, string(data))
+	})
+
+	t.Run("wrong type", func(t *testing.T) {
+		var unmarshalled geojson.Feature[*geojson.Point]
+		err := json.Unmarshal([]byte(`{"type": "Feature", "geometry": {"type": "MultiPoint", "coordinates": []}}`), &unmarshalled)
+		require.Error(t, err)
+	})
+}
+
 func TestFeatureCollection(t *testing.T) {
 	collection := geojson.NewFeatureCollection(
 		geojson.NewFeature[geojson.Geometry](
@@ -182,3 +342,43 @@ func TestFeatureCollectionWithBoundingBox(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, collection, unmarshalled)
 }
+
+func TestFeatureCollectionForeignMembers(t *testing.T) {
+	data := []byte(`
+		{
+			"type": "FeatureCollection",
+			"timeStamp": "2020-01-01T00:00:00Z",
+			"features": [
+				{
+					"type": "Feature",
+					"geometry": {
+						"type": "Point",
+						"coordinates": [9.189982, 45.4642035]
+					},
+					"title": "Milan"
+				}
+			],
+			"links": []
+		}`)
+
+	var collection geojson.FeatureCollection
+	err := json.Unmarshal(data, &collection)
+	require.NoError(t, err)
+	require.Equal(t, map[string]json.RawMessage{
+		"links":     json.RawMessage(`[]`),
+		"timeStamp": json.RawMessage(`"2020-01-01T00:00:00Z"`),
+	}, collection.ForeignMembers())
+
+	delete(collection.ForeignMembers(), "links")
+	require.Len(t, collection.ForeignMembers(), 2)
+
+	marshalled, err := json.Marshal(collection)
+	require.NoError(t, err)
+	require.Equal(t,
+		`{"type":"FeatureCollection","features":[{"type":"Feature","geometry":{"type":"Point","coordinates":[9.189982,45.4642035]},"title":"Milan"}],`+
+			`"links":[],"timeStamp":"2020-01-01T00:00:00Z"}`,
+		string(marshalled))
+
+	_, err = json.Marshal(collection.WithForeignMember("features", json.RawMessage(`[]`)))
+	require.Error(t, err)
+}
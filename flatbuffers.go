@@ -0,0 +1,346 @@
+package geojson
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// fbTable describes a FlatBuffers table to be serialized, with fields indexed by their ID.
+// Absent fields are nil.
+type fbTable []*fbField
+
+type fbFieldKind int
+
+const (
+	fbScalarField fbFieldKind = iota
+	fbStringField
+	fbVectorField
+	fbTableField
+	fbTableVectorField
+)
+
+type fbField struct {
+	kind     fbFieldKind
+	data     []byte // Little-endian scalar value, string content or vector elements.
+	elemSize int
+	table    fbTable
+	tables   []fbTable
+}
+
+func fbUint8(v uint8) *fbField {
+	return &fbField{kind: fbScalarField, data: []byte{v}}
+}
+
+func fbBool(v bool) *fbField {
+	if v {
+		return fbUint8(1)
+	}
+	return fbUint8(0)
+}
+
+func fbUint16(v uint16) *fbField {
+	return &fbField{kind: fbScalarField, data: binary.LittleEndian.AppendUint16(nil, v)}
+}
+
+func fbInt32(v int32) *fbField {
+	return &fbField{kind: fbScalarField, data: binary.LittleEndian.AppendUint32(nil, uint32(v))}
+}
+
+func fbUint64(v uint64) *fbField {
+	return &fbField{kind: fbScalarField, data: binary.LittleEndian.AppendUint64(nil, v)}
+}
+
+func fbString(s string) *fbField {
+	return &fbField{kind: fbStringField, data: []byte(s)}
+}
+
+func fbBytes(data []byte) *fbField {
+	return &fbField{kind: fbVectorField, data: data, elemSize: 1}
+}
+
+func fbUint32s(values []uint32) *fbField {
+	data := make([]byte, 0, 4*len(values))
+	for _, v := range values {
+		data = binary.LittleEndian.AppendUint32(data, v)
+	}
+	return &fbField{kind: fbVectorField, data: data, elemSize: 4}
+}
+
+func fbFloat64s(values []float64) *fbField {
+	data := make([]byte, 0, 8*len(values))
+	for _, v := range values {
+		data = binary.LittleEndian.AppendUint64(data, math.Float64bits(v))
+	}
+	return &fbField{kind: fbVectorField, data: data, elemSize: 8}
+}
+
+func fbSubTable(t fbTable) *fbField {
+	return &fbField{kind: fbTableField, table: t}
+}
+
+func fbTables(tables []fbTable) *fbField {
+	return &fbField{kind: fbTableVectorField, tables: tables}
+}
+
+// fbSerialize returns the FlatBuffers encoding of the root table.
+// Unlike the reference builder, the buffer is written front to back, with every object
+// preceding the objects that it refers to, so that all offsets are unsigned as required.
+func fbSerialize(root fbTable) []byte {
+	b := fbBuilder{buf: make([]byte, 4)}
+	pos := b.table(root)
+	binary.LittleEndian.PutUint32(b.buf, uint32(pos))
+	return b.buf
+}
+
+type fbBuilder struct {
+	buf []byte
+}
+
+func (b *fbBuilder) table(t fbTable) int {
+	// Lay out the inline fields in decreasing order of size, following the 4 byte offset to the vtable.
+	// The table is positioned so that the first field is aligned to the largest field size,
+	// which naturally aligns every field relative to the start of the buffer, as verifiers require.
+	offsets := make([]int, len(t))
+	size := 4
+	fieldAlign := 4
+	for _, fieldSize := range []int{8, 4, 2, 1} {
+		for i, field := range t {
+			if field == nil || field.inlineSize() != fieldSize {
+				continue
+			}
+			offsets[i] = size
+			size += fieldSize
+			if fieldSize > fieldAlign {
+				fieldAlign = fieldSize
+			}
+		}
+	}
+
+	b.align(2)
+	vtable := len(b.buf)
+	b.buf = binary.LittleEndian.AppendUint16(b.buf, uint16(4+2*len(t)))
+	b.buf = binary.LittleEndian.AppendUint16(b.buf, uint16(size))
+	for _, offset := range offsets {
+		b.buf = binary.LittleEndian.AppendUint16(b.buf, uint16(offset))
+	}
+
+	for len(b.buf)%4 != 0 || (len(b.buf)+4)%fieldAlign != 0 {
+		b.buf = append(b.buf, 0)
+	}
+	table := len(b.buf)
+	b.buf = append(b.buf, make([]byte, size)...)
+	binary.LittleEndian.PutUint32(b.buf[table:], uint32(table-vtable))
+
+	for i, field := range t {
+		if field == nil {
+			continue
+		}
+
+		pos := table + offsets[i]
+		if field.kind == fbScalarField {
+			copy(b.buf[pos:], field.data)
+			continue
+		}
+
+		var target int
+		switch field.kind {
+		case fbStringField:
+			target = b.vector(field.data, 1)
+			b.buf = append(b.buf, 0)
+		case fbVectorField:
+			target = b.vector(field.data, field.elemSize)
+		case fbTableField:
+			target = b.table(field.table)
+		case fbTableVectorField:
+			target = b.tableVector(field.tables)
+		}
+		binary.LittleEndian.PutUint32(b.buf[pos:], uint32(target-pos))
+	}
+	return table
+}
+
+func (b *fbBuilder) vector(data []byte, elemSize int) int {
+	// The length precedes the elements, which must be aligned to their size.
+	for len(b.buf)%4 != 0 || (len(b.buf)+4)%elemSize != 0 {
+		b.buf = append(b.buf, 0)
+	}
+
+	pos := len(b.buf)
+	b.buf = binary.LittleEndian.AppendUint32(b.buf, uint32(len(data)/elemSize))
+	b.buf = append(b.buf, data...)
+	return pos
+}
+
+func (b *fbBuilder) tableVector(tables []fbTable) int {
+	b.align(4)
+	pos := len(b.buf)
+	b.buf = binary.LittleEndian.AppendUint32(b.buf, uint32(len(tables)))
+	b.buf = append(b.buf, make([]byte, 4*len(tables))...)
+
+	for i, t := range tables {
+		slot := pos + 4 + 4*i
+		target := b.table(t)
+		binary.LittleEndian.PutUint32(b.buf[slot:], uint32(target-slot))
+	}
+	return pos
+}
+
+func (b *fbBuilder) align(n int) {
+	for len(b.buf)%n != 0 {
+		b.buf = append(b.buf, 0)
+	}
+}
+
+func (f *fbField) inlineSize() int {
+	if f.kind == fbScalarField {
+		return len(f.data)
+	}
+	return 4
+}
+
+// fbReader reads fields from a serialized FlatBuffers table.
+// Out of range accesses panic with an fbError, which should be recovered using fbRecover.
+type fbReader struct {
+	buf []byte
+	pos int
+}
+
+type fbError struct {
+	err error
+}
+
+func fbRoot(buf []byte) fbReader {
+	r := fbReader{buf: buf}
+	return fbReader{buf: buf, pos: int(r.uint32At(0))}
+}
+
+// fbRecover converts a panic caused by malformed FlatBuffers data into an error.
+func fbRecover(err *error) {
+	if r := recover(); r != nil {
+		fbErr, ok := r.(fbError)
+		if !ok {
+			panic(r)
+		}
+		*err = fbErr.err
+	}
+}
+
+// field returns the position of the field with the specified ID, or 0 if it is absent.
+func (r fbReader) field(id int) int {
+	vtable := r.pos - int(int32(r.uint32At(r.pos)))
+	vtableSize := int(r.uint16At(vtable))
+	if 4+2*id >= vtableSize {
+		return 0
+	}
+
+	offset := int(r.uint16At(vtable + 4 + 2*id))
+	if offset == 0 {
+		return 0
+	}
+	return r.pos + offset
+}
+
+func (r fbReader) uint8(id int, def uint8) uint8 {
+	pos := r.field(id)
+	if pos == 0 {
+		return def
+	}
+	r.check(pos, 1)
+	return r.buf[pos]
+}
+
+func (r fbReader) bool(id int) bool {
+	return r.uint8(id, 0) != 0
+}
+
+func (r fbReader) uint16(id int, def uint16) uint16 {
+	pos := r.field(id)
+	if pos == 0 {
+		return def
+	}
+	return r.uint16At(pos)
+}
+
+func (r fbReader) uint64(id int, def uint64) uint64 {
+	pos := r.field(id)
+	if pos == 0 {
+		return def
+	}
+	r.check(pos, 8)
+	return binary.LittleEndian.Uint64(r.buf[pos:])
+}
+
+func (r fbReader) string(id int) string {
+	return string(r.bytes(id))
+}
+
+func (r fbReader) bytes(id int) []byte {
+	start, n := r.vector(id, 1)
+	return r.buf[start : start+n]
+}
+
+func (r fbReader) uint32s(id int) []uint32 {
+	start, n := r.vector(id, 4)
+	values := make([]uint32, n)
+	for i := range values {
+		values[i] = binary.LittleEndian.Uint32(r.buf[start+4*i:])
+	}
+	return values
+}
+
+func (r fbReader) float64s(id int) []float64 {
+	start, n := r.vector(id, 8)
+	values := make([]float64, n)
+	for i := range values {
+		values[i] = math.Float64frombits(binary.LittleEndian.Uint64(r.buf[start+8*i:]))
+	}
+	return values
+}
+
+func (r fbReader) table(id int) (fbReader, bool) {
+	pos := r.field(id)
+	if pos == 0 {
+		return fbReader{}, false
+	}
+	return fbReader{buf: r.buf, pos: pos + int(r.uint32At(pos))}, true
+}
+
+func (r fbReader) tables(id int) []fbReader {
+	start, n := r.vector(id, 4)
+	tables := make([]fbReader, n)
+	for i := range tables {
+		slot := start + 4*i
+		tables[i] = fbReader{buf: r.buf, pos: slot + int(r.uint32At(slot))}
+	}
+	return tables
+}
+
+// vector returns the position of the first element of a vector field and the number of elements.
+func (r fbReader) vector(id int, elemSize int) (int, int) {
+	pos := r.field(id)
+	if pos == 0 {
+		return 0, 0
+	}
+
+	pos += int(r.uint32At(pos))
+	n := int(r.uint32At(pos))
+	r.check(pos+4, n*elemSize)
+	return pos + 4, n
+}
+
+func (r fbReader) uint16At(pos int) uint16 {
+	r.check(pos, 2)
+	return binary.LittleEndian.Uint16(r.buf[pos:])
+}
+
+func (r fbReader) uint32At(pos int) uint32 {
+	r.check(pos, 4)
+	return binary.LittleEndian.Uint32(r.buf[pos:])
+}
+
+func (r fbReader) check(pos int, size int) {
+	if pos < 0 || size < 0 || pos > len(r.buf) || size > len(r.buf)-pos {
+		panic(fbError{fmt.Errorf("offset %d is out of range", pos)})
+	}
+}
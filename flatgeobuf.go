@@ -0,0 +1,1092 @@
+package geojson
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"sort"
+	"time"
+)
+
+// FlatGeobufColumnType is the type of the values stored in a FlatGeobuf column.
+type FlatGeobufColumnType uint8
+
+// FlatGeobuf column types, and the Go types of the values that they are read as.
+const (
+	FlatGeobufByte     FlatGeobufColumnType = iota // int8
+	FlatGeobufUByte                                // uint8
+	FlatGeobufBool                                 // bool
+	FlatGeobufShort                                // int16
+	FlatGeobufUShort                               // uint16
+	FlatGeobufInt                                  // int32
+	FlatGeobufUInt                                 // uint32
+	FlatGeobufLong                                 // int64
+	FlatGeobufULong                                // uint64
+	FlatGeobufFloat                                // float32
+	FlatGeobufDouble                               // float64
+	FlatGeobufString                               // string
+	FlatGeobufJSON                                 // Any value produced by json.Unmarshal
+	FlatGeobufDateTime                             // string, in ISO 8601 format
+	FlatGeobufBinary                               // []byte
+)
+
+// FlatGeobufColumn describes a feature property stored in a FlatGeobuf file.
+type FlatGeobufColumn struct {
+	Name string
+	Type FlatGeobufColumnType
+}
+
+// FlatGeobufOptions configures a FlatGeobufWriter.
+type FlatGeobufOptions struct {
+	// Name of the dataset.
+	Name string
+	// Columns is the schema of the feature properties.
+	// Properties with a nil value are omitted, and it is an error for a property to have no column.
+	Columns []FlatGeobufColumn
+	// IndexNodeSize is the number of children of each spatial index node, which defaults to 16.
+	IndexNodeSize uint16
+	// NoIndex disables the spatial index, so that features are written in their original order.
+	NoIndex bool
+}
+
+// FlatGeobuf geometry types.
+const (
+	flatGeobufUnknown = iota
+	flatGeobufPoint
+	flatGeobufLineString
+	flatGeobufPolygon
+	flatGeobufMultiPoint
+	flatGeobufMultiLineString
+	flatGeobufMultiPolygon
+	flatGeobufGeometryCollection
+)
+
+const (
+	flatGeobufDefaultNodeSize = 16
+	flatGeobufNodeSize        = 40
+	flatGeobufMaxHeaderSize   = 10 << 20
+)
+
+var flatGeobufMagic = []byte{'f', 'g', 'b', 3, 'f', 'g', 'b', 0}
+
+// FlatGeobufWriter writes Features to a FlatGeobuf file.
+// Features are buffered in memory until Close is called, as the spatial index precedes them in the file.
+type FlatGeobufWriter struct {
+	w          io.Writer
+	opts       FlatGeobufOptions
+	columns    map[string]int
+	items      []flatGeobufItem
+	geoType    uint8
+	hasGeoType bool
+	hasZ       bool
+	hasItems   bool
+	closed     bool
+}
+
+type flatGeobufItem struct {
+	data    []byte
+	box     flatGeobufBox
+	hilbert uint32
+	offset  uint64
+}
+
+// NewFlatGeobufWriter returns a new FlatGeobufWriter that writes to w.
+func NewFlatGeobufWriter(w io.Writer, opts FlatGeobufOptions) *FlatGeobufWriter {
+	if opts.IndexNodeSize == 0 {
+		opts.IndexNodeSize = flatGeobufDefaultNodeSize
+	}
+
+	columns := make(map[string]int, len(opts.Columns))
+	for i, column := range opts.Columns {
+		columns[column.Name] = i
+	}
+
+	return &FlatGeobufWriter{
+		w:       w,
+		opts:    opts,
+		columns: columns,
+	}
+}
+
+// Write adds the feature to the file.
+// Features without a geometry are written without one, so they are never returned by Search.
+func (w *FlatGeobufWriter) Write(feature Feature[Geometry]) error {
+	if w.closed {
+		return fmt.Errorf("writer is closed")
+	} else if !w.opts.NoIndex && w.opts.IndexNodeSize < 2 {
+		return fmt.Errorf("index node size must be at least 2")
+	}
+
+	var err error
+	box := emptyFlatGeobufBox()
+	eachPosition(feature.geometry, func(pos Position) {
+		if hasZ := pos.elevation != nil; !w.hasItems {
+			w.hasItems = true
+			w.hasZ = hasZ
+		} else if hasZ != w.hasZ {
+			err = fmt.Errorf("positions must be in the same dimension")
+		}
+		box.extend(pos.lng, pos.lat)
+	})
+	if err != nil {
+		return err
+	}
+
+	var geometry fbTable
+	if feature.HasGeometry() {
+		if geometry, err = flatGeobufGeometry(feature.geometry); err != nil {
+			return err
+		}
+	}
+
+	var properties []byte
+	for _, prop := range feature.properties {
+		if prop.Value == nil {
+			continue
+		}
+
+		i, ok := w.columns[prop.Name]
+		if !ok {
+			return fmt.Errorf("property '%s' is not in the schema", prop.Name)
+		}
+
+		properties = binary.LittleEndian.AppendUint16(properties, uint16(i))
+		if properties, err = appendFlatGeobufValue(properties, w.opts.Columns[i].Type, prop.Value); err != nil {
+			return fmt.Errorf("property '%s': %w", prop.Name, err)
+		}
+	}
+
+	table := fbTable{nil, nil}
+	if geometry != nil {
+		table[0] = fbSubTable(geometry)
+	}
+	if len(properties) != 0 {
+		table[1] = fbBytes(properties)
+	}
+	data := fbSerialize(table)
+
+	if geometry != nil {
+		if geoType := geometry[6].data[0]; !w.hasGeoType {
+			w.geoType = geoType
+			w.hasGeoType = true
+		} else if geoType != w.geoType {
+			w.geoType = flatGeobufUnknown
+		}
+	}
+
+	w.items = append(w.items, flatGeobufItem{
+		data: append(binary.LittleEndian.AppendUint32(nil, uint32(len(data))), data...),
+		box:  box,
+	})
+	return nil
+}
+
+// Close writes the header, spatial index and features. It does not close the underlying writer.
+func (w *FlatGeobufWriter) Close() error {
+	if w.closed {
+		return fmt.Errorf("writer is closed")
+	}
+	w.closed = true
+
+	extent := emptyFlatGeobufBox()
+	for _, item := range w.items {
+		extent.union(item.box)
+	}
+
+	index := !w.opts.NoIndex && len(w.items) != 0
+	if index {
+		for i := range w.items {
+			w.items[i].hilbert = extent.hilbert(w.items[i].box)
+		}
+		sort.SliceStable(w.items, func(i, j int) bool {
+			return w.items[i].hilbert > w.items[j].hilbert
+		})
+	}
+
+	var offset uint64
+	for i := range w.items {
+		w.items[i].offset = offset
+		offset += uint64(len(w.items[i].data))
+	}
+
+	if _, err := w.w.Write(flatGeobufMagic); err != nil {
+		return err
+	}
+
+	header := fbSerialize(w.header(extent, index))
+	if _, err := w.w.Write(binary.LittleEndian.AppendUint32(nil, uint32(len(header)))); err != nil {
+		return err
+	} else if _, err := w.w.Write(header); err != nil {
+		return err
+	}
+
+	if index {
+		if _, err := w.w.Write(w.index()); err != nil {
+			return err
+		}
+	}
+
+	for _, item := range w.items {
+		if _, err := w.w.Write(item.data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *FlatGeobufWriter) header(extent flatGeobufBox, index bool) fbTable {
+	header := make(fbTable, 11)
+	if w.opts.Name != "" {
+		header[0] = fbString(w.opts.Name)
+	}
+	if len(w.items) != 0 && !extent.empty() {
+		header[1] = fbFloat64s(extent[:])
+	}
+	header[2] = fbUint8(w.geoType)
+	header[3] = fbBool(w.hasZ)
+
+	if len(w.opts.Columns) != 0 {
+		columns := make([]fbTable, len(w.opts.Columns))
+		for i, column := range w.opts.Columns {
+			columns[i] = fbTable{fbString(column.Name), fbUint8(uint8(column.Type))}
+		}
+		header[7] = fbTables(columns)
+	}
+
+	header[8] = fbUint64(uint64(len(w.items)))
+	if index {
+		header[9] = fbUint16(w.opts.IndexNodeSize)
+	} else {
+		header[9] = fbUint16(0)
+	}
+
+	// Coordinates are always WGS 84.
+	header[10] = fbSubTable(fbTable{fbString("EPSG"), fbInt32(4326)})
+	return header
+}
+
+// index returns the packed Hilbert R-tree of the sorted items.
+func (w *FlatGeobufWriter) index() []byte {
+	bounds := flatGeobufLevelBounds(len(w.items), int(w.opts.IndexNodeSize))
+	nodes := make([]flatGeobufNode, bounds[0][1])
+
+	for i, item := range w.items {
+		nodes[bounds[0][0]+i] = flatGeobufNode{box: item.box, offset: item.offset}
+	}
+
+	for level := 0; level < len(bounds)-1; level++ {
+		parent := bounds[level+1][0]
+		for child := bounds[level][0]; child < bounds[level][1]; parent++ {
+			node := flatGeobufNode{box: emptyFlatGeobufBox(), offset: uint64(child)}
+			for end := child + int(w.opts.IndexNodeSize); child < end && child < bounds[level][1]; child++ {
+				node.box.union(nodes[child].box)
+			}
+			nodes[parent] = node
+		}
+	}
+
+	data := make([]byte, 0, len(nodes)*flatGeobufNodeSize)
+	for _, node := range nodes {
+		for _, v := range node.box {
+			data = binary.LittleEndian.AppendUint64(data, math.Float64bits(v))
+		}
+		data = binary.LittleEndian.AppendUint64(data, node.offset)
+	}
+	return data
+}
+
+func flatGeobufGeometry(geo Geometry) (fbTable, error) {
+	table := make(fbTable, 8)
+	setCoords := func(lines ...[]Position) {
+		var ends []uint32
+		var xy, z []float64
+		for _, line := range lines {
+			for _, pos := range line {
+				xy = append(xy, pos.lng, pos.lat)
+				if pos.elevation != nil {
+					z = append(z, *pos.elevation)
+				}
+			}
+			ends = append(ends, uint32(len(xy)/2))
+		}
+
+		if len(lines) > 1 {
+			table[0] = fbUint32s(ends)
+		}
+		if len(xy) != 0 {
+			table[1] = fbFloat64s(xy)
+		}
+		if len(z) != 0 {
+			table[2] = fbFloat64s(z)
+		}
+	}
+
+	var geoType uint8
+	switch g := geo.(type) {
+	case *Point:
+		geoType = flatGeobufPoint
+		setCoords([]Position{g.pos})
+	case *MultiPoint:
+		geoType = flatGeobufMultiPoint
+		setCoords(g.positions)
+	case *LineString:
+		geoType = flatGeobufLineString
+		setCoords(g.positions)
+	case *MultiLineString:
+		geoType = flatGeobufMultiLineString
+		setCoords(g.lines...)
+	case *Polygon:
+		geoType = flatGeobufPolygon
+		setCoords(g.rings...)
+	case *MultiPolygon:
+		geoType = flatGeobufMultiPolygon
+		parts := make([]fbTable, len(g.polygons))
+		for i, polygon := range g.polygons {
+			parts[i], _ = flatGeobufGeometry(NewPolygon(polygon...))
+		}
+		table[7] = fbTables(parts)
+	case *GeometryCollection:
+		geoType = flatGeobufGeometryCollection
+		parts := make([]fbTable, len(g.geometries))
+		for i, geo := range g.geometries {
+			part, err := flatGeobufGeometry(geo)
+			if err != nil {
+				return nil, err
+			}
+			parts[i] = part
+		}
+		table[7] = fbTables(parts)
+	default:
+		return nil, fmt.Errorf("unsupported geometry type '%T'", geo)
+	}
+
+	table[6] = fbUint8(geoType)
+	return table, nil
+}
+
+func appendFlatGeobufValue(b []byte, t FlatGeobufColumnType, value interface{}) ([]byte, error) {
+	var err error
+	var i int64
+	var u uint64
+	var f float64
+
+	switch t {
+	case FlatGeobufByte:
+		if i, err = flatGeobufInt(value, 8); err == nil {
+			b = append(b, byte(i))
+		}
+	case FlatGeobufUByte:
+		if u, err = flatGeobufUint(value, 8); err == nil {
+			b = append(b, byte(u))
+		}
+	case FlatGeobufBool:
+		v, ok := value.(bool)
+		if !ok {
+			return nil, fmt.Errorf("cannot store %T as bool", value)
+		} else if v {
+			b = append(b, 1)
+		} else {
+			b = append(b, 0)
+		}
+	case FlatGeobufShort:
+		if i, err = flatGeobufInt(value, 16); err == nil {
+			b = binary.LittleEndian.AppendUint16(b, uint16(i))
+		}
+	case FlatGeobufUShort:
+		if u, err = flatGeobufUint(value, 16); err == nil {
+			b = binary.LittleEndian.AppendUint16(b, uint16(u))
+		}
+	case FlatGeobufInt:
+		if i, err = flatGeobufInt(value, 32); err == nil {
+			b = binary.LittleEndian.AppendUint32(b, uint32(i))
+		}
+	case FlatGeobufUInt:
+		if u, err = flatGeobufUint(value, 32); err == nil {
+			b = binary.LittleEndian.AppendUint32(b, uint32(u))
+		}
+	case FlatGeobufLong:
+		if i, err = flatGeobufInt(value, 64); err == nil {
+			b = binary.LittleEndian.AppendUint64(b, uint64(i))
+		}
+	case FlatGeobufULong:
+		if u, err = flatGeobufUint(value, 64); err == nil {
+			b = binary.LittleEndian.AppendUint64(b, u)
+		}
+	case FlatGeobufFloat:
+		if f, err = flatGeobufFloat(value); err == nil {
+			b = binary.LittleEndian.AppendUint32(b, math.Float32bits(float32(f)))
+		}
+	case FlatGeobufDouble:
+		if f, err = flatGeobufFloat(value); err == nil {
+			b = binary.LittleEndian.AppendUint64(b, math.Float64bits(f))
+		}
+	case FlatGeobufString:
+		v, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("cannot store %T as string", value)
+		}
+		b = appendFlatGeobufBytes(b, []byte(v))
+	case FlatGeobufJSON:
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, err
+		}
+		b = appendFlatGeobufBytes(b, data)
+	case FlatGeobufDateTime:
+		switch v := value.(type) {
+		case time.Time:
+			b = appendFlatGeobufBytes(b, []byte(v.Format(time.RFC3339Nano)))
+		case string:
+			b = appendFlatGeobufBytes(b, []byte(v))
+		default:
+			return nil, fmt.Errorf("cannot store %T as date-time", value)
+		}
+	case FlatGeobufBinary:
+		v, ok := value.([]byte)
+		if !ok {
+			return nil, fmt.Errorf("cannot store %T as binary", value)
+		}
+		b = appendFlatGeobufBytes(b, v)
+	default:
+		return nil, fmt.Errorf("unsupported column type %d", t)
+	}
+	return b, err
+}
+
+func appendFlatGeobufBytes(b []byte, data []byte) []byte {
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(data)))
+	return append(b, data...)
+}
+
+// flatGeobufInt converts a numeric value to a signed integer with the specified number of bits.
+// Floating point values are accepted if they are integral, as these are produced by json.Unmarshal.
+func flatGeobufInt(value interface{}, bits int) (int64, error) {
+	var i int64
+	switch v := reflect.ValueOf(value); v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		i = v.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if v.Uint() > math.MaxInt64 {
+			return 0, fmt.Errorf("value %v is out of range", value)
+		}
+		i = int64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		if f := v.Float(); f != math.Trunc(f) || f < math.MinInt64 || f >= math.MaxInt64 {
+			return 0, fmt.Errorf("value %v is not an integer", value)
+		}
+		i = int64(v.Float())
+	default:
+		return 0, fmt.Errorf("cannot store %T as an integer", value)
+	}
+
+	if shift := 64 - bits; i<<shift>>shift != i {
+		return 0, fmt.Errorf("value %v is out of range", value)
+	}
+	return i, nil
+}
+
+// flatGeobufUint converts a numeric value to an unsigned integer with the specified number of bits.
+func flatGeobufUint(value interface{}, bits int) (uint64, error) {
+	var u uint64
+	switch v := reflect.ValueOf(value); v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.Int() < 0 {
+			return 0, fmt.Errorf("value %v is out of range", value)
+		}
+		u = uint64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		u = v.Uint()
+	case reflect.Float32, reflect.Float64:
+		if f := v.Float(); f != math.Trunc(f) || f < 0 || f >= math.MaxUint64 {
+			return 0, fmt.Errorf("value %v is not an unsigned integer", value)
+		}
+		u = uint64(v.Float())
+	default:
+		return 0, fmt.Errorf("cannot store %T as an integer", value)
+	}
+
+	if bits < 64 && u>>bits != 0 {
+		return 0, fmt.Errorf("value %v is out of range", value)
+	}
+	return u, nil
+}
+
+func flatGeobufFloat(value interface{}) (float64, error) {
+	switch v := reflect.ValueOf(value); v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int()), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return float64(v.Uint()), nil
+	case reflect.Float32, reflect.Float64:
+		return v.Float(), nil
+	default:
+		return 0, fmt.Errorf("cannot store %T as a number", value)
+	}
+}
+
+// FlatGeobufReader reads Features from a FlatGeobuf file.
+type FlatGeobufReader struct {
+	r             io.Reader
+	name          string
+	columns       []FlatGeobufColumn
+	count         uint64
+	box           *BoundingBox
+	geoType       uint8
+	nodeSize      int
+	indexStart    int64
+	indexSkipped  bool
+	featuresStart int64
+}
+
+// NewFlatGeobufReader returns a new FlatGeobufReader that reads from r, after reading the file header.
+// If r is an io.ReadSeeker, the spatial index can be queried using Search.
+func NewFlatGeobufReader(r io.Reader) (*FlatGeobufReader, error) {
+	magic := make([]byte, len(flatGeobufMagic))
+	if _, err := io.ReadFull(r, magic); err != nil {
+		return nil, err
+	} else if string(magic[:3]) != "fgb" || string(magic[4:7]) != "fgb" {
+		return nil, fmt.Errorf("not a FlatGeobuf file")
+	} else if magic[3] != flatGeobufMagic[3] {
+		return nil, fmt.Errorf("unsupported FlatGeobuf version %d", magic[3])
+	}
+
+	var size [4]byte
+	if _, err := io.ReadFull(r, size[:]); err != nil {
+		return nil, err
+	}
+
+	n := binary.LittleEndian.Uint32(size[:])
+	if n > flatGeobufMaxHeaderSize {
+		return nil, fmt.Errorf("header size %d is too large", n)
+	}
+
+	header := make([]byte, n)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+
+	fr := FlatGeobufReader{r: r}
+	if err := fr.readHeader(header); err != nil {
+		return nil, err
+	}
+
+	if s, ok := r.(io.Seeker); ok {
+		pos, err := s.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return nil, err
+		}
+		fr.indexStart = pos
+		fr.featuresStart = pos + fr.indexSize()
+	}
+	return &fr, nil
+}
+
+func (r *FlatGeobufReader) readHeader(data []byte) (err error) {
+	defer fbRecover(&err)
+
+	header := fbRoot(data)
+	r.name = header.string(0)
+	r.geoType = header.uint8(2, flatGeobufUnknown)
+	r.count = header.uint64(8, 0)
+	r.nodeSize = int(header.uint16(9, flatGeobufDefaultNodeSize))
+
+	if envelope := header.float64s(1); len(envelope) >= 4 {
+		r.box = &BoundingBox{
+			BottomLeft: MakePosition(envelope[1], envelope[0]),
+			TopRight:   MakePosition(envelope[3], envelope[2]),
+		}
+	}
+
+	for _, column := range header.tables(7) {
+		r.columns = append(r.columns, FlatGeobufColumn{
+			Name: column.string(0),
+			Type: FlatGeobufColumnType(column.uint8(1, 0)),
+		})
+	}
+
+	if r.nodeSize == 1 {
+		return fmt.Errorf("invalid index node size %d", r.nodeSize)
+	}
+	return nil
+}
+
+// Name returns the name of the dataset.
+func (r *FlatGeobufReader) Name() string {
+	return r.name
+}
+
+// Columns returns the schema of the feature properties.
+func (r *FlatGeobufReader) Columns() []FlatGeobufColumn {
+	return r.columns
+}
+
+// Count returns the number of features in the file, which is 0 if unknown.
+func (r *FlatGeobufReader) Count() uint64 {
+	return r.count
+}
+
+// BoundingBox returns the extent of the features, or nil if unknown.
+func (r *FlatGeobufReader) BoundingBox() *BoundingBox {
+	return r.box
+}
+
+// Read returns the next feature in the file, or io.EOF if there are none left.
+// Features are returned in the order in which they are stored, which is not necessarily the order in which they were written.
+func (r *FlatGeobufReader) Read() (Feature[Geometry], error) {
+	if !r.indexSkipped {
+		if _, err := io.CopyN(io.Discard, r.r, r.indexSize()); err != nil {
+			return Feature[Geometry]{}, fmt.Errorf("failed to skip index: %w", err)
+		}
+		r.indexSkipped = true
+	}
+
+	var size [4]byte
+	if _, err := io.ReadFull(r.r, size[:]); err == io.EOF {
+		return Feature[Geometry]{}, io.EOF
+	} else if err != nil {
+		return Feature[Geometry]{}, err
+	}
+	return r.readFeature(binary.LittleEndian.Uint32(size[:]))
+}
+
+// Search returns the features whose bounding boxes intersect the supplied box, using the spatial index.
+// The reader must have been created from an io.ReadSeeker, and Search shouldn't be mixed with calls to Read.
+func (r *FlatGeobufReader) Search(box BoundingBox) ([]Feature[Geometry], error) {
+	s, ok := r.r.(io.ReadSeeker)
+	if !ok {
+		return nil, fmt.Errorf("search requires an io.ReadSeeker")
+	} else if r.nodeSize == 0 {
+		return nil, fmt.Errorf("file has no spatial index")
+	} else if r.count == 0 {
+		return nil, nil
+	}
+
+	query := flatGeobufBox{
+		box.BottomLeft.lng,
+		box.BottomLeft.lat,
+		box.TopRight.lng,
+		box.TopRight.lat,
+	}
+
+	offsets, err := r.search(s, query)
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] < offsets[j] })
+
+	features := make([]Feature[Geometry], len(offsets))
+	for i, offset := range offsets {
+		if _, err := s.Seek(r.featuresStart+int64(offset), io.SeekStart); err != nil {
+			return nil, err
+		}
+
+		var size [4]byte
+		if _, err := io.ReadFull(s, size[:]); err != nil {
+			return nil, err
+		}
+
+		if features[i], err = r.readFeature(binary.LittleEndian.Uint32(size[:])); err != nil {
+			return nil, err
+		}
+	}
+	return features, nil
+}
+
+// search returns the feature offsets of the matching leaf nodes, reading only the index nodes that it needs.
+func (r *FlatGeobufReader) search(s io.ReadSeeker, query flatGeobufBox) ([]uint64, error) {
+	bounds := flatGeobufLevelBounds(int(r.count), r.nodeSize)
+	leaves := bounds[0][0]
+
+	type entry struct {
+		node  int
+		level int
+	}
+	queue := []entry{{0, len(bounds) - 1}}
+
+	var offsets []uint64
+	for len(queue) != 0 {
+		next := queue[len(queue)-1]
+		queue = queue[:len(queue)-1]
+
+		end := next.node + r.nodeSize
+		if levelEnd := bounds[next.level][1]; end > levelEnd {
+			end = levelEnd
+		}
+
+		if _, err := s.Seek(r.indexStart+int64(next.node*flatGeobufNodeSize), io.SeekStart); err != nil {
+			return nil, err
+		}
+
+		data := make([]byte, (end-next.node)*flatGeobufNodeSize)
+		if _, err := io.ReadFull(s, data); err != nil {
+			return nil, fmt.Errorf("failed to read index: %w", err)
+		}
+
+		for i := 0; i < end-next.node; i++ {
+			node := readFlatGeobufNode(data[i*flatGeobufNodeSize:])
+			if !node.box.intersects(query) {
+				continue
+			}
+
+			if next.node >= leaves {
+				offsets = append(offsets, node.offset)
+			} else if child := node.offset; child < uint64(bounds[0][1]) && next.level > 0 {
+				queue = append(queue, entry{int(child), next.level - 1})
+			} else {
+				return nil, fmt.Errorf("invalid index node offset %d", child)
+			}
+		}
+	}
+	return offsets, nil
+}
+
+func (r *FlatGeobufReader) indexSize() int64 {
+	if r.nodeSize == 0 || r.count == 0 {
+		return 0
+	}
+
+	bounds := flatGeobufLevelBounds(int(r.count), r.nodeSize)
+	return int64(bounds[0][1]) * flatGeobufNodeSize
+}
+
+func (r *FlatGeobufReader) readFeature(size uint32) (Feature[Geometry], error) {
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r.r, data); err != nil {
+		return Feature[Geometry]{}, fmt.Errorf("failed to read feature: %w", err)
+	}
+	return r.decodeFeature(data)
+}
+
+func (r *FlatGeobufReader) decodeFeature(data []byte) (feature Feature[Geometry], err error) {
+	defer fbRecover(&err)
+
+	table := fbRoot(data)
+	var geo Geometry
+	if geometry, ok := table.table(0); ok {
+		if geo, err = readFlatGeobufGeometry(geometry, r.geoType); err != nil {
+			return Feature[Geometry]{}, err
+		}
+	}
+
+	props, err := r.decodeProperties(table.bytes(1))
+	if err != nil {
+		return Feature[Geometry]{}, err
+	}
+	return NewFeature(geo, props...), nil
+}
+
+func (r *FlatGeobufReader) decodeProperties(data []byte) ([]Property, error) {
+	var props []Property
+	for len(data) != 0 {
+		if len(data) < 2 {
+			return nil, fmt.Errorf("unexpected end of properties")
+		}
+
+		i := int(binary.LittleEndian.Uint16(data))
+		if i >= len(r.columns) {
+			return nil, fmt.Errorf("invalid column index %d", i)
+		}
+
+		value, n, err := readFlatGeobufValue(data[2:], r.columns[i].Type)
+		if err != nil {
+			return nil, fmt.Errorf("property '%s': %w", r.columns[i].Name, err)
+		}
+
+		props = append(props, Property{Name: r.columns[i].Name, Value: value})
+		data = data[2+n:]
+	}
+	return props, nil
+}
+
+// readFlatGeobufValue returns the value at the start of data and its encoded length.
+func readFlatGeobufValue(data []byte, t FlatGeobufColumnType) (interface{}, int, error) {
+	sizes := map[FlatGeobufColumnType]int{
+		FlatGeobufByte:   1,
+		FlatGeobufUByte:  1,
+		FlatGeobufBool:   1,
+		FlatGeobufShort:  2,
+		FlatGeobufUShort: 2,
+		FlatGeobufInt:    4,
+		FlatGeobufUInt:   4,
+		FlatGeobufLong:   8,
+		FlatGeobufULong:  8,
+		FlatGeobufFloat:  4,
+		FlatGeobufDouble: 8,
+	}
+
+	size, ok := sizes[t]
+	if !ok {
+		if len(data) < 4 {
+			return nil, 0, fmt.Errorf("unexpected end of properties")
+		}
+
+		n := binary.LittleEndian.Uint32(data)
+		if uint64(n) > uint64(len(data)-4) {
+			return nil, 0, fmt.Errorf("unexpected end of properties")
+		}
+		size = 4 + int(n)
+	} else if len(data) < size {
+		return nil, 0, fmt.Errorf("unexpected end of properties")
+	}
+
+	var value interface{}
+	switch t {
+	case FlatGeobufByte:
+		value = int8(data[0])
+	case FlatGeobufUByte:
+		value = data[0]
+	case FlatGeobufBool:
+		value = data[0] != 0
+	case FlatGeobufShort:
+		value = int16(binary.LittleEndian.Uint16(data))
+	case FlatGeobufUShort:
+		value = binary.LittleEndian.Uint16(data)
+	case FlatGeobufInt:
+		value = int32(binary.LittleEndian.Uint32(data))
+	case FlatGeobufUInt:
+		value = binary.LittleEndian.Uint32(data)
+	case FlatGeobufLong:
+		value = int64(binary.LittleEndian.Uint64(data))
+	case FlatGeobufULong:
+		value = binary.LittleEndian.Uint64(data)
+	case FlatGeobufFloat:
+		value = math.Float32frombits(binary.LittleEndian.Uint32(data))
+	case FlatGeobufDouble:
+		value = math.Float64frombits(binary.LittleEndian.Uint64(data))
+	case FlatGeobufString, FlatGeobufDateTime:
+		value = string(data[4:size])
+	case FlatGeobufJSON:
+		if err := json.Unmarshal(data[4:size], &value); err != nil {
+			return nil, 0, err
+		}
+	case FlatGeobufBinary:
+		value = append([]byte{}, data[4:size]...)
+	default:
+		return nil, 0, fmt.Errorf("unsupported column type %d", t)
+	}
+	return value, size, nil
+}
+
+func readFlatGeobufGeometry(table fbReader, geoType uint8) (Geometry, error) {
+	if t := table.uint8(6, flatGeobufUnknown); t != flatGeobufUnknown {
+		geoType = t
+	}
+
+	switch geoType {
+	case flatGeobufMultiPolygon:
+		parts := table.tables(7)
+		polygons := make([][][]Position, len(parts))
+		for i, part := range parts {
+			lines, err := readFlatGeobufLines(part)
+			if err != nil {
+				return nil, err
+			}
+			polygons[i] = lines
+		}
+		return NewMultiPolygon(polygons...), nil
+	case flatGeobufGeometryCollection:
+		parts := table.tables(7)
+		geometries := make([]Geometry, len(parts))
+		for i, part := range parts {
+			geo, err := readFlatGeobufGeometry(part, flatGeobufUnknown)
+			if err != nil {
+				return nil, err
+			}
+			geometries[i] = geo
+		}
+		return NewGeometryCollection(geometries...), nil
+	}
+
+	lines, err := readFlatGeobufLines(table)
+	if err != nil {
+		return nil, err
+	}
+
+	switch geoType {
+	case flatGeobufPoint:
+		if len(lines) != 1 || len(lines[0]) != 1 {
+			return nil, fmt.Errorf("point must have exactly 1 position")
+		}
+		return NewPointFromPosition(lines[0][0]), nil
+	case flatGeobufMultiPoint:
+		return NewMultiPoint(lines[0]...), nil
+	case flatGeobufLineString:
+		return &LineString{positions: lines[0]}, nil
+	case flatGeobufMultiLineString:
+		return NewMultiLineString(lines...), nil
+	case flatGeobufPolygon:
+		return NewPolygon(lines...), nil
+	default:
+		return nil, fmt.Errorf("unsupported geometry type %d", geoType)
+	}
+}
+
+// readFlatGeobufLines returns the positions of the geometry, split into parts by the ends field.
+func readFlatGeobufLines(table fbReader) ([][]Position, error) {
+	xy := table.float64s(1)
+	z := table.float64s(2)
+	if len(xy)%2 != 0 {
+		return nil, fmt.Errorf("odd number of coordinates")
+	} else if len(z) != 0 && len(z) != len(xy)/2 {
+		return nil, fmt.Errorf("number of z coordinates doesn't match number of positions")
+	}
+
+	ends := table.uint32s(0)
+	if len(ends) == 0 {
+		ends = []uint32{uint32(len(xy) / 2)}
+	}
+
+	lines := make([][]Position, len(ends))
+	var start uint32
+	for i, end := range ends {
+		if end < start || int(end) > len(xy)/2 {
+			return nil, fmt.Errorf("invalid part end %d", end)
+		}
+
+		line := make([]Position, 0, end-start)
+		for j := start; j < end; j++ {
+			if len(z) != 0 {
+				line = append(line, MakePositionWithElevation(xy[2*j+1], xy[2*j], z[j]))
+			} else {
+				line = append(line, MakePosition(xy[2*j+1], xy[2*j]))
+			}
+		}
+		lines[i] = line
+		start = end
+	}
+	return lines, nil
+}
+
+// flatGeobufBox is a bounding box in the form minX, minY, maxX, maxY.
+type flatGeobufBox [4]float64
+
+func emptyFlatGeobufBox() flatGeobufBox {
+	return flatGeobufBox{math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)}
+}
+
+func (b *flatGeobufBox) extend(x, y float64) {
+	b[0] = math.Min(b[0], x)
+	b[1] = math.Min(b[1], y)
+	b[2] = math.Max(b[2], x)
+	b[3] = math.Max(b[3], y)
+}
+
+func (b *flatGeobufBox) union(other flatGeobufBox) {
+	b[0] = math.Min(b[0], other[0])
+	b[1] = math.Min(b[1], other[1])
+	b[2] = math.Max(b[2], other[2])
+	b[3] = math.Max(b[3], other[3])
+}
+
+func (b flatGeobufBox) empty() bool {
+	return b[0] > b[2] || b[1] > b[3]
+}
+
+func (b flatGeobufBox) intersects(other flatGeobufBox) bool {
+	return b[0] <= other[2] && b[1] <= other[3] && b[2] >= other[0] && b[3] >= other[1]
+}
+
+// hilbert returns the position along a Hilbert curve of the centre of the item, which must be within the extent.
+func (b flatGeobufBox) hilbert(item flatGeobufBox) uint32 {
+	if item.empty() {
+		return 0
+	}
+
+	const max = 1<<16 - 1
+	var x, y uint32
+	if width := b[2] - b[0]; width != 0 {
+		x = uint32(math.Floor(max * ((item[0]+item[2])/2 - b[0]) / width))
+	}
+	if height := b[3] - b[1]; height != 0 {
+		y = uint32(math.Floor(max * ((item[1]+item[3])/2 - b[1]) / height))
+	}
+	return hilbert(x, y)
+}
+
+// hilbert returns the Hilbert curve index of the 16 bit coordinates, using the algorithm from flatbush.
+func hilbert(x, y uint32) uint32 {
+	a := x ^ y
+	b := 0xFFFF ^ a
+	c := 0xFFFF ^ (x | y)
+	d := x & (y ^ 0xFFFF)
+
+	A := a | (b >> 1)
+	B := (a >> 1) ^ a
+	C := ((c >> 1) ^ (b & (d >> 1))) ^ c
+	D := ((a & (c >> 1)) ^ (d >> 1)) ^ d
+
+	a, b, c, d = A, B, C, D
+	A = (a & (a >> 2)) ^ (b & (b >> 2))
+	B = (a & (b >> 2)) ^ (b & ((a ^ b) >> 2))
+	C ^= (a & (c >> 2)) ^ (b & (d >> 2))
+	D ^= (b & (c >> 2)) ^ ((a ^ b) & (d >> 2))
+
+	a, b, c, d = A, B, C, D
+	A = (a & (a >> 4)) ^ (b & (b >> 4))
+	B = (a & (b >> 4)) ^ (b & ((a ^ b) >> 4))
+	C ^= (a & (c >> 4)) ^ (b & (d >> 4))
+	D ^= (b & (c >> 4)) ^ ((a ^ b) & (d >> 4))
+
+	a, b, c, d = A, B, C, D
+	C ^= (a & (c >> 8)) ^ (b & (d >> 8))
+	D ^= (b & (c >> 8)) ^ ((a ^ b) & (d >> 8))
+
+	a = C ^ (C >> 1)
+	b = D ^ (D >> 1)
+
+	i0 := x ^ y
+	i1 := b | (0xFFFF ^ (i0 | a))
+
+	i0 = (i0 | (i0 << 8)) & 0x00FF00FF
+	i0 = (i0 | (i0 << 4)) & 0x0F0F0F0F
+	i0 = (i0 | (i0 << 2)) & 0x33333333
+	i0 = (i0 | (i0 << 1)) & 0x55555555
+
+	i1 = (i1 | (i1 << 8)) & 0x00FF00FF
+	i1 = (i1 | (i1 << 4)) & 0x0F0F0F0F
+	i1 = (i1 | (i1 << 2)) & 0x33333333
+	i1 = (i1 | (i1 << 1)) & 0x55555555
+
+	return (i1 << 1) | i0
+}
+
+type flatGeobufNode struct {
+	box    flatGeobufBox
+	offset uint64
+}
+
+func readFlatGeobufNode(data []byte) flatGeobufNode {
+	var node flatGeobufNode
+	for i := range node.box {
+		node.box[i] = math.Float64frombits(binary.LittleEndian.Uint64(data[8*i:]))
+	}
+	node.offset = binary.LittleEndian.Uint64(data[32:])
+	return node
+}
+
+// flatGeobufLevelBounds returns the range of node indexes at each level of the packed R-tree, starting with the leaves.
+// The root node is stored first, so that the leaves are at the end of the index.
+func flatGeobufLevelBounds(numItems int, nodeSize int) [][2]int {
+	n := numItems
+	numNodes := n
+	levelNumNodes := []int{n}
+	for {
+		n = (n + nodeSize - 1) / nodeSize
+		numNodes += n
+		levelNumNodes = append(levelNumNodes, n)
+		if n == 1 {
+			break
+		}
+	}
+
+	bounds := make([][2]int, len(levelNumNodes))
+	n = numNodes
+	for i, size := range levelNumNodes {
+		bounds[i] = [2]int{n - size, n}
+		n -= size
+	}
+	return bounds
+}
@@ -0,0 +1,473 @@
+package geojson_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"testing"
+
+	geojson "github.com/everystreet/go-geojson/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlatGeobuf(t *testing.T) {
+	columns := []geojson.FlatGeobufColumn{
+		{Name: "name", Type: geojson.FlatGeobufString},
+		{Name: "population", Type: geojson.FlatGeobufLong},
+		{Name: "area", Type: geojson.FlatGeobufDouble},
+		{Name: "capital", Type: geojson.FlatGeobufBool},
+		{Name: "tags", Type: geojson.FlatGeobufJSON},
+	}
+
+	features := []geojson.Feature[geojson.Geometry]{
+		geojson.NewFeature[geojson.Geometry](
+			geojson.NewPoint(45.4642035, 9.189982),
+			geojson.Property{Name: "name", Value: "Milan"},
+			geojson.Property{Name: "population", Value: int64(1352000)},
+			geojson.Property{Name: "area", Value: 181.67},
+			geojson.Property{Name: "capital", Value: false},
+			geojson.Property{Name: "tags", Value: []interface{}{"fashion", "finance"}},
+		),
+		geojson.NewFeature[geojson.Geometry](
+			geojson.NewMultiPoint(
+				geojson.MakePosition(12, 34),
+				geojson.MakePosition(56, 78),
+			),
+		),
+		geojson.NewFeature[geojson.Geometry](
+			geojson.NewLineString(
+				geojson.MakePosition(12, 34),
+				geojson.MakePosition(56, 78),
+			),
+			geojson.Property{Name: "name", Value: "line"},
+		),
+		geojson.NewFeature[geojson.Geometry](
+			nil,
+			geojson.Property{Name: "name", Value: "unlocated"},
+		),
+		geojson.NewFeature[geojson.Geometry](
+			geojson.NewMultiLineString(
+				[]geojson.Position{
+					geojson.MakePosition(12, 34),
+					geojson.MakePosition(56, 78),
+				},
+				[]geojson.Position{
+					geojson.MakePosition(23, 45),
+					geojson.MakePosition(67, 89),
+					geojson.MakePosition(1, 2),
+				},
+			),
+		),
+		geojson.NewFeature[geojson.Geometry](
+			geojson.NewPolygon(
+				[]geojson.Position{
+					geojson.MakePosition(7, 7),
+					geojson.MakePosition(4, 8),
+					geojson.MakePosition(3, 4),
+					geojson.MakePosition(5, 2),
+					geojson.MakePosition(7, 3),
+					geojson.MakePosition(7, 7),
+				},
+				[]geojson.Position{
+					geojson.MakePosition(4, 4),
+					geojson.MakePosition(4, 6),
+					geojson.MakePosition(5, 7),
+					geojson.MakePosition(6, 4),
+					geojson.MakePosition(4, 4),
+				},
+			),
+		),
+		geojson.NewFeature[geojson.Geometry](
+			geojson.NewMultiPolygon(
+				[][]geojson.Position{
+					{
+						geojson.MakePosition(7, 7),
+						geojson.MakePosition(4, 8),
+						geojson.MakePosition(3, 4),
+						geojson.MakePosition(7, 7),
+					},
+				},
+				[][]geojson.Position{
+					{
+						geojson.MakePosition(1, 1),
+						geojson.MakePosition(1, 2),
+						geojson.MakePosition(2, 2),
+						geojson.MakePosition(1, 1),
+					},
+				},
+			),
+		),
+		geojson.NewFeature[geojson.Geometry](
+			geojson.NewGeometryCollection(
+				geojson.NewPoint(1, 2),
+				geojson.NewLineString(
+					geojson.MakePosition(3, 4),
+					geojson.MakePosition(5, 6),
+				),
+			),
+		),
+	}
+
+	for name, opts := range map[string]geojson.FlatGeobufOptions{
+		"indexed":     {Name: "test", Columns: columns},
+		"not indexed": {Name: "test", Columns: columns, NoIndex: true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w := geojson.NewFlatGeobufWriter(&buf, opts)
+			for _, feature := range features {
+				require.NoError(t, w.Write(feature))
+			}
+			require.NoError(t, w.Close())
+
+			r, err := geojson.NewFlatGeobufReader(&buf)
+			require.NoError(t, err)
+			require.Equal(t, "test", r.Name())
+			require.Equal(t, columns, r.Columns())
+			require.Equal(t, uint64(len(features)), r.Count())
+			require.Equal(t, &geojson.BoundingBox{
+				BottomLeft: geojson.MakePosition(1, 1),
+				TopRight:   geojson.MakePosition(67, 89),
+			}, r.BoundingBox())
+
+			var read []geojson.Feature[geojson.Geometry]
+			for {
+				feature, err := r.Read()
+				if err == io.EOF {
+					break
+				}
+				require.NoError(t, err)
+				read = append(read, feature)
+			}
+
+			if opts.NoIndex {
+				require.Equal(t, features, read)
+			} else {
+				require.ElementsMatch(t, features, read)
+			}
+		})
+	}
+}
+
+func TestFlatGeobufSearch(t *testing.T) {
+	var features []geojson.Feature[geojson.Geometry]
+	for lat := -80; lat <= 80; lat += 10 {
+		for lng := -170; lng <= 170; lng += 10 {
+			features = append(features, geojson.NewFeature[geojson.Geometry](
+				geojson.NewPoint(float64(lat), float64(lng)),
+			))
+		}
+	}
+
+	var buf bytes.Buffer
+	w := geojson.NewFlatGeobufWriter(&buf, geojson.FlatGeobufOptions{IndexNodeSize: 4})
+	for _, feature := range features {
+		require.NoError(t, w.Write(feature))
+	}
+	require.NoError(t, w.Close())
+
+	r, err := geojson.NewFlatGeobufReader(bytes.NewReader(buf.Bytes()))
+	require.NoError(t, err)
+
+	box := geojson.BoundingBox{
+		BottomLeft: geojson.MakePosition(-5, 15),
+		TopRight:   geojson.MakePosition(30, 40),
+	}
+
+	found, err := r.Search(box)
+	require.NoError(t, err)
+
+	var expected []geojson.Feature[geojson.Geometry]
+	for lat := 0; lat <= 30; lat += 10 {
+		for lng := 20; lng <= 40; lng += 10 {
+			expected = append(expected, geojson.NewFeature[geojson.Geometry](
+				geojson.NewPoint(float64(lat), float64(lng)),
+			))
+		}
+	}
+	require.ElementsMatch(t, expected, found)
+
+	found, err = r.Search(geojson.BoundingBox{
+		BottomLeft: geojson.MakePosition(1, 1),
+		TopRight:   geojson.MakePosition(2, 2),
+	})
+	require.NoError(t, err)
+	require.Empty(t, found)
+
+	r, err = geojson.NewFlatGeobufReader(bytes.NewBuffer(buf.Bytes()))
+	require.NoError(t, err)
+
+	_, err = r.Search(box)
+	require.Error(t, err)
+}
+
+func TestFlatGeobufProperties(t *testing.T) {
+	columns := []geojson.FlatGeobufColumn{
+		{Name: "byte", Type: geojson.FlatGeobufByte},
+		{Name: "ubyte", Type: geojson.FlatGeobufUByte},
+		{Name: "short", Type: geojson.FlatGeobufShort},
+		{Name: "ushort", Type: geojson.FlatGeobufUShort},
+		{Name: "int", Type: geojson.FlatGeobufInt},
+		{Name: "uint", Type: geojson.FlatGeobufUInt},
+		{Name: "ulong", Type: geojson.FlatGeobufULong},
+		{Name: "float", Type: geojson.FlatGeobufFloat},
+		{Name: "datetime", Type: geojson.FlatGeobufDateTime},
+		{Name: "binary", Type: geojson.FlatGeobufBinary},
+	}
+
+	var buf bytes.Buffer
+	w := geojson.NewFlatGeobufWriter(&buf, geojson.FlatGeobufOptions{Columns: columns})
+	require.NoError(t, w.Write(geojson.NewFeature[geojson.Geometry](
+		geojson.NewPoint(1, 2),
+		geojson.Property{Name: "byte", Value: -3.0},
+		geojson.Property{Name: "ubyte", Value: 200},
+		geojson.Property{Name: "short", Value: int16(-300)},
+		geojson.Property{Name: "ushort", Value: uint(60000)},
+		geojson.Property{Name: "int", Value: -70000},
+		geojson.Property{Name: "uint", Value: 70000.0},
+		geojson.Property{Name: "ulong", Value: uint64(1 << 63)},
+		geojson.Property{Name: "float", Value: 1.5},
+		geojson.Property{Name: "datetime", Value: "2020-01-02T03:04:05Z"},
+		geojson.Property{Name: "binary", Value: []byte{1, 2, 3}},
+		geojson.Property{Name: "missing", Value: nil},
+	)))
+	require.NoError(t, w.Close())
+
+	r, err := geojson.NewFlatGeobufReader(&buf)
+	require.NoError(t, err)
+
+	feature, err := r.Read()
+	require.NoError(t, err)
+	require.Equal(t, geojson.PropertyList{
+		{Name: "byte", Value: int8(-3)},
+		{Name: "ubyte", Value: uint8(200)},
+		{Name: "short", Value: int16(-300)},
+		{Name: "ushort", Value: uint16(60000)},
+		{Name: "int", Value: int32(-70000)},
+		{Name: "uint", Value: uint32(70000)},
+		{Name: "ulong", Value: uint64(1 << 63)},
+		{Name: "float", Value: float32(1.5)},
+		{Name: "datetime", Value: "2020-01-02T03:04:05Z"},
+		{Name: "binary", Value: []byte{1, 2, 3}},
+	}, feature.Properties())
+
+	_, err = r.Read()
+	require.Equal(t, io.EOF, err)
+}
+
+func TestFlatGeobufAlignment(t *testing.T) {
+	columns := []geojson.FlatGeobufColumn{
+		{Name: "name", Type: geojson.FlatGeobufString},
+		{Name: "population", Type: geojson.FlatGeobufLong},
+	}
+
+	var buf bytes.Buffer
+	w := geojson.NewFlatGeobufWriter(&buf, geojson.FlatGeobufOptions{Name: "test", Columns: columns, NoIndex: true})
+	for _, feature := range []geojson.Feature[geojson.Geometry]{
+		geojson.NewFeature[geojson.Geometry](
+			geojson.NewPointWithElevation(45.4642035, 9.189982, 120),
+			geojson.Property{Name: "name", Value: "Milan"},
+			geojson.Property{Name: "population", Value: int64(1352000)},
+		),
+		geojson.NewFeature[geojson.Geometry](
+			geojson.NewMultiLineString(
+				[]geojson.Position{geojson.MakePositionWithElevation(1, 2, 3), geojson.MakePositionWithElevation(4, 5, 6)},
+				[]geojson.Position{geojson.MakePositionWithElevation(7, 8, 9), geojson.MakePositionWithElevation(1, 2, 3)},
+			),
+			geojson.Property{Name: "name", Value: "a"},
+		),
+		geojson.NewFeature[geojson.Geometry](
+			geojson.NewGeometryCollection(
+				geojson.NewPointWithElevation(1, 2, 3),
+				geojson.NewLineString(geojson.MakePositionWithElevation(3, 4, 5), geojson.MakePositionWithElevation(5, 6, 7)),
+			),
+		),
+	} {
+		require.NoError(t, w.Write(feature))
+	}
+	require.NoError(t, w.Close())
+
+	// Each buffer follows a 4 byte size prefix, and must pass the checks made by the reference FlatBuffers verifier.
+	data := buf.Bytes()[8:]
+	size := binary.LittleEndian.Uint32(data)
+	require.NoError(t, verifyFlatBuffer(data[4:4+size], "Header"))
+
+	data = data[4+size:]
+	for len(data) != 0 {
+		size := binary.LittleEndian.Uint32(data)
+		require.NoError(t, verifyFlatBuffer(data[4:4+size], "Feature"))
+		data = data[4+size:]
+	}
+}
+
+// fbTestField is a field of a FlatBuffers table in the FlatGeobuf schema.
+// The kind is 's' for strings, 'n' for scalars, 'v' for vectors of scalars, 't' for tables and 'T' for vectors of tables.
+type fbTestField struct {
+	kind  byte
+	size  int
+	table string
+}
+
+var flatGeobufTestSchema = map[string][]fbTestField{
+	"Header": {
+		{kind: 's'}, {kind: 'v', size: 8}, {kind: 'n', size: 1}, {kind: 'n', size: 1}, {kind: 'n', size: 1}, {kind: 'n', size: 1},
+		{kind: 'n', size: 1}, {kind: 'T', table: "Column"}, {kind: 'n', size: 8}, {kind: 'n', size: 2}, {kind: 't', table: "Crs"},
+		{kind: 's'}, {kind: 's'}, {kind: 's'},
+	},
+	"Feature": {
+		{kind: 't', table: "Geometry"}, {kind: 'v', size: 1}, {kind: 'T', table: "Column"},
+	},
+	"Geometry": {
+		{kind: 'v', size: 4}, {kind: 'v', size: 8}, {kind: 'v', size: 8}, {kind: 'v', size: 8}, {kind: 'v', size: 8}, {kind: 'v', size: 8},
+		{kind: 'n', size: 1}, {kind: 'T', table: "Geometry"},
+	},
+	"Column": {
+		{kind: 's'}, {kind: 'n', size: 1}, {kind: 's'}, {kind: 's'}, {kind: 'n', size: 4}, {kind: 'n', size: 4}, {kind: 'n', size: 4},
+		{kind: 'n', size: 1}, {kind: 'n', size: 1}, {kind: 'n', size: 1}, {kind: 's'},
+	},
+	"Crs": {
+		{kind: 's'}, {kind: 'n', size: 4}, {kind: 's'}, {kind: 's'}, {kind: 's'}, {kind: 's'},
+	},
+}
+
+// verifyFlatBuffer checks the bounds and alignment of every object in the buffer, relative to its start.
+func verifyFlatBuffer(buf []byte, root string) error {
+	v := fbTestVerifier{buf}
+	if err := v.check(0, 4); err != nil {
+		return err
+	}
+	return v.table(int(binary.LittleEndian.Uint32(buf)), root)
+}
+
+type fbTestVerifier struct {
+	buf []byte
+}
+
+func (v fbTestVerifier) check(pos, size int) error {
+	if err := v.inRange(pos, size); err != nil {
+		return err
+	} else if pos%size != 0 {
+		return fmt.Errorf("%d bytes at %d are misaligned", size, pos)
+	}
+	return nil
+}
+
+func (v fbTestVerifier) inRange(pos, size int) error {
+	if pos < 0 || pos+size > len(v.buf) {
+		return fmt.Errorf("%d bytes at %d are out of range", size, pos)
+	}
+	return nil
+}
+
+func (v fbTestVerifier) uint32(pos int) int {
+	return int(binary.LittleEndian.Uint32(v.buf[pos:]))
+}
+
+func (v fbTestVerifier) table(pos int, name string) error {
+	if err := v.check(pos, 4); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+
+	vtable := pos - int(int32(binary.LittleEndian.Uint32(v.buf[pos:])))
+	if err := v.check(vtable, 2); err != nil {
+		return fmt.Errorf("%s vtable: %w", name, err)
+	}
+	vtableSize := int(binary.LittleEndian.Uint16(v.buf[vtable:]))
+	tableSize := int(binary.LittleEndian.Uint16(v.buf[vtable+2:]))
+	if vtableSize%2 != 0 || vtableSize < 4 || vtable+vtableSize > len(v.buf) || pos+tableSize > len(v.buf) {
+		return fmt.Errorf("%s: invalid vtable", name)
+	}
+
+	for id, field := range flatGeobufTestSchema[name] {
+		if 4+2*id >= vtableSize {
+			break
+		}
+		offset := int(binary.LittleEndian.Uint16(v.buf[vtable+4+2*id:]))
+		if offset == 0 {
+			continue
+		}
+
+		if err := v.field(pos+offset, field); err != nil {
+			return fmt.Errorf("%s field %d: %w", name, id, err)
+		}
+	}
+	return nil
+}
+
+func (v fbTestVerifier) field(pos int, field fbTestField) error {
+	if field.kind == 'n' {
+		return v.check(pos, field.size)
+	} else if err := v.check(pos, 4); err != nil {
+		return err
+	}
+
+	target := pos + v.uint32(pos)
+	if field.kind == 't' {
+		return v.table(target, field.table)
+	} else if err := v.check(target, 4); err != nil {
+		return err
+	}
+
+	n := v.uint32(target)
+	switch field.kind {
+	case 's':
+		if err := v.inRange(target+4, n+1); err != nil {
+			return err
+		} else if v.buf[target+4+n] != 0 {
+			return fmt.Errorf("string at %d isn't terminated", target)
+		}
+	case 'v':
+		if err := v.inRange(target+4, n*field.size); err != nil {
+			return err
+		} else if (target+4)%field.size != 0 {
+			return fmt.Errorf("vector at %d is misaligned", target)
+		}
+	case 'T':
+		for i := 0; i < n; i++ {
+			slot := target + 4 + 4*i
+			if err := v.check(slot, 4); err != nil {
+				return err
+			} else if err := v.table(slot+v.uint32(slot), field.table); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func TestFlatGeobufInvalidProperties(t *testing.T) {
+	columns := []geojson.FlatGeobufColumn{
+		{Name: "byte", Type: geojson.FlatGeobufByte},
+		{Name: "string", Type: geojson.FlatGeobufString},
+	}
+
+	for name, prop := range map[string]geojson.Property{
+		"out of range":  {Name: "byte", Value: 128},
+		"not integral":  {Name: "byte", Value: 1.5},
+		"wrong type":    {Name: "string", Value: 1},
+		"not in schema": {Name: "other", Value: "value"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			w := geojson.NewFlatGeobufWriter(io.Discard, geojson.FlatGeobufOptions{Columns: columns})
+			require.Error(t, w.Write(geojson.NewFeature[geojson.Geometry](geojson.NewPoint(1, 2), prop)))
+		})
+	}
+}
+
+func TestFlatGeobufInvalid(t *testing.T) {
+	_, err := geojson.NewFlatGeobufReader(bytes.NewReader([]byte("not a flatgeobuf file")))
+	require.Error(t, err)
+
+	var buf bytes.Buffer
+	w := geojson.NewFlatGeobufWriter(&buf, geojson.FlatGeobufOptions{})
+	require.NoError(t, w.Write(geojson.NewFeature[geojson.Geometry](geojson.NewPoint(1, 2))))
+	require.NoError(t, w.Close())
+
+	data := buf.Bytes()
+	r, err := geojson.NewFlatGeobufReader(bytes.NewReader(data[:len(data)-4]))
+	require.NoError(t, err)
+
+	_, err = r.Read()
+	require.Error(t, err)
+}
@@ -0,0 +1,764 @@
+package geojson
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// Geobuf geometry types.
+const (
+	geobufPoint = iota
+	geobufMultiPoint
+	geobufLineString
+	geobufMultiLineString
+	geobufPolygon
+	geobufMultiPolygon
+	geobufGeometryCollection
+)
+
+const (
+	// Precision assumed by readers when none is specified.
+	geobufDefaultPrecision = 6
+	// Maximum number of decimal digits retained, at which point coordinates can still be represented exactly by a float64.
+	geobufMaxPrecision = 12
+)
+
+// MarshalGeobufFeatureCollection returns the Geobuf encoding of the FeatureCollection.
+// Feature IDs that aren't integers are stored as strings, and features without a geometry are written without one.
+func MarshalGeobufFeatureCollection(c FeatureCollection) ([]byte, error) {
+	geometries := make([]Geometry, len(c.features))
+	for i, f := range c.features {
+		geometries[i] = f.geometry
+	}
+
+	e, err := newGeobufEncoder(geometries...)
+	if err != nil {
+		return nil, err
+	}
+
+	var collection []byte
+	for _, f := range c.features {
+		feature, err := e.feature(f)
+		if err != nil {
+			return nil, err
+		}
+		collection = appendProtoBytes(collection, 1, feature)
+	}
+	return e.data(4, collection), nil
+}
+
+// MarshalGeobufFeature returns the Geobuf encoding of the Feature.
+// IDs that aren't integers are stored as strings, and a feature without a geometry is written without one.
+func MarshalGeobufFeature(f Feature[Geometry]) ([]byte, error) {
+	e, err := newGeobufEncoder(f.geometry)
+	if err != nil {
+		return nil, err
+	}
+
+	feature, err := e.feature(f)
+	if err != nil {
+		return nil, err
+	}
+	return e.data(5, feature), nil
+}
+
+// MarshalGeobufGeometry returns the Geobuf encoding of the Geometry.
+func MarshalGeobufGeometry(geo Geometry) ([]byte, error) {
+	e, err := newGeobufEncoder(geo)
+	if err != nil {
+		return nil, err
+	}
+
+	geometry, err := e.geometry(geo)
+	if err != nil {
+		return nil, err
+	}
+	return e.data(6, geometry), nil
+}
+
+// UnmarshalGeobufFeatureCollection parses the Geobuf encoded FeatureCollection.
+func UnmarshalGeobufFeatureCollection(data []byte) (FeatureCollection, error) {
+	d, payload, err := newGeobufDecoder(data, 4)
+	if err != nil {
+		return FeatureCollection{}, err
+	}
+
+	var features []Feature[Geometry]
+	for payload.more() {
+		field, wire, err := payload.next()
+		if err != nil {
+			return FeatureCollection{}, err
+		}
+
+		if field != 1 {
+			if err := payload.skip(wire); err != nil {
+				return FeatureCollection{}, err
+			}
+			continue
+		}
+
+		msg, err := payload.message()
+		if err != nil {
+			return FeatureCollection{}, err
+		}
+
+		feature, err := d.feature(msg)
+		if err != nil {
+			return FeatureCollection{}, err
+		}
+		features = append(features, feature)
+	}
+	return NewFeatureCollection(features...), nil
+}
+
+// UnmarshalGeobufFeature parses the Geobuf encoded Feature.
+func UnmarshalGeobufFeature(data []byte) (Feature[Geometry], error) {
+	d, payload, err := newGeobufDecoder(data, 5)
+	if err != nil {
+		return Feature[Geometry]{}, err
+	}
+	return d.feature(payload)
+}
+
+// UnmarshalGeobufGeometry parses the Geobuf encoded Geometry.
+func UnmarshalGeobufGeometry(data []byte) (Geometry, error) {
+	d, payload, err := newGeobufDecoder(data, 6)
+	if err != nil {
+		return nil, err
+	}
+	return d.geometry(payload)
+}
+
+type geobufEncoder struct {
+	keys      []string
+	keyIndex  map[string]int
+	dims      int
+	precision int
+	scale     float64
+}
+
+func newGeobufEncoder(geometries ...Geometry) (*geobufEncoder, error) {
+	e := geobufEncoder{
+		keyIndex: map[string]int{},
+		dims:     2,
+	}
+
+	var err error
+	var seen bool
+	scale := 1.0
+	updateScale := func(v float64) {
+		for math.Round(v*scale)/scale != v && e.precision < geobufMaxPrecision {
+			scale *= 10
+			e.precision++
+		}
+	}
+
+	for _, geo := range geometries {
+		eachPosition(geo, func(pos Position) {
+			if hasZ := pos.elevation != nil; !seen {
+				seen = true
+				if hasZ {
+					e.dims = 3
+				}
+			} else if hasZ != (e.dims == 3) {
+				err = fmt.Errorf("positions must be in the same dimension")
+			}
+
+			updateScale(pos.lng)
+			updateScale(pos.lat)
+			if pos.elevation != nil {
+				updateScale(*pos.elevation)
+			}
+		})
+	}
+
+	e.scale = math.Pow10(e.precision)
+	return &e, err
+}
+
+func (e *geobufEncoder) data(field int, payload []byte) []byte {
+	var data []byte
+	for _, key := range e.keys {
+		data = appendProtoString(data, 1, key)
+	}
+	if e.dims != 2 {
+		data = appendProtoVarint(data, 2, uint64(e.dims))
+	}
+	if e.precision != geobufDefaultPrecision {
+		data = appendProtoVarint(data, 3, uint64(e.precision))
+	}
+	return appendProtoBytes(data, field, payload)
+}
+
+func (e *geobufEncoder) feature(f Feature[Geometry]) ([]byte, error) {
+	var data []byte
+	if f.HasGeometry() {
+		geometry, err := e.geometry(f.geometry)
+		if err != nil {
+			return nil, err
+		}
+		data = appendProtoBytes(data, 1, geometry)
+	}
+	if f.id != nil {
+		if v, ok := f.id.Int64(); ok {
+			data = appendProtoSint(data, 12, v)
+		} else {
+			data = appendProtoString(data, 11, f.id.String())
+		}
+	}
+
+	properties := make([]uint64, 0, 2*len(f.properties))
+	for i, prop := range f.properties {
+		value, err := geobufValue(prop.Value)
+		if err != nil {
+			return nil, fmt.Errorf("property '%s': %w", prop.Name, err)
+		}
+		data = appendProtoBytes(data, 13, value)
+		properties = append(properties, uint64(e.key(prop.Name)), uint64(i))
+	}
+
+	if len(properties) != 0 {
+		data = appendProtoPackedVarints(data, 14, properties)
+	}
+	return data, nil
+}
+
+func (e *geobufEncoder) key(name string) int {
+	if i, ok := e.keyIndex[name]; ok {
+		return i
+	}
+
+	e.keyIndex[name] = len(e.keys)
+	e.keys = append(e.keys, name)
+	return len(e.keys) - 1
+}
+
+func (e *geobufEncoder) geometry(geo Geometry) ([]byte, error) {
+	var data []byte
+	var lengths []uint64
+	var coords []int64
+
+	switch g := geo.(type) {
+	case *Point:
+		data = appendProtoVarint(data, 1, geobufPoint)
+		coords = e.line(coords, []Position{g.pos}, false)
+	case *MultiPoint:
+		data = appendProtoVarint(data, 1, geobufMultiPoint)
+		coords = e.line(coords, g.positions, false)
+	case *LineString:
+		data = appendProtoVarint(data, 1, geobufLineString)
+		coords = e.line(coords, g.positions, false)
+	case *MultiLineString:
+		data = appendProtoVarint(data, 1, geobufMultiLineString)
+		lengths, coords = e.lines(g.lines, false)
+	case *Polygon:
+		data = appendProtoVarint(data, 1, geobufPolygon)
+		lengths, coords = e.lines(g.rings, true)
+	case *MultiPolygon:
+		data = appendProtoVarint(data, 1, geobufMultiPolygon)
+		if len(g.polygons) != 1 || len(g.polygons[0]) != 1 {
+			lengths = append(lengths, uint64(len(g.polygons)))
+			for _, polygon := range g.polygons {
+				lengths = append(lengths, uint64(len(polygon)))
+				for _, ring := range polygon {
+					lengths = append(lengths, uint64(geobufRingLength(ring)))
+				}
+			}
+		}
+		for _, polygon := range g.polygons {
+			for _, ring := range polygon {
+				coords = e.line(coords, ring, true)
+			}
+		}
+	case *GeometryCollection:
+		data = appendProtoVarint(data, 1, geobufGeometryCollection)
+		for _, geo := range g.geometries {
+			geometry, err := e.geometry(geo)
+			if err != nil {
+				return nil, err
+			}
+			data = appendProtoBytes(data, 4, geometry)
+		}
+	default:
+		return nil, fmt.Errorf("unsupported geometry type '%T'", geo)
+	}
+
+	if len(lengths) != 0 {
+		data = appendProtoPackedVarints(data, 2, lengths)
+	}
+	if len(coords) != 0 {
+		data = appendProtoPackedSints(data, 3, coords)
+	}
+	return data, nil
+}
+
+func (e *geobufEncoder) lines(lines [][]Position, closed bool) ([]uint64, []int64) {
+	var lengths []uint64
+	if len(lines) != 1 {
+		for _, line := range lines {
+			if closed {
+				lengths = append(lengths, uint64(geobufRingLength(line)))
+			} else {
+				lengths = append(lengths, uint64(len(line)))
+			}
+		}
+	}
+
+	var coords []int64
+	for _, line := range lines {
+		coords = e.line(coords, line, closed)
+	}
+	return lengths, coords
+}
+
+// line appends the delta-encoded coordinates of the positions, omitting the last position of closed rings.
+func (e *geobufEncoder) line(coords []int64, positions []Position, closed bool) []int64 {
+	if closed {
+		positions = positions[:geobufRingLength(positions)]
+	}
+
+	var sum [3]int64
+	for _, pos := range positions {
+		values := [3]float64{pos.lng, pos.lat}
+		if e.dims == 3 {
+			values[2] = *pos.elevation
+		}
+
+		for i := 0; i < e.dims; i++ {
+			n := int64(math.Round(values[i]*e.scale)) - sum[i]
+			coords = append(coords, n)
+			sum[i] += n
+		}
+	}
+	return coords
+}
+
+func geobufRingLength(ring []Position) int {
+	if len(ring) == 0 {
+		return 0
+	}
+	return len(ring) - 1
+}
+
+func geobufValue(value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case string:
+		return appendProtoString(nil, 1, v), nil
+	case float64:
+		return appendProtoDouble(nil, 2, v), nil
+	case float32:
+		return appendProtoDouble(nil, 2, float64(v)), nil
+	case bool:
+		var b uint64
+		if v {
+			b = 1
+		}
+		return appendProtoVarint(nil, 5, b), nil
+	case int:
+		return geobufInt(int64(v)), nil
+	case int8:
+		return geobufInt(int64(v)), nil
+	case int16:
+		return geobufInt(int64(v)), nil
+	case int32:
+		return geobufInt(int64(v)), nil
+	case int64:
+		return geobufInt(v), nil
+	case uint:
+		return appendProtoVarint(nil, 3, uint64(v)), nil
+	case uint8:
+		return appendProtoVarint(nil, 3, uint64(v)), nil
+	case uint16:
+		return appendProtoVarint(nil, 3, uint64(v)), nil
+	case uint32:
+		return appendProtoVarint(nil, 3, uint64(v)), nil
+	case uint64:
+		return appendProtoVarint(nil, 3, v), nil
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		return appendProtoString(nil, 6, string(data)), nil
+	}
+}
+
+func geobufInt(v int64) []byte {
+	if v < 0 {
+		return appendProtoVarint(nil, 4, uint64(-v))
+	}
+	return appendProtoVarint(nil, 3, uint64(v))
+}
+
+type geobufDecoder struct {
+	keys  []string
+	dims  int
+	scale float64
+}
+
+// newGeobufDecoder parses the top-level Data message, and returns the payload, which must be of the expected type.
+func newGeobufDecoder(data []byte, expected int) (*geobufDecoder, *protoReader, error) {
+	d := geobufDecoder{
+		dims: 2,
+	}
+
+	precision := uint64(geobufDefaultPrecision)
+	var payload *protoReader
+	r := protoReader{data: data}
+	for r.more() {
+		field, wire, err := r.next()
+		if err != nil {
+			return nil, nil, err
+		}
+
+		switch field {
+		case 1:
+			key, err := r.bytes()
+			if err != nil {
+				return nil, nil, err
+			}
+			d.keys = append(d.keys, string(key))
+		case 2:
+			dims, err := r.varint()
+			if err != nil {
+				return nil, nil, err
+			} else if dims < 2 {
+				return nil, nil, fmt.Errorf("invalid dimensions %d", dims)
+			}
+			d.dims = int(dims)
+		case 3:
+			if precision, err = r.varint(); err != nil {
+				return nil, nil, err
+			}
+		case 4, 5, 6:
+			if field != expected {
+				return nil, nil, fmt.Errorf("unexpected data type %d, expecting %d", field, expected)
+			}
+			if payload, err = r.message(); err != nil {
+				return nil, nil, err
+			}
+		default:
+			if err := r.skip(wire); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	if payload == nil {
+		return nil, nil, fmt.Errorf("missing data")
+	}
+
+	d.scale = math.Pow10(int(precision))
+	return &d, payload, nil
+}
+
+func (d *geobufDecoder) feature(r *protoReader) (Feature[Geometry], error) {
+	var feature Feature[Geometry]
+	var values []interface{}
+	var properties []uint64
+
+	for r.more() {
+		field, wire, err := r.next()
+		if err != nil {
+			return Feature[Geometry]{}, err
+		}
+
+		switch field {
+		case 1:
+			msg, err := r.message()
+			if err != nil {
+				return Feature[Geometry]{}, err
+			}
+			if feature.geometry, err = d.geometry(msg); err != nil {
+				return Feature[Geometry]{}, err
+			}
+		case 11:
+			id, err := r.bytes()
+			if err != nil {
+				return Feature[Geometry]{}, err
+			}
+			feature = feature.WithID(StringFeatureID(string(id)))
+		case 12:
+			id, err := r.sint()
+			if err != nil {
+				return Feature[Geometry]{}, err
+			}
+			feature = feature.WithID(IntFeatureID(id))
+		case 13:
+			msg, err := r.message()
+			if err != nil {
+				return Feature[Geometry]{}, err
+			}
+			value, err := d.value(msg)
+			if err != nil {
+				return Feature[Geometry]{}, err
+			}
+			values = append(values, value)
+		case 14:
+			if properties, err = r.varints(wire, properties); err != nil {
+				return Feature[Geometry]{}, err
+			}
+		default:
+			if err := r.skip(wire); err != nil {
+				return Feature[Geometry]{}, err
+			}
+		}
+	}
+
+	if len(properties)%2 != 0 {
+		return Feature[Geometry]{}, fmt.Errorf("invalid properties")
+	}
+
+	for i := 0; i < len(properties); i += 2 {
+		key, value := properties[i], properties[i+1]
+		if key >= uint64(len(d.keys)) || value >= uint64(len(values)) {
+			return Feature[Geometry]{}, fmt.Errorf("property index out of range")
+		}
+		feature.properties = append(feature.properties, Property{
+			Name:  d.keys[key],
+			Value: values[value],
+		})
+	}
+	return feature, nil
+}
+
+func (d *geobufDecoder) value(r *protoReader) (interface{}, error) {
+	var value interface{}
+	for r.more() {
+		field, wire, err := r.next()
+		if err != nil {
+			return nil, err
+		}
+
+		switch field {
+		case 1:
+			s, err := r.bytes()
+			if err != nil {
+				return nil, err
+			}
+			value = string(s)
+		case 2:
+			if value, err = r.double(); err != nil {
+				return nil, err
+			}
+		case 3:
+			v, err := r.varint()
+			if err != nil {
+				return nil, err
+			} else if v > math.MaxInt64 {
+				value = v
+			} else {
+				value = int64(v)
+			}
+		case 4:
+			v, err := r.varint()
+			if err != nil {
+				return nil, err
+			}
+			value = -int64(v)
+		case 5:
+			v, err := r.varint()
+			if err != nil {
+				return nil, err
+			}
+			value = v != 0
+		case 6:
+			s, err := r.bytes()
+			if err != nil {
+				return nil, err
+			}
+			if err := json.Unmarshal(s, &value); err != nil {
+				return nil, err
+			}
+		default:
+			if err := r.skip(wire); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return value, nil
+}
+
+func (d *geobufDecoder) geometry(r *protoReader) (Geometry, error) {
+	var typ uint64
+	var lengths []uint64
+	var coords []int64
+	var geometries []Geometry
+
+	for r.more() {
+		field, wire, err := r.next()
+		if err != nil {
+			return nil, err
+		}
+
+		switch field {
+		case 1:
+			if typ, err = r.varint(); err != nil {
+				return nil, err
+			}
+		case 2:
+			if lengths, err = r.varints(wire, lengths); err != nil {
+				return nil, err
+			}
+		case 3:
+			if coords, err = r.sints(wire, coords); err != nil {
+				return nil, err
+			}
+		case 4:
+			msg, err := r.message()
+			if err != nil {
+				return nil, err
+			}
+			geo, err := d.geometry(msg)
+			if err != nil {
+				return nil, err
+			}
+			geometries = append(geometries, geo)
+		default:
+			if err := r.skip(wire); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if len(coords)%d.dims != 0 {
+		return nil, fmt.Errorf("invalid number of coordinates")
+	}
+	c := geobufCoords{decoder: d, coords: coords}
+
+	switch typ {
+	case geobufPoint:
+		positions, err := c.line(1, false)
+		if err != nil {
+			return nil, err
+		}
+		return NewPointFromPosition(positions[0]), nil
+
+	case geobufMultiPoint:
+		positions, err := c.line(c.remaining(), false)
+		if err != nil {
+			return nil, err
+		}
+		return NewMultiPoint(positions...), nil
+
+	case geobufLineString:
+		positions, err := c.line(c.remaining(), false)
+		if err != nil {
+			return nil, err
+		}
+		return &LineString{positions: positions}, nil
+
+	case geobufMultiLineString:
+		lines, err := c.lines(lengths, false)
+		if err != nil {
+			return nil, err
+		}
+		return NewMultiLineString(lines...), nil
+
+	case geobufPolygon:
+		rings, err := c.lines(lengths, true)
+		if err != nil {
+			return nil, err
+		}
+		return NewPolygon(rings...), nil
+
+	case geobufMultiPolygon:
+		if len(lengths) == 0 {
+			ring, err := c.line(c.remaining(), true)
+			if err != nil {
+				return nil, err
+			}
+			return NewMultiPolygon([][]Position{ring}), nil
+		}
+
+		var polygons [][][]Position
+		n, lengths := lengths[0], lengths[1:]
+		for i := uint64(0); i < n; i++ {
+			if len(lengths) == 0 || uint64(len(lengths)-1) < lengths[0] {
+				return nil, fmt.Errorf("invalid lengths")
+			}
+
+			rings, err := c.lines(lengths[1:1+lengths[0]], true)
+			if err != nil {
+				return nil, err
+			}
+			polygons = append(polygons, rings)
+			lengths = lengths[1+lengths[0]:]
+		}
+		return NewMultiPolygon(polygons...), nil
+
+	case geobufGeometryCollection:
+		return NewGeometryCollection(geometries...), nil
+
+	default:
+		return nil, fmt.Errorf("unsupported geometry type '%d'", typ)
+	}
+}
+
+type geobufCoords struct {
+	decoder *geobufDecoder
+	coords  []int64
+	pos     int
+}
+
+func (c *geobufCoords) remaining() int {
+	return (len(c.coords) - c.pos) / c.decoder.dims
+}
+
+func (c *geobufCoords) lines(lengths []uint64, closed bool) ([][]Position, error) {
+	if len(lengths) == 0 && c.remaining() == 0 {
+		return nil, nil
+	} else if len(lengths) == 0 {
+		line, err := c.line(c.remaining(), closed)
+		if err != nil {
+			return nil, err
+		}
+		return [][]Position{line}, nil
+	}
+
+	lines := make([][]Position, len(lengths))
+	for i, n := range lengths {
+		if n > uint64(c.remaining()) {
+			return nil, fmt.Errorf("invalid lengths")
+		}
+
+		var err error
+		if lines[i], err = c.line(int(n), closed); err != nil {
+			return nil, err
+		}
+	}
+	return lines, nil
+}
+
+// line decodes n delta-encoded positions, repeating the first position of closed rings.
+func (c *geobufCoords) line(n int, closed bool) ([]Position, error) {
+	if n > c.remaining() {
+		return nil, fmt.Errorf("invalid number of coordinates")
+	}
+
+	dims := c.decoder.dims
+	positions := make([]Position, 0, n+1)
+	var sum [3]int64
+	for i := 0; i < n; i++ {
+		for j := 0; j < dims; j++ {
+			if j < 3 {
+				sum[j] += c.coords[c.pos+j]
+			}
+		}
+		c.pos += dims
+
+		lng := float64(sum[0]) / c.decoder.scale
+		lat := float64(sum[1]) / c.decoder.scale
+		if dims > 2 {
+			positions = append(positions, MakePositionWithElevation(lat, lng, float64(sum[2])/c.decoder.scale))
+		} else {
+			positions = append(positions, MakePosition(lat, lng))
+		}
+	}
+
+	if closed && n > 0 {
+		positions = append(positions, positions[0])
+	}
+	return positions, nil
+}
@@ -0,0 +1,162 @@
+package geojson_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	geojson "github.com/everystreet/go-geojson/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeobufGeometry(t *testing.T) {
+	geometries := map[string]geojson.Geometry{
+		"point":                geojson.NewPoint(45.4642035, 9.189982),
+		"point with elevation": geojson.NewPointWithElevation(45.5, 9.25, 125),
+		"multipoint": geojson.NewMultiPoint(
+			geojson.MakePosition(12, 34),
+			geojson.MakePosition(56, 78),
+		),
+		"linestring": geojson.NewLineString(
+			geojson.MakePositionWithElevation(12.5, 34, 1),
+			geojson.MakePositionWithElevation(56, 78.25, 2),
+		),
+		"multilinestring": geojson.NewMultiLineString(
+			[]geojson.Position{
+				geojson.MakePosition(12, 34),
+				geojson.MakePosition(56, 78),
+			},
+			[]geojson.Position{
+				geojson.MakePosition(23, 45),
+				geojson.MakePosition(67, 89),
+				geojson.MakePosition(1, 2),
+			},
+		),
+		"polygon": geojson.NewPolygon(
+			[]geojson.Position{
+				geojson.MakePosition(7, 7),
+				geojson.MakePosition(4, 8),
+				geojson.MakePosition(3, 4),
+				geojson.MakePosition(7, 7),
+			},
+		),
+		"polygon with hole": geojson.NewPolygon(
+			[]geojson.Position{
+				geojson.MakePosition(7, 7),
+				geojson.MakePosition(4, 8),
+				geojson.MakePosition(3, 4),
+				geojson.MakePosition(5, 2),
+				geojson.MakePosition(7, 3),
+				geojson.MakePosition(7, 7),
+			},
+			[]geojson.Position{
+				geojson.MakePosition(4, 4),
+				geojson.MakePosition(4, 6),
+				geojson.MakePosition(5, 7),
+				geojson.MakePosition(6, 4),
+				geojson.MakePosition(4, 4),
+			},
+		),
+		"multipolygon": geojson.NewMultiPolygon(
+			[][]geojson.Position{
+				{
+					geojson.MakePosition(7, 7),
+					geojson.MakePosition(4, 8),
+					geojson.MakePosition(3, 4),
+					geojson.MakePosition(7, 7),
+				},
+			},
+			[][]geojson.Position{
+				{
+					geojson.MakePosition(1, 1),
+					geojson.MakePosition(1, 2),
+					geojson.MakePosition(2, 2),
+					geojson.MakePosition(1, 1),
+				},
+			},
+		),
+		"geometry collection": geojson.NewGeometryCollection(
+			geojson.NewPoint(1, 2),
+			geojson.NewLineString(
+				geojson.MakePosition(3, 4),
+				geojson.MakePosition(5, 6),
+			),
+		),
+	}
+
+	for name, geometry := range geometries {
+		t.Run(name, func(t *testing.T) {
+			data, err := geojson.MarshalGeobufGeometry(geometry)
+			require.NoError(t, err)
+
+			geo, err := geojson.UnmarshalGeobufGeometry(data)
+			require.NoError(t, err)
+			require.Equal(t, geometry, geo)
+		})
+	}
+}
+
+func TestGeobufEncoding(t *testing.T) {
+	data, err := geojson.MarshalGeobufGeometry(geojson.NewPoint(2, 1))
+	require.NoError(t, err)
+	require.Equal(t, "1800320608001a020204", hex.EncodeToString(data))
+}
+
+func TestGeobufFeature(t *testing.T) {
+	feature := geojson.NewFeature[geojson.Geometry](
+		geojson.NewPoint(45.4642035, 9.189982),
+		geojson.Property{Name: "city", Value: "Milan"},
+		geojson.Property{Name: "population", Value: int64(1352000)},
+		geojson.Property{Name: "elevation", Value: -1.5},
+		geojson.Property{Name: "offset", Value: int64(-7)},
+		geojson.Property{Name: "capital", Value: false},
+		geojson.Property{Name: "districts", Value: []interface{}{"Centro", "Brera"}},
+		geojson.Property{Name: "mayor", Value: nil},
+	)
+
+	data, err := geojson.MarshalGeobufFeature(feature)
+	require.NoError(t, err)
+
+	unmarshalled, err := geojson.UnmarshalGeobufFeature(data)
+	require.NoError(t, err)
+	require.Equal(t, feature, unmarshalled)
+
+	_, err = geojson.UnmarshalGeobufGeometry(data)
+	require.Error(t, err)
+}
+
+func TestGeobufFeatureCollection(t *testing.T) {
+	collection := geojson.NewFeatureCollection(
+		geojson.NewFeatureWithID[geojson.Geometry](
+			geojson.NewPoint(45.4642035, 9.189982),
+			geojson.IntFeatureID(-5),
+			geojson.Property{Name: "city", Value: "Milan"},
+		),
+		geojson.NewFeatureWithID[geojson.Geometry](
+			geojson.NewPoint(13.0473748, 79.9288064),
+			geojson.StringFeatureID("chennai"),
+			geojson.Property{Name: "city", Value: "Chennai"},
+		),
+		geojson.NewFeature[geojson.Geometry](
+			geojson.NewPoint(1, 2),
+		),
+		geojson.NewFeature[geojson.Geometry](
+			nil,
+			geojson.Property{Name: "city", Value: "Unknown"},
+		),
+	)
+
+	data, err := geojson.MarshalGeobufFeatureCollection(collection)
+	require.NoError(t, err)
+
+	unmarshalled, err := geojson.UnmarshalGeobufFeatureCollection(data)
+	require.NoError(t, err)
+	require.Equal(t, collection, unmarshalled)
+}
+
+func TestGeobufMixedDimensions(t *testing.T) {
+	_, err := geojson.MarshalGeobufGeometry(geojson.NewLineString(
+		geojson.MakePosition(12, 34),
+		geojson.MakePositionWithElevation(56, 78, 1),
+	))
+	require.Error(t, err)
+}
@@ -0,0 +1,299 @@
+package geojson
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+)
+
+// GPX properties.
+const (
+	GPXName       = "name"
+	GPXDesc       = "desc"
+	GPXTime       = "time"
+	GPXCoordTimes = "coordTimes"
+)
+
+const (
+	gpxNamespace = "http://www.topografix.com/GPX/1/1"
+	gpxCreator   = "go-geojson"
+)
+
+// ReadGPX reads a GPX document, returning a feature for each waypoint, route and track.
+// Waypoints become Points, routes become LineStrings and tracks become MultiLineStrings, with a line per segment.
+//
+// The name and description of each are stored as the GPXName and GPXDesc properties, and the time of waypoints as GPXTime.
+// The times of route and track points are stored as GPXCoordTimes, in a []time.Time or [][]time.Time respectively,
+// which contain zero values for points without a time.
+func ReadGPX(r io.Reader) (FeatureCollection, error) {
+	var doc gpxDocument
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return FeatureCollection{}, err
+	}
+
+	var features []Feature[Geometry]
+	for _, wpt := range doc.Waypoints {
+		point := NewPointFromPosition(wpt.position())
+		props := gpxProperties(wpt.Name, wpt.Desc)
+		if wpt.Time != nil {
+			props = append(props, Property{Name: GPXTime, Value: *wpt.Time})
+		}
+		features = append(features, NewFeature[Geometry](point, props...))
+	}
+
+	for _, rte := range doc.Routes {
+		line, times := gpxLine(rte.Points)
+		props := gpxProperties(rte.Name, rte.Desc)
+		if times != nil {
+			props = append(props, Property{Name: GPXCoordTimes, Value: times})
+		}
+		features = append(features, NewFeature[Geometry](&LineString{positions: line}, props...))
+	}
+
+	for _, trk := range doc.Tracks {
+		lines := make([][]Position, len(trk.Segments))
+		times := make([][]time.Time, len(trk.Segments))
+		var hasTimes bool
+		for i, seg := range trk.Segments {
+			lines[i], times[i] = gpxLine(seg.Points)
+			hasTimes = hasTimes || times[i] != nil
+		}
+
+		props := gpxProperties(trk.Name, trk.Desc)
+		if hasTimes {
+			for i, seg := range trk.Segments {
+				if times[i] == nil {
+					times[i] = make([]time.Time, len(seg.Points))
+				}
+			}
+			props = append(props, Property{Name: GPXCoordTimes, Value: times})
+		}
+		features = append(features, NewFeature[Geometry](NewMultiLineString(lines...), props...))
+	}
+	return NewFeatureCollection(features...), nil
+}
+
+// WriteGPX writes the features as a GPX 1.1 document.
+// Points are written as waypoints, LineStrings as routes and MultiLineStrings as tracks, with the properties described by ReadGPX.
+// Other properties are discarded, and it is an error for features to have any other type of geometry, or no geometry.
+func WriteGPX(w io.Writer, c FeatureCollection) error {
+	doc := gpxDocument{
+		Namespace: gpxNamespace,
+		Version:   "1.1",
+		Creator:   gpxCreator,
+	}
+
+	for _, f := range c.features {
+		if !f.HasGeometry() {
+			return errNoGeometry
+		}
+
+		name, desc := stringProperty(f.properties, GPXName), stringProperty(f.properties, GPXDesc)
+
+		switch g := f.geometry.(type) {
+		case *Point:
+			wpt := makeGPXPoint(g.pos, time.Time{})
+			wpt.Name, wpt.Desc = name, desc
+			if prop, ok := f.properties.Get(GPXTime); ok {
+				t, err := gpxTime(prop.Value)
+				if err != nil {
+					return fmt.Errorf("property '%s': %w", GPXTime, err)
+				}
+				if !t.IsZero() {
+					wpt.Time = &t
+				}
+			}
+			doc.Waypoints = append(doc.Waypoints, wpt)
+		case *LineString:
+			var times []time.Time
+			if prop, ok := f.properties.Get(GPXCoordTimes); ok {
+				var err error
+				if times, err = gpxTimes(prop.Value); err != nil {
+					return err
+				}
+			}
+
+			doc.Routes = append(doc.Routes, gpxRoute{
+				Name:   name,
+				Desc:   desc,
+				Points: makeGPXPoints(g.positions, times),
+			})
+		case *MultiLineString:
+			var times [][]time.Time
+			if prop, ok := f.properties.Get(GPXCoordTimes); ok {
+				var err error
+				if times, err = gpxTrackTimes(prop.Value); err != nil {
+					return err
+				}
+			}
+
+			trk := gpxTrack{Name: name, Desc: desc}
+			for i, line := range g.lines {
+				var segTimes []time.Time
+				if i < len(times) {
+					segTimes = times[i]
+				}
+				trk.Segments = append(trk.Segments, gpxSegment{Points: makeGPXPoints(line, segTimes)})
+			}
+			doc.Tracks = append(doc.Tracks, trk)
+		default:
+			return fmt.Errorf("unsupported geometry type '%T'", f.geometry)
+		}
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	e := xml.NewEncoder(w)
+	e.Indent("", "  ")
+	if err := e.Encode(&doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+type gpxDocument struct {
+	XMLName   xml.Name   `xml:"gpx"`
+	Namespace string     `xml:"xmlns,attr,omitempty"`
+	Version   string     `xml:"version,attr"`
+	Creator   string     `xml:"creator,attr"`
+	Waypoints []gpxPoint `xml:"wpt"`
+	Routes    []gpxRoute `xml:"rte"`
+	Tracks    []gpxTrack `xml:"trk"`
+}
+
+type gpxPoint struct {
+	Lat  float64    `xml:"lat,attr"`
+	Lon  float64    `xml:"lon,attr"`
+	Ele  *float64   `xml:"ele,omitempty"`
+	Time *time.Time `xml:"time,omitempty"`
+	Name string     `xml:"name,omitempty"`
+	Desc string     `xml:"desc,omitempty"`
+}
+
+type gpxRoute struct {
+	Name   string     `xml:"name,omitempty"`
+	Desc   string     `xml:"desc,omitempty"`
+	Points []gpxPoint `xml:"rtept"`
+}
+
+type gpxTrack struct {
+	Name     string       `xml:"name,omitempty"`
+	Desc     string       `xml:"desc,omitempty"`
+	Segments []gpxSegment `xml:"trkseg"`
+}
+
+type gpxSegment struct {
+	Points []gpxPoint `xml:"trkpt"`
+}
+
+func (p gpxPoint) position() Position {
+	if p.Ele != nil {
+		return MakePositionWithElevation(p.Lat, p.Lon, *p.Ele)
+	}
+	return MakePosition(p.Lat, p.Lon)
+}
+
+func makeGPXPoint(pos Position, t time.Time) gpxPoint {
+	p := gpxPoint{
+		Lat: pos.lat,
+		Lon: pos.lng,
+		Ele: pos.elevation,
+	}
+	if !t.IsZero() {
+		p.Time = &t
+	}
+	return p
+}
+
+func makeGPXPoints(positions []Position, times []time.Time) []gpxPoint {
+	points := make([]gpxPoint, len(positions))
+	for i, pos := range positions {
+		var t time.Time
+		if i < len(times) {
+			t = times[i]
+		}
+		points[i] = makeGPXPoint(pos, t)
+	}
+	return points
+}
+
+// gpxLine returns the positions of the points, and their times if any point has a time.
+func gpxLine(points []gpxPoint) ([]Position, []time.Time) {
+	positions := make([]Position, len(points))
+	var times []time.Time
+	for i, p := range points {
+		positions[i] = p.position()
+		if p.Time != nil {
+			if times == nil {
+				times = make([]time.Time, len(points))
+			}
+			times[i] = *p.Time
+		}
+	}
+	return positions, times
+}
+
+func gpxProperties(name, desc string) PropertyList {
+	var props PropertyList
+	if name != "" {
+		props = append(props, Property{Name: GPXName, Value: name})
+	}
+	if desc != "" {
+		props = append(props, Property{Name: GPXDesc, Value: desc})
+	}
+	return props
+}
+
+// gpxTime accepts time.Time values and RFC 3339 strings, which are produced when unmarshalling GeoJSON.
+func gpxTime(value interface{}) (time.Time, error) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, nil
+	case string:
+		return time.Parse(time.RFC3339, v)
+	default:
+		return time.Time{}, fmt.Errorf("cannot use %T as a time", value)
+	}
+}
+
+func gpxTimes(value interface{}) ([]time.Time, error) {
+	switch v := value.(type) {
+	case []time.Time:
+		return v, nil
+	case []interface{}:
+		times := make([]time.Time, len(v))
+		for i, value := range v {
+			t, err := gpxTime(value)
+			if err != nil {
+				return nil, fmt.Errorf("property '%s': %w", GPXCoordTimes, err)
+			}
+			times[i] = t
+		}
+		return times, nil
+	default:
+		return nil, fmt.Errorf("property '%s': cannot use %T as a list of times", GPXCoordTimes, value)
+	}
+}
+
+func gpxTrackTimes(value interface{}) ([][]time.Time, error) {
+	switch v := value.(type) {
+	case [][]time.Time:
+		return v, nil
+	case []interface{}:
+		times := make([][]time.Time, len(v))
+		for i, value := range v {
+			segTimes, err := gpxTimes(value)
+			if err != nil {
+				return nil, err
+			}
+			times[i] = segTimes
+		}
+		return times, nil
+	default:
+		return nil, fmt.Errorf("property '%s': cannot use %T as a list of times for each segment", GPXCoordTimes, value)
+	}
+}
@@ -0,0 +1,137 @@
+package geojson_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	geojson "github.com/everystreet/go-geojson/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadGPX(t *testing.T) {
+	c, err := geojson.ReadGPX(strings.NewReader(`<?xml version="1.0" encoding="UTF-8"?>
+<gpx xmlns="http://www.topografix.com/GPX/1/1" version="1.1" creator="test">
+  <wpt lat="51.5" lon="-0.1">
+    <ele>11.5</ele>
+    <time>2021-06-01T10:00:00Z</time>
+    <name>Camp</name>
+    <desc>Base camp</desc>
+  </wpt>
+  <rte>
+    <name>Route</name>
+    <rtept lat="1" lon="2"></rtept>
+    <rtept lat="3" lon="4"></rtept>
+  </rte>
+  <trk>
+    <name>Track</name>
+    <trkseg>
+      <trkpt lat="1" lon="2"><ele>10</ele><time>2021-06-01T10:00:00Z</time></trkpt>
+      <trkpt lat="3" lon="4"><ele>20</ele></trkpt>
+    </trkseg>
+    <trkseg>
+      <trkpt lat="5" lon="6"><ele>30</ele><time>2021-06-01T11:00:00Z</time></trkpt>
+    </trkseg>
+  </trk>
+</gpx>`))
+	require.NoError(t, err)
+
+	t1 := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+	t2 := time.Date(2021, 6, 1, 11, 0, 0, 0, time.UTC)
+
+	require.Equal(t, geojson.NewFeatureCollection(
+		geojson.NewFeature[geojson.Geometry](
+			geojson.NewPointWithElevation(51.5, -0.1, 11.5),
+			geojson.Property{Name: geojson.GPXName, Value: "Camp"},
+			geojson.Property{Name: geojson.GPXDesc, Value: "Base camp"},
+			geojson.Property{Name: geojson.GPXTime, Value: t1},
+		),
+		geojson.NewFeature[geojson.Geometry](
+			geojson.NewLineString(
+				geojson.MakePosition(1, 2),
+				geojson.MakePosition(3, 4),
+			),
+			geojson.Property{Name: geojson.GPXName, Value: "Route"},
+		),
+		geojson.NewFeature[geojson.Geometry](
+			geojson.NewMultiLineString(
+				[]geojson.Position{
+					geojson.MakePositionWithElevation(1, 2, 10),
+					geojson.MakePositionWithElevation(3, 4, 20),
+				},
+				[]geojson.Position{
+					geojson.MakePositionWithElevation(5, 6, 30),
+				},
+			),
+			geojson.Property{Name: geojson.GPXName, Value: "Track"},
+			geojson.Property{Name: geojson.GPXCoordTimes, Value: [][]time.Time{{t1, {}}, {t2}}},
+		),
+	), c)
+
+	var buf bytes.Buffer
+	require.NoError(t, geojson.WriteGPX(&buf, c))
+
+	written, err := geojson.ReadGPX(&buf)
+	require.NoError(t, err)
+	require.Equal(t, c, written)
+}
+
+func TestWriteGPX(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, geojson.WriteGPX(&buf, geojson.NewFeatureCollection(
+		geojson.NewFeature[geojson.Geometry](
+			geojson.NewPoint(51.5, -0.1),
+			geojson.Property{Name: geojson.GPXName, Value: "Camp"},
+			geojson.Property{Name: geojson.GPXTime, Value: "2021-06-01T10:00:00Z"},
+			geojson.Property{Name: "ignored", Value: 1},
+		),
+		geojson.NewFeature[geojson.Geometry](
+			geojson.NewLineString(
+				geojson.MakePosition(1, 2),
+				geojson.MakePositionWithElevation(3, 4, 5),
+			),
+			geojson.Property{Name: geojson.GPXCoordTimes, Value: []interface{}{"2021-06-01T10:00:00Z", "0001-01-01T00:00:00Z"}},
+		),
+	)))
+	require.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<gpx xmlns="http://www.topografix.com/GPX/1/1" version="1.1" creator="go-geojson">
+  <wpt lat="51.5" lon="-0.1">
+    <time>2021-06-01T10:00:00Z</time>
+    <name>Camp</name>
+  </wpt>
+  <rte>
+    <rtept lat="1" lon="2">
+      <time>2021-06-01T10:00:00Z</time>
+    </rtept>
+    <rtept lat="3" lon="4">
+      <ele>5</ele>
+    </rtept>
+  </rte>
+</gpx>
+`, buf.String())
+}
+
+func TestWriteGPXInvalid(t *testing.T) {
+	for name, f := range map[string]geojson.Feature[geojson.Geometry]{
+		"unsupported geometry": geojson.NewFeature[geojson.Geometry](geojson.NewPolygon([]geojson.Position{
+			geojson.MakePosition(0, 0),
+			geojson.MakePosition(1, 1),
+			geojson.MakePosition(1, 0),
+			geojson.MakePosition(0, 0),
+		})),
+		"no geometry": geojson.NewFeature[geojson.Geometry](nil),
+		"invalid time": geojson.NewFeature[geojson.Geometry](
+			geojson.NewPoint(1, 2),
+			geojson.Property{Name: geojson.GPXTime, Value: "yesterday"},
+		),
+		"invalid coord times": geojson.NewFeature[geojson.Geometry](
+			geojson.NewMultiLineString([]geojson.Position{geojson.MakePosition(1, 2)}),
+			geojson.Property{Name: geojson.GPXCoordTimes, Value: []time.Time{{}}},
+		),
+	} {
+		t.Run(name, func(t *testing.T) {
+			require.Error(t, geojson.WriteGPX(&bytes.Buffer{}, geojson.NewFeatureCollection(f)))
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package geojson
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// FeatureID identifies a Feature, and is either a string or a number.
+// Numbers are stored as they appear in JSON, so that large integers aren't rounded.
+type FeatureID struct {
+	value  string
+	number bool
+}
+
+// StringFeatureID returns a FeatureID that is a string.
+func StringFeatureID(s string) FeatureID {
+	return FeatureID{value: s}
+}
+
+// IntFeatureID returns a FeatureID that is an integer.
+func IntFeatureID(v int64) FeatureID {
+	return FeatureID{value: strconv.FormatInt(v, 10), number: true}
+}
+
+// UintFeatureID returns a FeatureID that is an unsigned integer.
+func UintFeatureID(v uint64) FeatureID {
+	return FeatureID{value: strconv.FormatUint(v, 10), number: true}
+}
+
+// FloatFeatureID returns a FeatureID that is a number, which must be finite.
+func FloatFeatureID(v float64) FeatureID {
+	return FeatureID{value: strconv.FormatFloat(v, 'g', -1, 64), number: true}
+}
+
+// IsNumber reports whether the ID is a number rather than a string.
+func (id FeatureID) IsNumber() bool {
+	return id.number
+}
+
+// String returns the ID if it is a string, or the JSON encoding of the number.
+func (id FeatureID) String() string {
+	return id.value
+}
+
+// Int64 returns the ID if it is an integer that fits in an int64.
+func (id FeatureID) Int64() (int64, bool) {
+	if !id.number {
+		return 0, false
+	}
+	v, err := strconv.ParseInt(id.value, 10, 64)
+	return v, err == nil
+}
+
+// Uint64 returns the ID if it is a non-negative integer that fits in a uint64.
+func (id FeatureID) Uint64() (uint64, bool) {
+	if !id.number {
+		return 0, false
+	}
+	v, err := strconv.ParseUint(id.value, 10, 64)
+	return v, err == nil
+}
+
+// Float64 returns the ID if it is a number, which may have been rounded.
+func (id FeatureID) Float64() (float64, bool) {
+	if !id.number {
+		return 0, false
+	}
+	v, err := strconv.ParseFloat(id.value, 64)
+	return v, err == nil
+}
+
+// MarshalJSON returns the JSON encoding of the FeatureID.
+func (id FeatureID) MarshalJSON() ([]byte, error) {
+	if !id.number {
+		return json.Marshal(id.value)
+	}
+
+	var n json.Number
+	if err := json.Unmarshal([]byte(id.value), &n); err != nil {
+		return nil, fmt.Errorf("invalid numeric id '%s'", id.value)
+	}
+	return []byte(id.value), nil
+}
+
+// UnmarshalJSON parses the JSON-encoded data and stores the result.
+func (id *FeatureID) UnmarshalJSON(data []byte) error {
+	var v interface{}
+	d := json.NewDecoder(bytes.NewReader(data))
+	d.UseNumber()
+	if err := d.Decode(&v); err != nil {
+		return err
+	}
+
+	switch v := v.(type) {
+	case string:
+		*id = StringFeatureID(v)
+	case json.Number:
+		*id = FeatureID{value: v.String(), number: true}
+	default:
+		return fmt.Errorf("id must be a string or number")
+	}
+	return nil
+}
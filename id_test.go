@@ -0,0 +1,87 @@
+package geojson_test
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+
+	geojson "github.com/everystreet/go-geojson/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFeatureID(t *testing.T) {
+	tests := []struct {
+		name string
+		id   geojson.FeatureID
+		json string
+	}{
+		{
+			name: "string",
+			id:   geojson.StringFeatureID("123"),
+			json: `"123"`,
+		},
+		{
+			name: "int",
+			id:   geojson.IntFeatureID(-9007199254740993),
+			json: `-9007199254740993`,
+		},
+		{
+			name: "uint",
+			id:   geojson.UintFeatureID(18446744073709551615),
+			json: `18446744073709551615`,
+		},
+		{
+			name: "float",
+			id:   geojson.FloatFeatureID(1.5),
+			json: `1.5`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := json.Marshal(tt.id)
+			require.NoError(t, err)
+			require.Equal(t, tt.json, string(data))
+
+			var id geojson.FeatureID
+			require.NoError(t, json.Unmarshal(data, &id))
+			require.Equal(t, tt.id, id)
+		})
+	}
+}
+
+func TestFeatureIDAccessors(t *testing.T) {
+	id := geojson.IntFeatureID(-9007199254740993)
+	require.True(t, id.IsNumber())
+	require.Equal(t, "-9007199254740993", id.String())
+
+	i, ok := id.Int64()
+	require.True(t, ok)
+	require.Equal(t, int64(-9007199254740993), i)
+
+	_, ok = id.Uint64()
+	require.False(t, ok)
+
+	f, ok := id.Float64()
+	require.True(t, ok)
+	require.Equal(t, -9007199254740992.0, f)
+
+	id = geojson.StringFeatureID("1")
+	require.False(t, id.IsNumber())
+	require.Equal(t, "1", id.String())
+
+	_, ok = id.Int64()
+	require.False(t, ok)
+
+	var unmarshalled geojson.FeatureID
+	require.NoError(t, json.Unmarshal([]byte(`1e3`), &unmarshalled))
+	_, ok = unmarshalled.Int64()
+	require.False(t, ok)
+
+	f, ok = unmarshalled.Float64()
+	require.True(t, ok)
+	require.Equal(t, 1000.0, f)
+
+	_, err := json.Marshal(geojson.FloatFeatureID(math.NaN()))
+	require.Error(t, err)
+}
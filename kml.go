@@ -0,0 +1,460 @@
+package geojson
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// KML properties.
+const (
+	KMLName        = "name"
+	KMLDescription = "description"
+	KMLFolder      = "folder"
+)
+
+const kmlNamespace = "http://www.opengis.net/kml/2.2"
+
+// ReadKML reads a KML document, returning a feature for each Placemark.
+// Point, LineString, LinearRing and Polygon geometries are supported, as is MultiGeometry,
+// which becomes a MultiPoint, MultiLineString or MultiPolygon if all of its geometries are of the corresponding type,
+// and a GeometryCollection otherwise. Placemarks without a supported geometry become features without a geometry.
+//
+// The name and description of each Placemark are stored as the KMLName and KMLDescription properties,
+// and the names of the Folders that contain it as KMLFolder, in a []string starting with the outermost Folder.
+// ExtendedData is stored as string properties.
+func ReadKML(r io.Reader) (FeatureCollection, error) {
+	var doc kmlDocument
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return FeatureCollection{}, err
+	}
+
+	var features []Feature[Geometry]
+	if err := readKMLFeatures(doc.Children, nil, &features); err != nil {
+		return FeatureCollection{}, err
+	}
+	return NewFeatureCollection(features...), nil
+}
+
+// WriteKML writes the features as Placemarks in a KML 2.2 document, with the properties described by ReadKML.
+// Features are written inside nested Folders according to their KMLFolder property, and other properties are written as ExtendedData,
+// with values that are not strings encoded as JSON.
+func WriteKML(w io.Writer, c FeatureCollection) error {
+	root := kmlNode{XMLName: xml.Name{Local: "Document"}}
+	for _, f := range c.features {
+		folder, err := kmlFolderPath(f.properties)
+		if err != nil {
+			return err
+		}
+
+		var children []kmlNode
+		if f.HasGeometry() {
+			geo, err := makeKMLGeometry(f.geometry)
+			if err != nil {
+				return err
+			}
+			children = append(children, geo)
+		}
+
+		data, err := makeKMLExtendedData(f.properties)
+		if err != nil {
+			return err
+		}
+
+		parent := kmlFolder(&root, folder)
+		parent.Children = append(parent.Children, kmlNode{
+			XMLName:      xml.Name{Local: "Placemark"},
+			Name:         stringProperty(f.properties, KMLName),
+			Description:  stringProperty(f.properties, KMLDescription),
+			ExtendedData: data,
+			Children:     children,
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+
+	e := xml.NewEncoder(w)
+	e.Indent("", "  ")
+	if err := e.Encode(&kmlDocument{
+		Namespace: kmlNamespace,
+		Children:  []kmlNode{root},
+	}); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
+
+type kmlDocument struct {
+	XMLName   xml.Name  `xml:"kml"`
+	Namespace string    `xml:"xmlns,attr,omitempty"`
+	Children  []kmlNode `xml:",any"`
+}
+
+// kmlNode is any KML element, such as a container, Placemark or geometry.
+// Child elements without a corresponding field are stored in order in Children.
+type kmlNode struct {
+	XMLName      xml.Name
+	Name         string           `xml:"name,omitempty"`
+	Description  string           `xml:"description,omitempty"`
+	ExtendedData *kmlExtendedData `xml:"ExtendedData"`
+	Coordinates  string           `xml:"coordinates,omitempty"`
+	Outer        *kmlBoundary     `xml:"outerBoundaryIs"`
+	Inner        []kmlBoundary    `xml:"innerBoundaryIs"`
+	Children     []kmlNode        `xml:",any"`
+}
+
+type kmlBoundary struct {
+	Rings []kmlRing `xml:"LinearRing"`
+}
+
+type kmlRing struct {
+	Coordinates string `xml:"coordinates"`
+}
+
+type kmlExtendedData struct {
+	Data       []kmlData       `xml:"Data"`
+	SchemaData []kmlSchemaData `xml:"SchemaData"`
+}
+
+type kmlData struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:"value"`
+}
+
+type kmlSchemaData struct {
+	SimpleData []kmlSimpleData `xml:"SimpleData"`
+}
+
+type kmlSimpleData struct {
+	Name  string `xml:"name,attr"`
+	Value string `xml:",chardata"`
+}
+
+func readKMLFeatures(nodes []kmlNode, folder []string, features *[]Feature[Geometry]) error {
+	for _, n := range nodes {
+		switch n.XMLName.Local {
+		case "Document":
+			if err := readKMLFeatures(n.Children, folder, features); err != nil {
+				return err
+			}
+		case "Folder":
+			path := append(append([]string{}, folder...), n.Name)
+			if err := readKMLFeatures(n.Children, path, features); err != nil {
+				return err
+			}
+		case "Placemark":
+			f, err := n.feature(folder)
+			if err != nil {
+				return err
+			}
+			*features = append(*features, f)
+		}
+	}
+	return nil
+}
+
+func (n kmlNode) feature(folder []string) (Feature[Geometry], error) {
+	var geo Geometry
+	for _, child := range n.Children {
+		var err error
+		if geo, err = child.geometry(); err != nil {
+			return Feature[Geometry]{}, err
+		} else if geo != nil {
+			break
+		}
+	}
+
+	var props []Property
+	if n.Name != "" {
+		props = append(props, Property{Name: KMLName, Value: n.Name})
+	}
+	if n.Description != "" {
+		props = append(props, Property{Name: KMLDescription, Value: n.Description})
+	}
+	if len(folder) != 0 {
+		props = append(props, Property{Name: KMLFolder, Value: folder})
+	}
+
+	if n.ExtendedData != nil {
+		for _, data := range n.ExtendedData.Data {
+			props = append(props, Property{Name: data.Name, Value: data.Value})
+		}
+		for _, schemaData := range n.ExtendedData.SchemaData {
+			for _, data := range schemaData.SimpleData {
+				props = append(props, Property{Name: data.Name, Value: data.Value})
+			}
+		}
+	}
+	return NewFeature(geo, props...), nil
+}
+
+// geometry returns the geometry represented by the node, or nil if it isn't a supported geometry.
+func (n kmlNode) geometry() (Geometry, error) {
+	switch n.XMLName.Local {
+	case "Point":
+		positions, err := parseKMLCoordinates(n.Coordinates)
+		if err != nil {
+			return nil, err
+		} else if len(positions) != 1 {
+			return nil, fmt.Errorf("point must have exactly 1 position, but has %d", len(positions))
+		}
+		return NewPointFromPosition(positions[0]), nil
+	case "LineString", "LinearRing":
+		positions, err := parseKMLCoordinates(n.Coordinates)
+		if err != nil {
+			return nil, err
+		}
+		return &LineString{positions: positions}, nil
+	case "Polygon":
+		var boundaries []kmlBoundary
+		if n.Outer != nil {
+			boundaries = append(boundaries, *n.Outer)
+		}
+		boundaries = append(boundaries, n.Inner...)
+
+		var rings [][]Position
+		for _, boundary := range boundaries {
+			for _, ring := range boundary.Rings {
+				positions, err := parseKMLCoordinates(ring.Coordinates)
+				if err != nil {
+					return nil, err
+				}
+				rings = append(rings, positions)
+			}
+		}
+		return NewPolygon(rings...), nil
+	case "MultiGeometry":
+		var geometries []Geometry
+		for _, child := range n.Children {
+			geo, err := child.geometry()
+			if err != nil {
+				return nil, err
+			} else if geo != nil {
+				geometries = append(geometries, geo)
+			}
+		}
+		return makeKMLMultiGeometry(geometries), nil
+	default:
+		return nil, nil
+	}
+}
+
+// makeKMLMultiGeometry returns the most specific geometry that can represent a MultiGeometry.
+func makeKMLMultiGeometry(geometries []Geometry) Geometry {
+	if len(geometries) == 0 {
+		return NewGeometryCollection()
+	}
+
+	switch geometries[0].(type) {
+	case *Point:
+		positions := make([]Position, len(geometries))
+		for i, geo := range geometries {
+			point, ok := geo.(*Point)
+			if !ok {
+				return NewGeometryCollection(geometries...)
+			}
+			positions[i] = point.pos
+		}
+		return NewMultiPoint(positions...)
+	case *LineString:
+		lines := make([][]Position, len(geometries))
+		for i, geo := range geometries {
+			line, ok := geo.(*LineString)
+			if !ok {
+				return NewGeometryCollection(geometries...)
+			}
+			lines[i] = line.positions
+		}
+		return NewMultiLineString(lines...)
+	case *Polygon:
+		polygons := make([][][]Position, len(geometries))
+		for i, geo := range geometries {
+			polygon, ok := geo.(*Polygon)
+			if !ok {
+				return NewGeometryCollection(geometries...)
+			}
+			polygons[i] = polygon.rings
+		}
+		return NewMultiPolygon(polygons...)
+	default:
+		return NewGeometryCollection(geometries...)
+	}
+}
+
+func parseKMLCoordinates(s string) ([]Position, error) {
+	tuples := strings.Fields(s)
+	positions := make([]Position, len(tuples))
+	for i, tuple := range tuples {
+		values := strings.Split(tuple, ",")
+		if len(values) != 2 && len(values) != 3 {
+			return nil, fmt.Errorf("invalid coordinates '%s'", tuple)
+		}
+
+		var ordinates [3]float64
+		for j, v := range values {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				return nil, fmt.Errorf("invalid coordinates '%s': %w", tuple, err)
+			}
+			ordinates[j] = f
+		}
+
+		if len(values) == 3 {
+			positions[i] = MakePositionWithElevation(ordinates[1], ordinates[0], ordinates[2])
+		} else {
+			positions[i] = MakePosition(ordinates[1], ordinates[0])
+		}
+	}
+	return positions, nil
+}
+
+func formatKMLCoordinates(positions []Position) string {
+	tuples := make([]string, len(positions))
+	for i, pos := range positions {
+		tuple := strconv.FormatFloat(pos.lng, 'f', -1, 64) + "," + strconv.FormatFloat(pos.lat, 'f', -1, 64)
+		if pos.elevation != nil {
+			tuple += "," + strconv.FormatFloat(*pos.elevation, 'f', -1, 64)
+		}
+		tuples[i] = tuple
+	}
+	return strings.Join(tuples, " ")
+}
+
+func makeKMLGeometry(geo Geometry) (kmlNode, error) {
+	switch g := geo.(type) {
+	case *Point:
+		return kmlNode{
+			XMLName:     xml.Name{Local: "Point"},
+			Coordinates: formatKMLCoordinates([]Position{g.pos}),
+		}, nil
+	case *MultiPoint:
+		geometries := make([]Geometry, len(g.positions))
+		for i := range g.positions {
+			geometries[i] = NewPointFromPosition(g.positions[i])
+		}
+		return makeKMLMultiGeometryNode(geometries)
+	case *LineString:
+		return kmlNode{
+			XMLName:     xml.Name{Local: "LineString"},
+			Coordinates: formatKMLCoordinates(g.positions),
+		}, nil
+	case *MultiLineString:
+		geometries := make([]Geometry, len(g.lines))
+		for i := range g.lines {
+			geometries[i] = &LineString{positions: g.lines[i]}
+		}
+		return makeKMLMultiGeometryNode(geometries)
+	case *Polygon:
+		n := kmlNode{XMLName: xml.Name{Local: "Polygon"}}
+		for i, ring := range g.rings {
+			boundary := kmlBoundary{Rings: []kmlRing{{Coordinates: formatKMLCoordinates(ring)}}}
+			if i == 0 {
+				n.Outer = &boundary
+			} else {
+				n.Inner = append(n.Inner, boundary)
+			}
+		}
+		return n, nil
+	case *MultiPolygon:
+		geometries := make([]Geometry, len(g.polygons))
+		for i := range g.polygons {
+			geometries[i] = NewPolygon(g.polygons[i]...)
+		}
+		return makeKMLMultiGeometryNode(geometries)
+	case *GeometryCollection:
+		return makeKMLMultiGeometryNode(g.geometries)
+	default:
+		return kmlNode{}, fmt.Errorf("unsupported geometry type '%T'", geo)
+	}
+}
+
+func makeKMLMultiGeometryNode(geometries []Geometry) (kmlNode, error) {
+	n := kmlNode{XMLName: xml.Name{Local: "MultiGeometry"}}
+	for _, geo := range geometries {
+		child, err := makeKMLGeometry(geo)
+		if err != nil {
+			return kmlNode{}, err
+		}
+		n.Children = append(n.Children, child)
+	}
+	return n, nil
+}
+
+func makeKMLExtendedData(props PropertyList) (*kmlExtendedData, error) {
+	var data []kmlData
+	for _, prop := range props {
+		switch prop.Name {
+		case KMLName, KMLDescription, KMLFolder:
+			continue
+		}
+
+		switch v := prop.Value.(type) {
+		case nil:
+		case string:
+			data = append(data, kmlData{Name: prop.Name, Value: v})
+		default:
+			value, err := json.Marshal(v)
+			if err != nil {
+				return nil, fmt.Errorf("property '%s': %w", prop.Name, err)
+			}
+			data = append(data, kmlData{Name: prop.Name, Value: string(value)})
+		}
+	}
+
+	if data == nil {
+		return nil, nil
+	}
+	return &kmlExtendedData{Data: data}, nil
+}
+
+// kmlFolderPath returns the folder names in the KMLFolder property, which may also be a []interface{} as produced when unmarshalling GeoJSON.
+func kmlFolderPath(props PropertyList) ([]string, error) {
+	prop, ok := props.Get(KMLFolder)
+	if !ok {
+		return nil, nil
+	}
+
+	switch v := prop.Value.(type) {
+	case []string:
+		return v, nil
+	case []interface{}:
+		path := make([]string, len(v))
+		for i, name := range v {
+			s, ok := name.(string)
+			if !ok {
+				return nil, fmt.Errorf("property '%s': cannot use %T as a folder name", KMLFolder, name)
+			}
+			path[i] = s
+		}
+		return path, nil
+	default:
+		return nil, fmt.Errorf("property '%s': cannot use %T as a list of folder names", KMLFolder, prop.Value)
+	}
+}
+
+// kmlFolder returns the Folder with the specified path, creating any Folders that don't already exist.
+func kmlFolder(parent *kmlNode, path []string) *kmlNode {
+	for _, name := range path {
+		i := 0
+		for ; i < len(parent.Children); i++ {
+			if child := parent.Children[i]; child.XMLName.Local == "Folder" && child.Name == name {
+				break
+			}
+		}
+
+		if i == len(parent.Children) {
+			parent.Children = append(parent.Children, kmlNode{
+				XMLName: xml.Name{Local: "Folder"},
+				Name:    name,
+			})
+		}
+		parent = &parent.Children[i]
+	}
+	return parent
+}
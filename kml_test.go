@@ -0,0 +1,196 @@
+package geojson_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	geojson "github.com/everystreet/go-geojson/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadKML(t *testing.T) {
+	c, err := geojson.ReadKML(strings.NewReader(`<?xml version="1.0" encoding="UTF-8"?>
+<kml xmlns="http://www.opengis.net/kml/2.2">
+  <Document>
+    <name>Survey</name>
+    <Style id="red"><LineStyle><color>ff0000ff</color></LineStyle></Style>
+    <Placemark>
+      <name>Office</name>
+      <description><![CDATA[<b>Head</b> office]]></description>
+      <Point><coordinates>-0.1,51.5,12</coordinates></Point>
+    </Placemark>
+    <Folder>
+      <name>Sites</name>
+      <Folder>
+        <name>North</name>
+        <Placemark>
+          <name>Field</name>
+          <ExtendedData>
+            <Data name="crop"><value>wheat</value></Data>
+            <SchemaData schemaUrl="#site"><SimpleData name="area">12.5</SimpleData></SchemaData>
+          </ExtendedData>
+          <Polygon>
+            <outerBoundaryIs><LinearRing><coordinates>
+              0,0 0,10 10,10 10,0 0,0
+            </coordinates></LinearRing></outerBoundaryIs>
+            <innerBoundaryIs><LinearRing><coordinates>
+              2,2 8,2 8,8 2,8 2,2
+            </coordinates></LinearRing></innerBoundaryIs>
+          </Polygon>
+        </Placemark>
+      </Folder>
+      <Placemark>
+        <MultiGeometry>
+          <LineString><coordinates>1,2 3,4</coordinates></LineString>
+          <LineString><coordinates>5,6 7,8</coordinates></LineString>
+        </MultiGeometry>
+      </Placemark>
+    </Folder>
+    <Placemark>
+      <MultiGeometry>
+        <Point><coordinates>1,2</coordinates></Point>
+        <LineString><coordinates>1,2 3,4</coordinates></LineString>
+      </MultiGeometry>
+    </Placemark>
+    <Placemark>
+      <name>Unlocated</name>
+    </Placemark>
+  </Document>
+</kml>`))
+	require.NoError(t, err)
+
+	require.Equal(t, geojson.NewFeatureCollection(
+		geojson.NewFeature[geojson.Geometry](
+			geojson.NewPointWithElevation(51.5, -0.1, 12),
+			geojson.Property{Name: geojson.KMLName, Value: "Office"},
+			geojson.Property{Name: geojson.KMLDescription, Value: "<b>Head</b> office"},
+		),
+		geojson.NewFeature[geojson.Geometry](
+			geojson.NewPolygon(
+				[]geojson.Position{
+					geojson.MakePosition(0, 0),
+					geojson.MakePosition(10, 0),
+					geojson.MakePosition(10, 10),
+					geojson.MakePosition(0, 10),
+					geojson.MakePosition(0, 0),
+				},
+				[]geojson.Position{
+					geojson.MakePosition(2, 2),
+					geojson.MakePosition(2, 8),
+					geojson.MakePosition(8, 8),
+					geojson.MakePosition(8, 2),
+					geojson.MakePosition(2, 2),
+				},
+			),
+			geojson.Property{Name: geojson.KMLName, Value: "Field"},
+			geojson.Property{Name: geojson.KMLFolder, Value: []string{"Sites", "North"}},
+			geojson.Property{Name: "crop", Value: "wheat"},
+			geojson.Property{Name: "area", Value: "12.5"},
+		),
+		geojson.NewFeature[geojson.Geometry](
+			geojson.NewMultiLineString(
+				[]geojson.Position{
+					geojson.MakePosition(2, 1),
+					geojson.MakePosition(4, 3),
+				},
+				[]geojson.Position{
+					geojson.MakePosition(6, 5),
+					geojson.MakePosition(8, 7),
+				},
+			),
+			geojson.Property{Name: geojson.KMLFolder, Value: []string{"Sites"}},
+		),
+		geojson.NewFeature[geojson.Geometry](
+			geojson.NewGeometryCollection(
+				geojson.NewPoint(2, 1),
+				geojson.NewLineString(
+					geojson.MakePosition(2, 1),
+					geojson.MakePosition(4, 3),
+				),
+			),
+		),
+		geojson.NewFeature[geojson.Geometry](
+			nil,
+			geojson.Property{Name: geojson.KMLName, Value: "Unlocated"},
+		),
+	), c)
+
+	var buf bytes.Buffer
+	require.NoError(t, geojson.WriteKML(&buf, c))
+
+	written, err := geojson.ReadKML(&buf)
+	require.NoError(t, err)
+	require.Equal(t, c, written)
+}
+
+func TestWriteKML(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, geojson.WriteKML(&buf, geojson.NewFeatureCollection(
+		geojson.NewFeature[geojson.Geometry](
+			geojson.NewPoint(51.5, -0.1),
+			geojson.Property{Name: geojson.KMLName, Value: "Office"},
+			geojson.Property{Name: geojson.KMLFolder, Value: []interface{}{"Sites"}},
+			geojson.Property{Name: "floors", Value: 3},
+			geojson.Property{Name: "empty", Value: nil},
+		),
+		geojson.NewFeature[geojson.Geometry](
+			geojson.NewMultiPoint(
+				geojson.MakePosition(1, 2),
+				geojson.MakePositionWithElevation(3, 4, 5),
+			),
+		),
+	)))
+	require.Equal(t, `<?xml version="1.0" encoding="UTF-8"?>
+<kml xmlns="http://www.opengis.net/kml/2.2">
+  <Document>
+    <Folder>
+      <name>Sites</name>
+      <Placemark>
+        <name>Office</name>
+        <ExtendedData>
+          <Data name="floors">
+            <value>3</value>
+          </Data>
+        </ExtendedData>
+        <Point>
+          <coordinates>-0.1,51.5</coordinates>
+        </Point>
+      </Placemark>
+    </Folder>
+    <Placemark>
+      <MultiGeometry>
+        <Point>
+          <coordinates>2,1</coordinates>
+        </Point>
+        <Point>
+          <coordinates>4,3,5</coordinates>
+        </Point>
+      </MultiGeometry>
+    </Placemark>
+  </Document>
+</kml>
+`, buf.String())
+}
+
+func TestReadKMLInvalid(t *testing.T) {
+	for name, placemark := range map[string]string{
+		"invalid coordinates": `<Placemark><Point><coordinates>1;2</coordinates></Point></Placemark>`,
+		"multiple positions":  `<Placemark><Point><coordinates>1,2 3,4</coordinates></Point></Placemark>`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := geojson.ReadKML(strings.NewReader(`<kml xmlns="http://www.opengis.net/kml/2.2">` + placemark + `</kml>`))
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestWriteKMLInvalid(t *testing.T) {
+	err := geojson.WriteKML(&bytes.Buffer{}, geojson.NewFeatureCollection(
+		geojson.NewFeature[geojson.Geometry](
+			geojson.NewPoint(1, 2),
+			geojson.Property{Name: geojson.KMLFolder, Value: "Sites"},
+		),
+	))
+	require.Error(t, err)
+}
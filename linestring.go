@@ -5,13 +5,15 @@ import (
 	"fmt"
 )
 
-// LineString is a set of two or more Positions.
-type LineString []Position
+// LineString is a set of two or more Positions, or none if it is empty.
+type LineString struct {
+	positions []Position
+	foreign   map[string]json.RawMessage
+}
 
 // NewLineString returns a LineString from the supplied positions.
 func NewLineString(pos1, pos2 Position, others ...Position) *LineString {
-	all := append([]Position{pos1, pos2}, others...)
-	return (*LineString)(&all)
+	return &LineString{positions: append([]Position{pos1, pos2}, others...)}
 }
 
 // Type returns the geometry type.
@@ -19,20 +21,39 @@ func (l LineString) Type() GeometryType {
 	return LineStringGeometryType
 }
 
+// Positions returns the positions of the line.
+func (l LineString) Positions() []Position {
+	return l.positions
+}
+
+// ForeignMembers returns a copy of the members of the line that aren't defined by RFC 7946, or nil if there are none.
+func (l LineString) ForeignMembers() map[string]json.RawMessage {
+	return copyForeignMembers(l.foreign)
+}
+
+// WithForeignMember returns a copy of l with the supplied foreign member, which replaces any existing member with the same name.
+// The name must not be one of the members defined by RFC 7946 for a geometry.
+func (l LineString) WithForeignMember(name string, value json.RawMessage) *LineString {
+	l.foreign = withForeignMember(l.foreign, name, value)
+	return &l
+}
+
 // Validate the LineString.
 func (l LineString) Validate() error {
-	if len(l) < 2 {
+	if len(l.positions) == 1 {
 		return errLineStringTooShort
 	}
 	return nil
 }
 
 // MarshalJSON returns the JSON encoding of the LineString.
+// Foreign members follow the members defined by RFC 7946, sorted by name.
 func (l LineString) MarshalJSON() ([]byte, error) {
-	return json.Marshal(geometry{
-		Type:        LineStringGeometryType,
-		Coordinates: []Position(l),
-	})
+	positions := l.positions
+	if positions == nil {
+		positions = []Position{}
+	}
+	return marshalGeometry(LineStringGeometryType, positions, l.foreign)
 }
 
 // UnmarshalJSON parses the JSON-encoded data and stores the result.
@@ -45,16 +66,25 @@ func (l *LineString) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
-	*l = LineString(geo.Coordinates)
+	foreign, err := unmarshalForeignMembers(data, geometryMembers)
+	if err != nil {
+		return err
+	}
+
+	*l = LineString{positions: geo.Coordinates, foreign: foreign}
 	return nil
 }
 
-// MultiLineString is a set of LineStrings.
-type MultiLineString [][]Position
+// MultiLineString is a set of LineStrings, which is empty if there are none.
+// Each LineString must contain at least 2 positions.
+type MultiLineString struct {
+	lines   [][]Position
+	foreign map[string]json.RawMessage
+}
 
 // NewMultiLineString returns a new MultiLineString from the supplied position "strings".
 func NewMultiLineString(pos ...[]Position) *MultiLineString {
-	return (*MultiLineString)(&pos)
+	return &MultiLineString{lines: pos}
 }
 
 // Type returns the geometry type.
@@ -62,9 +92,26 @@ func (m MultiLineString) Type() GeometryType {
 	return MultiLineStringGeometryType
 }
 
+// LineStrings returns the positions of each line.
+func (m MultiLineString) LineStrings() [][]Position {
+	return m.lines
+}
+
+// ForeignMembers returns a copy of the members of the multilinestring that aren't defined by RFC 7946, or nil if there are none.
+func (m MultiLineString) ForeignMembers() map[string]json.RawMessage {
+	return copyForeignMembers(m.foreign)
+}
+
+// WithForeignMember returns a copy of m with the supplied foreign member, which replaces any existing member with the same name.
+// The name must not be one of the members defined by RFC 7946 for a geometry.
+func (m MultiLineString) WithForeignMember(name string, value json.RawMessage) *MultiLineString {
+	m.foreign = withForeignMember(m.foreign, name, value)
+	return &m
+}
+
 // Validate the MultiLineString.
 func (m MultiLineString) Validate() error {
-	for _, ls := range m {
+	for _, ls := range m.lines {
 		if len(ls) < 2 {
 			return errLineStringTooShort
 		}
@@ -73,11 +120,13 @@ func (m MultiLineString) Validate() error {
 }
 
 // MarshalJSON returns the JSON encoding of the MultiLineString.
+// Foreign members follow the members defined by RFC 7946, sorted by name.
 func (m MultiLineString) MarshalJSON() ([]byte, error) {
-	return json.Marshal(geometry{
-		Type:        MultiLineStringGeometryType,
-		Coordinates: [][]Position(m),
-	})
+	lines := m.lines
+	if lines == nil {
+		lines = [][]Position{}
+	}
+	return marshalGeometry(MultiLineStringGeometryType, lines, m.foreign)
 }
 
 // UnmarshalJSON parses the JSON-encoded data and stores the result.
@@ -90,7 +139,12 @@ func (m *MultiLineString) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
-	*m = MultiLineString(geo.Coordinates)
+	foreign, err := unmarshalForeignMembers(data, geometryMembers)
+	if err != nil {
+		return err
+	}
+
+	*m = MultiLineString{lines: geo.Coordinates, foreign: foreign}
 	return nil
 }
 
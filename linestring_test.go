@@ -41,10 +41,32 @@ func TestLineString(t *testing.T) {
 	require.Equal(t, feature, unmarshalled)
 }
 
+func TestLineStringEmpty(t *testing.T) {
+	feature := geojson.NewFeature(new(geojson.LineString))
+	require.NoError(t, feature.Validate())
+
+	data, err := json.Marshal(feature)
+	require.NoError(t, err)
+	require.JSONEq(t, `
+		{
+			"type": "Feature",
+			"geometry": {
+				"type": "LineString",
+				"coordinates": []
+			}
+		}`, string(data))
+
+	var unmarshalled geojson.Feature[*geojson.LineString]
+	err = json.Unmarshal(data, &unmarshalled)
+	require.NoError(t, err)
+	require.True(t, unmarshalled.HasGeometry())
+	require.Empty(t, unmarshalled.Geometry().(*geojson.LineString).Positions())
+}
+
 func TestLineStringTooShort(t *testing.T) {
-	err := geojson.LineString{
+	err := geojson.NewMultiLineString([]geojson.Position{
 		geojson.MakePosition(12, 34),
-	}.Validate()
+	}).Validate()
 	require.Error(t, err)
 }
 
@@ -0,0 +1,831 @@
+package geojson
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// Tile identifies a tile in the Web Mercator tiling scheme, where the tile with X and Y of 0 is in the top left corner.
+type Tile struct {
+	Z, X, Y uint32
+}
+
+// MVTLayer is a named layer of features within a vector tile.
+type MVTLayer struct {
+	Name     string
+	Features []Feature[Geometry]
+}
+
+// MVTOptions configures the encoding of a vector tile.
+type MVTOptions struct {
+	// Extent is the size of the tile in integer coordinates, which defaults to 4096.
+	Extent uint32
+	// Buffer is the distance outside of the tile, in integer coordinates, to which geometries are clipped.
+	Buffer uint32
+}
+
+// MVT geometry types and commands.
+const (
+	mvtPoint      = 1
+	mvtLineString = 2
+	mvtPolygon    = 3
+
+	mvtMoveTo    = 1
+	mvtLineTo    = 2
+	mvtClosePath = 7
+)
+
+const (
+	mvtDefaultExtent = 4096
+	mvtVersion       = 2
+	// Latitude beyond which Web Mercator is undefined.
+	mvtMaxLatitude = 85.0511287798066
+)
+
+// MarshalMVT returns the Mapbox Vector Tile encoding of the layers, projected into the tile.
+// Geometries are clipped to the tile, including the buffer, and features that lie entirely outside of it,
+// or that have no geometry, are omitted. As a vector tile feature can only contain a single type of geometry,
+// features with a GeometryCollection are split into a tile feature for each member, with the same ID and properties.
+// Elevation is discarded, and property values that can't be represented in a vector tile are stored as JSON strings.
+// Feature IDs are kept if they are non-negative integers, as vector tiles can't store other IDs.
+func MarshalMVT(tile Tile, layers []MVTLayer, opts MVTOptions) ([]byte, error) {
+	if opts.Extent == 0 {
+		opts.Extent = mvtDefaultExtent
+	}
+
+	names := make(map[string]bool, len(layers))
+	var data []byte
+	for _, layer := range layers {
+		if names[layer.Name] {
+			return nil, fmt.Errorf("duplicate layer name '%s'", layer.Name)
+		}
+		names[layer.Name] = true
+
+		encoded, err := marshalMVTLayer(tile, layer, opts)
+		if err != nil {
+			return nil, fmt.Errorf("layer '%s': %w", layer.Name, err)
+		}
+		data = appendProtoBytes(data, 3, encoded)
+	}
+	return data, nil
+}
+
+func marshalMVTLayer(tile Tile, layer MVTLayer, opts MVTOptions) ([]byte, error) {
+	keys := map[string]int{}
+	values := map[string]int{}
+	var keyList []string
+	var valueList [][]byte
+
+	var features []byte
+	for _, f := range layer.Features {
+		if !f.HasGeometry() {
+			continue
+		}
+
+		// Each member of a GeometryCollection becomes a separate tile feature.
+		var geometries [][]byte
+		for _, geo := range mvtMembers(f.geometry) {
+			geoType, commands, err := mvtGeometry(tile, geo, opts)
+			if err != nil {
+				return nil, err
+			} else if len(commands) == 0 {
+				continue
+			}
+
+			geometry := appendProtoVarint(nil, 3, uint64(geoType))
+			geometries = append(geometries, appendProtoPackedVarints(geometry, 4, commands))
+		}
+		if len(geometries) == 0 {
+			continue
+		}
+
+		tags := make([]uint64, 0, 2*len(f.properties))
+		for _, prop := range f.properties {
+			if prop.Value == nil {
+				continue
+			}
+
+			value, err := mvtValue(prop.Value)
+			if err != nil {
+				return nil, fmt.Errorf("property '%s': %w", prop.Name, err)
+			}
+
+			key, ok := keys[prop.Name]
+			if !ok {
+				key = len(keyList)
+				keys[prop.Name] = key
+				keyList = append(keyList, prop.Name)
+			}
+
+			index, ok := values[string(value)]
+			if !ok {
+				index = len(valueList)
+				values[string(value)] = index
+				valueList = append(valueList, value)
+			}
+			tags = append(tags, uint64(key), uint64(index))
+		}
+
+		var prefix []byte
+		if f.id != nil {
+			if id, ok := f.id.Uint64(); ok {
+				prefix = appendProtoVarint(prefix, 1, id)
+			}
+		}
+		if len(tags) != 0 {
+			prefix = appendProtoPackedVarints(prefix, 2, tags)
+		}
+		for _, geometry := range geometries {
+			feature := append(prefix[:len(prefix):len(prefix)], geometry...)
+			features = appendProtoBytes(features, 2, feature)
+		}
+	}
+
+	data := appendProtoVarint(nil, 15, mvtVersion)
+	data = appendProtoString(data, 1, layer.Name)
+	data = append(data, features...)
+	for _, key := range keyList {
+		data = appendProtoString(data, 3, key)
+	}
+	for _, value := range valueList {
+		data = appendProtoBytes(data, 4, value)
+	}
+	return appendProtoVarint(data, 5, uint64(opts.Extent)), nil
+}
+
+// mvtMembers returns the geometry, or the members of a GeometryCollection, including those of nested collections.
+func mvtMembers(geo Geometry) []Geometry {
+	collection, ok := geo.(*GeometryCollection)
+	if !ok {
+		return []Geometry{geo}
+	}
+
+	var members []Geometry
+	for _, member := range collection.geometries {
+		if !isNilGeometry(member) {
+			members = append(members, mvtMembers(member)...)
+		}
+	}
+	return members
+}
+
+// mvtGeometry returns the geometry type and encoded commands, which are empty if the geometry lies outside of the tile.
+func mvtGeometry(tile Tile, geo Geometry, opts MVTOptions) (int, []uint64, error) {
+	p := mvtProjection{tile: tile, extent: float64(opts.Extent)}
+	min := -float64(opts.Buffer)
+	max := float64(opts.Extent) + float64(opts.Buffer)
+
+	var e mvtCommandEncoder
+	switch g := geo.(type) {
+	case *Point:
+		e.points(p.clipPoints([]Position{g.pos}, min, max))
+		return mvtPoint, e.commands, nil
+	case *MultiPoint:
+		e.points(p.clipPoints(g.positions, min, max))
+		return mvtPoint, e.commands, nil
+	case *LineString:
+		e.lines(p.clipLine(g.positions, min, max))
+		return mvtLineString, e.commands, nil
+	case *MultiLineString:
+		for _, line := range g.lines {
+			e.lines(p.clipLine(line, min, max))
+		}
+		return mvtLineString, e.commands, nil
+	case *Polygon:
+		e.polygon(p.clipPolygon(g.rings, min, max))
+		return mvtPolygon, e.commands, nil
+	case *MultiPolygon:
+		for _, polygon := range g.polygons {
+			e.polygon(p.clipPolygon(polygon, min, max))
+		}
+		return mvtPolygon, e.commands, nil
+	default:
+		return 0, nil, fmt.Errorf("unsupported geometry type '%T'", geo)
+	}
+}
+
+func mvtValue(value interface{}) ([]byte, error) {
+	switch v := value.(type) {
+	case string:
+		return appendProtoString(nil, 1, v), nil
+	case float32:
+		return appendProtoFloat(nil, 2, v), nil
+	case float64:
+		return appendProtoDouble(nil, 3, v), nil
+	case bool:
+		var b uint64
+		if v {
+			b = 1
+		}
+		return appendProtoVarint(nil, 7, b), nil
+	case int:
+		return mvtInt(int64(v)), nil
+	case int8:
+		return mvtInt(int64(v)), nil
+	case int16:
+		return mvtInt(int64(v)), nil
+	case int32:
+		return mvtInt(int64(v)), nil
+	case int64:
+		return mvtInt(v), nil
+	case uint:
+		return appendProtoVarint(nil, 5, uint64(v)), nil
+	case uint8:
+		return appendProtoVarint(nil, 5, uint64(v)), nil
+	case uint16:
+		return appendProtoVarint(nil, 5, uint64(v)), nil
+	case uint32:
+		return appendProtoVarint(nil, 5, uint64(v)), nil
+	case uint64:
+		return appendProtoVarint(nil, 5, v), nil
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		return appendProtoString(nil, 1, string(data)), nil
+	}
+}
+
+func mvtInt(v int64) []byte {
+	if v < 0 {
+		return appendProtoSint(nil, 6, v)
+	}
+	return appendProtoVarint(nil, 5, uint64(v))
+}
+
+// UnmarshalMVT parses the Mapbox Vector Tile encoded data, converting coordinates within the tile to longitude and latitude.
+func UnmarshalMVT(tile Tile, data []byte) ([]MVTLayer, error) {
+	var layers []MVTLayer
+	r := protoReader{data: data}
+	for r.more() {
+		field, wire, err := r.next()
+		if err != nil {
+			return nil, err
+		} else if field != 3 {
+			if err := r.skip(wire); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		lr, err := r.message()
+		if err != nil {
+			return nil, err
+		}
+
+		layer, err := unmarshalMVTLayer(tile, lr)
+		if err != nil {
+			return nil, err
+		}
+		layers = append(layers, layer)
+	}
+	return layers, nil
+}
+
+type mvtFeature struct {
+	id       *uint64
+	tags     []uint64
+	geoType  uint64
+	commands []uint64
+}
+
+func unmarshalMVTLayer(tile Tile, r *protoReader) (MVTLayer, error) {
+	var layer MVTLayer
+	var keys []string
+	var values []interface{}
+	var features []mvtFeature
+	extent := uint64(mvtDefaultExtent)
+
+	for r.more() {
+		field, wire, err := r.next()
+		if err != nil {
+			return MVTLayer{}, err
+		}
+
+		switch field {
+		case 1:
+			name, err := r.bytes()
+			if err != nil {
+				return MVTLayer{}, err
+			}
+			layer.Name = string(name)
+		case 2:
+			fr, err := r.message()
+			if err != nil {
+				return MVTLayer{}, err
+			}
+
+			feature, err := unmarshalMVTFeature(fr)
+			if err != nil {
+				return MVTLayer{}, err
+			}
+			features = append(features, feature)
+		case 3:
+			key, err := r.bytes()
+			if err != nil {
+				return MVTLayer{}, err
+			}
+			keys = append(keys, string(key))
+		case 4:
+			vr, err := r.message()
+			if err != nil {
+				return MVTLayer{}, err
+			}
+
+			value, err := unmarshalMVTValue(vr)
+			if err != nil {
+				return MVTLayer{}, err
+			}
+			values = append(values, value)
+		case 5:
+			if extent, err = r.varint(); err != nil {
+				return MVTLayer{}, err
+			} else if extent == 0 || extent > math.MaxUint32 {
+				return MVTLayer{}, fmt.Errorf("invalid extent %d", extent)
+			}
+		default:
+			if err := r.skip(wire); err != nil {
+				return MVTLayer{}, err
+			}
+		}
+	}
+
+	p := mvtProjection{tile: tile, extent: float64(extent)}
+	for _, feature := range features {
+		if len(feature.tags)%2 != 0 {
+			return MVTLayer{}, fmt.Errorf("odd number of tags")
+		}
+
+		var props []Property
+		for i := 0; i < len(feature.tags); i += 2 {
+			if feature.tags[i] >= uint64(len(keys)) {
+				return MVTLayer{}, fmt.Errorf("invalid key index %d", feature.tags[i])
+			} else if feature.tags[i+1] >= uint64(len(values)) {
+				return MVTLayer{}, fmt.Errorf("invalid value index %d", feature.tags[i+1])
+			}
+			props = append(props, Property{Name: keys[feature.tags[i]], Value: values[feature.tags[i+1]]})
+		}
+
+		geo, err := p.geometry(feature.geoType, feature.commands)
+		if err != nil {
+			return MVTLayer{}, fmt.Errorf("layer '%s': %w", layer.Name, err)
+		}
+		f := NewFeature(geo, props...)
+		if feature.id != nil {
+			f = f.WithID(UintFeatureID(*feature.id))
+		}
+		layer.Features = append(layer.Features, f)
+	}
+	return layer, nil
+}
+
+func unmarshalMVTFeature(r *protoReader) (mvtFeature, error) {
+	var feature mvtFeature
+	for r.more() {
+		field, wire, err := r.next()
+		if err != nil {
+			return mvtFeature{}, err
+		}
+
+		switch field {
+		case 1:
+			var id uint64
+			id, err = r.varint()
+			feature.id = &id
+		case 2:
+			feature.tags, err = r.varints(wire, feature.tags)
+		case 3:
+			feature.geoType, err = r.varint()
+		case 4:
+			feature.commands, err = r.varints(wire, feature.commands)
+		default:
+			err = r.skip(wire)
+		}
+
+		if err != nil {
+			return mvtFeature{}, err
+		}
+	}
+	return feature, nil
+}
+
+func unmarshalMVTValue(r *protoReader) (interface{}, error) {
+	var value interface{}
+	for r.more() {
+		field, wire, err := r.next()
+		if err != nil {
+			return nil, err
+		}
+
+		switch field {
+		case 1:
+			s, err := r.bytes()
+			if err != nil {
+				return nil, err
+			}
+			value = string(s)
+		case 2:
+			f, err := r.float()
+			if err != nil {
+				return nil, err
+			}
+			value = f
+		case 3:
+			if value, err = r.double(); err != nil {
+				return nil, err
+			}
+		case 4:
+			v, err := r.varint()
+			if err != nil {
+				return nil, err
+			}
+			value = int64(v)
+		case 5:
+			v, err := r.varint()
+			if err != nil {
+				return nil, err
+			} else if v > math.MaxInt64 {
+				value = v
+			} else {
+				value = int64(v)
+			}
+		case 6:
+			if value, err = r.sint(); err != nil {
+				return nil, err
+			}
+		case 7:
+			v, err := r.varint()
+			if err != nil {
+				return nil, err
+			}
+			value = v != 0
+		default:
+			if err := r.skip(wire); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return value, nil
+}
+
+// mvtProjection converts between positions and coordinates within a tile.
+type mvtProjection struct {
+	tile   Tile
+	extent float64
+}
+
+type mvtPointF [2]float64
+
+type mvtPointI [2]int64
+
+func (p mvtProjection) project(pos Position) mvtPointF {
+	lat := math.Max(-mvtMaxLatitude, math.Min(mvtMaxLatitude, pos.lat))
+	n := math.Exp2(float64(p.tile.Z))
+
+	x := (pos.lng + 180) / 360 * n
+	y := (1 - math.Log(math.Tan(lat*math.Pi/180)+1/math.Cos(lat*math.Pi/180))/math.Pi) / 2 * n
+	return mvtPointF{
+		(x - float64(p.tile.X)) * p.extent,
+		(y - float64(p.tile.Y)) * p.extent,
+	}
+}
+
+func (p mvtProjection) unproject(pt mvtPointI) Position {
+	n := math.Exp2(float64(p.tile.Z))
+	x := float64(p.tile.X) + float64(pt[0])/p.extent
+	y := float64(p.tile.Y) + float64(pt[1])/p.extent
+
+	lng := x/n*360 - 180
+	lat := math.Atan(math.Sinh(math.Pi*(1-2*y/n))) * 180 / math.Pi
+	return MakePosition(lat, lng)
+}
+
+func (p mvtProjection) clipPoints(positions []Position, min, max float64) []mvtPointI {
+	var points []mvtPointI
+	for _, pos := range positions {
+		pt := p.project(pos)
+		if pt[0] >= min && pt[0] <= max && pt[1] >= min && pt[1] <= max {
+			points = append(points, quantizeMVT(pt))
+		}
+	}
+	return points
+}
+
+// clipLine returns the parts of the line that are within the bounds.
+func (p mvtProjection) clipLine(positions []Position, min, max float64) [][]mvtPointI {
+	var lines [][]mvtPointI
+	var current []mvtPointF
+	flush := func() {
+		if line := quantizeMVTLine(current); len(line) >= 2 {
+			lines = append(lines, line)
+		}
+		current = nil
+	}
+
+	for i := 1; i < len(positions); i++ {
+		a, b := p.project(positions[i-1]), p.project(positions[i])
+		c0, c1, ok := clipMVTSegment(a, b, min, max)
+		if !ok {
+			flush()
+			continue
+		}
+
+		if len(current) == 0 || current[len(current)-1] != c0 {
+			flush()
+			current = append(current, c0)
+		}
+		current = append(current, c1)
+
+		if c1 != b {
+			flush()
+		}
+	}
+	flush()
+	return lines
+}
+
+// clipPolygon returns the rings of the polygon clipped to the bounds, omitting the closing position.
+// The exterior ring is omitted if it lies outside of the bounds, in which case the holes are also omitted.
+func (p mvtProjection) clipPolygon(rings [][]Position, min, max float64) [][]mvtPointI {
+	var clipped [][]mvtPointI
+	for i, ring := range rings {
+		points := make([]mvtPointF, 0, len(ring))
+		for j, pos := range ring {
+			if j != len(ring)-1 || !pos.Equal(ring[0]) {
+				points = append(points, p.project(pos))
+			}
+		}
+
+		quantized := quantizeMVTLine(clipMVTRing(points, min, max))
+		if len(quantized) > 1 && quantized[0] == quantized[len(quantized)-1] {
+			quantized = quantized[:len(quantized)-1]
+		}
+
+		if len(quantized) < 3 || mvtRingArea(quantized) == 0 {
+			if i == 0 {
+				return nil
+			}
+			continue
+		}
+
+		// The exterior ring must have a positive area in tile coordinates, and holes a negative area.
+		if (mvtRingArea(quantized) > 0) != (i == 0) {
+			for l, r := 0, len(quantized)-1; l < r; l, r = l+1, r-1 {
+				quantized[l], quantized[r] = quantized[r], quantized[l]
+			}
+		}
+		clipped = append(clipped, quantized)
+	}
+	return clipped
+}
+
+// clipMVTSegment clips the segment to the bounds using the Liang-Barsky algorithm.
+func clipMVTSegment(a, b mvtPointF, min, max float64) (mvtPointF, mvtPointF, bool) {
+	t0, t1 := 0.0, 1.0
+	dx, dy := b[0]-a[0], b[1]-a[1]
+
+	for _, edge := range [][2]float64{
+		{-dx, a[0] - min},
+		{dx, max - a[0]},
+		{-dy, a[1] - min},
+		{dy, max - a[1]},
+	} {
+		p, q := edge[0], edge[1]
+		if p == 0 {
+			if q < 0 {
+				return mvtPointF{}, mvtPointF{}, false
+			}
+			continue
+		}
+
+		r := q / p
+		if p < 0 {
+			if r > t1 {
+				return mvtPointF{}, mvtPointF{}, false
+			} else if r > t0 {
+				t0 = r
+			}
+		} else {
+			if r < t0 {
+				return mvtPointF{}, mvtPointF{}, false
+			} else if r < t1 {
+				t1 = r
+			}
+		}
+	}
+
+	c0, c1 := a, b
+	if t0 > 0 {
+		c0 = mvtPointF{a[0] + t0*dx, a[1] + t0*dy}
+	}
+	if t1 < 1 {
+		c1 = mvtPointF{a[0] + t1*dx, a[1] + t1*dy}
+	}
+	return c0, c1, true
+}
+
+// clipMVTRing clips the ring to the bounds using the Sutherland-Hodgman algorithm.
+func clipMVTRing(ring []mvtPointF, min, max float64) []mvtPointF {
+	for edge := 0; edge < 4 && len(ring) != 0; edge++ {
+		axis := edge / 2
+		bound := min
+		if edge%2 == 1 {
+			bound = max
+		}
+
+		inside := func(pt mvtPointF) bool {
+			if edge%2 == 0 {
+				return pt[axis] >= bound
+			}
+			return pt[axis] <= bound
+		}
+
+		intersect := func(a, b mvtPointF) mvtPointF {
+			t := (bound - a[axis]) / (b[axis] - a[axis])
+			return mvtPointF{a[0] + t*(b[0]-a[0]), a[1] + t*(b[1]-a[1])}
+		}
+
+		var out []mvtPointF
+		for i, cur := range ring {
+			prev := ring[(i+len(ring)-1)%len(ring)]
+			if inside(cur) {
+				if !inside(prev) {
+					out = append(out, intersect(prev, cur))
+				}
+				out = append(out, cur)
+			} else if inside(prev) {
+				out = append(out, intersect(prev, cur))
+			}
+		}
+		ring = out
+	}
+	return ring
+}
+
+func quantizeMVT(pt mvtPointF) mvtPointI {
+	return mvtPointI{int64(math.Round(pt[0])), int64(math.Round(pt[1]))}
+}
+
+// quantizeMVTLine rounds the points to integer coordinates, removing consecutive duplicates.
+func quantizeMVTLine(points []mvtPointF) []mvtPointI {
+	var line []mvtPointI
+	for _, pt := range points {
+		if q := quantizeMVT(pt); len(line) == 0 || line[len(line)-1] != q {
+			line = append(line, q)
+		}
+	}
+	return line
+}
+
+// mvtRingArea returns twice the signed area of the ring, which is positive for clockwise rings in tile coordinates.
+func mvtRingArea(ring []mvtPointI) int64 {
+	var area int64
+	for i, pt := range ring {
+		next := ring[(i+1)%len(ring)]
+		area += pt[0]*next[1] - next[0]*pt[1]
+	}
+	return area
+}
+
+// mvtCommandEncoder encodes geometry commands, with coordinates relative to the cursor.
+type mvtCommandEncoder struct {
+	commands []uint64
+	cursor   mvtPointI
+}
+
+func (e *mvtCommandEncoder) command(id int, count int) {
+	e.commands = append(e.commands, uint64(id&0x7|count<<3))
+}
+
+func (e *mvtCommandEncoder) moveTo(points ...mvtPointI) {
+	for _, pt := range points {
+		e.commands = append(e.commands, zigzag(pt[0]-e.cursor[0]), zigzag(pt[1]-e.cursor[1]))
+		e.cursor = pt
+	}
+}
+
+func (e *mvtCommandEncoder) points(points []mvtPointI) {
+	if len(points) == 0 {
+		return
+	}
+	e.command(mvtMoveTo, len(points))
+	e.moveTo(points...)
+}
+
+func (e *mvtCommandEncoder) lines(lines [][]mvtPointI) {
+	for _, line := range lines {
+		e.command(mvtMoveTo, 1)
+		e.moveTo(line[0])
+		e.command(mvtLineTo, len(line)-1)
+		e.moveTo(line[1:]...)
+	}
+}
+
+func (e *mvtCommandEncoder) polygon(rings [][]mvtPointI) {
+	for _, ring := range rings {
+		e.command(mvtMoveTo, 1)
+		e.moveTo(ring[0])
+		e.command(mvtLineTo, len(ring)-1)
+		e.moveTo(ring[1:]...)
+		e.command(mvtClosePath, 1)
+	}
+}
+
+// geometry decodes the commands, and converts the coordinates to positions.
+func (p mvtProjection) geometry(geoType uint64, commands []uint64) (Geometry, error) {
+	var parts [][]mvtPointI
+	var cursor mvtPointI
+	for i := 0; i < len(commands); {
+		id, count := commands[i]&0x7, int(commands[i]>>3)
+		i++
+
+		switch id {
+		case mvtMoveTo, mvtLineTo:
+			if count > (len(commands)-i)/2 {
+				return nil, fmt.Errorf("unexpected end of geometry")
+			}
+
+			for j := 0; j < count; j++ {
+				cursor[0] += unzigzag(commands[i])
+				cursor[1] += unzigzag(commands[i+1])
+				i += 2
+
+				if id == mvtMoveTo {
+					parts = append(parts, nil)
+				} else if len(parts) == 0 {
+					return nil, fmt.Errorf("line to without move to")
+				}
+				parts[len(parts)-1] = append(parts[len(parts)-1], cursor)
+			}
+		case mvtClosePath:
+			if len(parts) == 0 || len(parts[len(parts)-1]) == 0 {
+				return nil, fmt.Errorf("close path without move to")
+			}
+			parts[len(parts)-1] = append(parts[len(parts)-1], parts[len(parts)-1][0])
+		default:
+			return nil, fmt.Errorf("unsupported command %d", id)
+		}
+	}
+
+	positions := func(points []mvtPointI) []Position {
+		list := make([]Position, len(points))
+		for i, pt := range points {
+			list[i] = p.unproject(pt)
+		}
+		return list
+	}
+
+	switch geoType {
+	case mvtPoint:
+		var points []Position
+		for _, part := range parts {
+			points = append(points, positions(part)...)
+		}
+
+		if len(points) == 1 {
+			return NewPointFromPosition(points[0]), nil
+		}
+		return NewMultiPoint(points...), nil
+	case mvtLineString:
+		lines := make([][]Position, len(parts))
+		for i, part := range parts {
+			lines[i] = positions(part)
+		}
+
+		if len(lines) == 1 {
+			return &LineString{positions: lines[0]}, nil
+		}
+		return NewMultiLineString(lines...), nil
+	case mvtPolygon:
+		// Each ring with a positive area starts a new polygon, and those with a negative area are its holes.
+		var polygons [][][]Position
+		for _, part := range parts {
+			if len(part) != 0 && part[0] == part[len(part)-1] {
+				part = part[:len(part)-1]
+			}
+
+			area := mvtRingArea(part)
+			if area == 0 {
+				continue
+			}
+
+			ring := positions(append(part, part[0]))
+			if area > 0 {
+				polygons = append(polygons, [][]Position{ring})
+			} else if len(polygons) == 0 {
+				return nil, fmt.Errorf("polygon must start with an exterior ring")
+			} else {
+				polygons[len(polygons)-1] = append(polygons[len(polygons)-1], ring)
+			}
+		}
+
+		if len(polygons) == 1 {
+			return NewPolygon(polygons[0]...), nil
+		}
+		return NewMultiPolygon(polygons...), nil
+	default:
+		return nil, fmt.Errorf("unsupported geometry type %d", geoType)
+	}
+}
@@ -0,0 +1,234 @@
+package geojson_test
+
+import (
+	"encoding/hex"
+	"math"
+	"testing"
+
+	geojson "github.com/everystreet/go-geojson/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMVT(t *testing.T) {
+	tile := geojson.Tile{Z: 10, X: 544, Y: 370}
+	pos := func(x, y int) geojson.Position {
+		return tilePosition(tile, 4096, x, y)
+	}
+
+	layers := []geojson.MVTLayer{
+		{
+			Name: "places",
+			Features: []geojson.Feature[geojson.Geometry]{
+				geojson.NewFeature[geojson.Geometry](
+					geojson.NewPointFromPosition(pos(25, 17)),
+					geojson.Property{Name: "name", Value: "a"},
+					geojson.Property{Name: "rank", Value: int64(-3)},
+					geojson.Property{Name: "population", Value: int64(1352000)},
+				),
+				geojson.NewFeature[geojson.Geometry](
+					geojson.NewMultiPoint(pos(5, 7), pos(3, 2)),
+					geojson.Property{Name: "name", Value: "b"},
+					geojson.Property{Name: "capital", Value: true},
+					geojson.Property{Name: "area", Value: 1.5},
+					geojson.Property{Name: "ratio", Value: float32(0.25)},
+				),
+			},
+		},
+		{
+			Name: "roads",
+			Features: []geojson.Feature[geojson.Geometry]{
+				geojson.NewFeatureWithID[geojson.Geometry](
+					geojson.NewLineString(pos(2, 2), pos(2, 10), pos(10, 10)),
+					geojson.UintFeatureID(18446744073709551615),
+					geojson.Property{Name: "name", Value: "a"},
+				),
+				geojson.NewFeatureWithID[geojson.Geometry](
+					geojson.NewMultiLineString(
+						[]geojson.Position{pos(2, 2), pos(2, 10), pos(10, 10)},
+						[]geojson.Position{pos(1, 1), pos(3, 5)},
+					),
+					geojson.UintFeatureID(7),
+				),
+			},
+		},
+		{
+			Name: "areas",
+			Features: []geojson.Feature[geojson.Geometry]{
+				geojson.NewFeature[geojson.Geometry](
+					geojson.NewPolygon(
+						[]geojson.Position{pos(3, 6), pos(8, 12), pos(20, 34), pos(3, 6)},
+					),
+				),
+				geojson.NewFeature[geojson.Geometry](
+					geojson.NewMultiPolygon(
+						[][]geojson.Position{
+							{pos(0, 0), pos(10, 0), pos(10, 10), pos(0, 10), pos(0, 0)},
+						},
+						[][]geojson.Position{
+							{pos(11, 11), pos(20, 11), pos(20, 20), pos(11, 20), pos(11, 11)},
+							{pos(13, 13), pos(13, 17), pos(17, 17), pos(17, 13), pos(13, 13)},
+						},
+					),
+				),
+			},
+		},
+	}
+
+	data, err := geojson.MarshalMVT(tile, layers, geojson.MVTOptions{})
+	require.NoError(t, err)
+
+	unmarshalled, err := geojson.UnmarshalMVT(tile, data)
+	require.NoError(t, err)
+	require.Equal(t, layers, unmarshalled)
+
+	for _, feature := range unmarshalled[2].Features {
+		require.NoError(t, feature.Geometry().Validate())
+	}
+}
+
+func TestMVTEncoding(t *testing.T) {
+	tile := geojson.Tile{Z: 0, X: 0, Y: 0}
+	point := geojson.NewPointFromPosition(tilePosition(tile, 4096, 25, 17))
+
+	data, err := geojson.MarshalMVT(tile, []geojson.MVTLayer{
+		{
+			Name: "a",
+			Features: []geojson.Feature[geojson.Geometry]{
+				geojson.NewFeature[geojson.Geometry](point),
+			},
+		},
+	}, geojson.MVTOptions{})
+	require.NoError(t, err)
+
+	// Layer containing a point feature with the geometry [9, 50, 34].
+	require.Equal(t, "1a1178020a0161120718012203093222288020", hex.EncodeToString(data))
+}
+
+func TestMVTClip(t *testing.T) {
+	tile := geojson.Tile{Z: 2, X: 2, Y: 1}
+	pos := func(x, y int) geojson.Position {
+		return tilePosition(tile, 256, x, y)
+	}
+
+	layers := []geojson.MVTLayer{
+		{
+			Name: "a",
+			Features: []geojson.Feature[geojson.Geometry]{
+				geojson.NewFeature[geojson.Geometry](
+					geojson.NewMultiPoint(pos(-20, 10), pos(10, 10)),
+				),
+				geojson.NewFeature[geojson.Geometry](
+					geojson.NewLineString(pos(-100, 100), pos(100, 100), pos(100, 300), pos(200, 300), pos(200, 200)),
+				),
+				geojson.NewFeature[geojson.Geometry](
+					geojson.NewPolygon(
+						[]geojson.Position{pos(-100, -100), pos(300, -100), pos(300, 300), pos(-100, 300), pos(-100, -100)},
+					),
+				),
+				geojson.NewFeature[geojson.Geometry](
+					geojson.NewLineString(pos(-100, -100), pos(-50, -50)),
+				),
+				geojson.NewFeature[geojson.Geometry](nil),
+			},
+		},
+	}
+
+	data, err := geojson.MarshalMVT(tile, layers, geojson.MVTOptions{Extent: 256, Buffer: 16})
+	require.NoError(t, err)
+
+	unmarshalled, err := geojson.UnmarshalMVT(tile, data)
+	require.NoError(t, err)
+	require.Equal(t, []geojson.MVTLayer{
+		{
+			Name: "a",
+			Features: []geojson.Feature[geojson.Geometry]{
+				geojson.NewFeature[geojson.Geometry](
+					geojson.NewPointFromPosition(pos(10, 10)),
+				),
+				geojson.NewFeature[geojson.Geometry](
+					geojson.NewMultiLineString(
+						[]geojson.Position{pos(-16, 100), pos(100, 100), pos(100, 272)},
+						[]geojson.Position{pos(200, 272), pos(200, 200)},
+					),
+				),
+				geojson.NewFeature[geojson.Geometry](
+					geojson.NewPolygon(
+						[]geojson.Position{pos(-16, 272), pos(-16, -16), pos(272, -16), pos(272, 272), pos(-16, 272)},
+					),
+				),
+			},
+		},
+	}, unmarshalled)
+}
+
+func TestMVTGeometryCollection(t *testing.T) {
+	tile := geojson.Tile{Z: 2, X: 2, Y: 1}
+	pos := func(x, y int) geojson.Position {
+		return tilePosition(tile, 256, x, y)
+	}
+	point := func(x, y int) *geojson.Point {
+		return geojson.NewPointFromPosition(pos(x, y))
+	}
+
+	data, err := geojson.MarshalMVT(tile, []geojson.MVTLayer{
+		{
+			Name: "a",
+			Features: []geojson.Feature[geojson.Geometry]{
+				geojson.NewFeatureWithID[geojson.Geometry](
+					geojson.NewGeometryCollection(
+						point(20, 20),
+						point(-100, -100),
+						geojson.NewGeometryCollection(geojson.NewLineString(pos(10, 10), pos(30, 40))),
+					),
+					geojson.UintFeatureID(5),
+					geojson.Property{Name: "name", Value: "a"},
+				),
+				geojson.NewFeature[geojson.Geometry](geojson.NewGeometryCollection(point(-100, -100))),
+			},
+		},
+	}, geojson.MVTOptions{Extent: 256})
+	require.NoError(t, err)
+
+	// Each member within the tile becomes a feature with the same ID and properties.
+	unmarshalled, err := geojson.UnmarshalMVT(tile, data)
+	require.NoError(t, err)
+	require.Equal(t, []geojson.MVTLayer{
+		{
+			Name: "a",
+			Features: []geojson.Feature[geojson.Geometry]{
+				geojson.NewFeatureWithID[geojson.Geometry](
+					point(20, 20),
+					geojson.UintFeatureID(5),
+					geojson.Property{Name: "name", Value: "a"},
+				),
+				geojson.NewFeatureWithID[geojson.Geometry](
+					geojson.NewLineString(pos(10, 10), pos(30, 40)),
+					geojson.UintFeatureID(5),
+					geojson.Property{Name: "name", Value: "a"},
+				),
+			},
+		},
+	}, unmarshalled)
+}
+
+func TestMVTInvalid(t *testing.T) {
+	_, err := geojson.MarshalMVT(geojson.Tile{}, []geojson.MVTLayer{{Name: "a"}, {Name: "a"}}, geojson.MVTOptions{})
+	require.Error(t, err)
+
+	data, err := hex.DecodeString("1a0b0a01611206180122020932")
+	require.NoError(t, err)
+
+	_, err = geojson.UnmarshalMVT(geojson.Tile{}, data)
+	require.Error(t, err)
+}
+
+// tilePosition returns the position of the integer coordinates within the tile.
+func tilePosition(tile geojson.Tile, extent int, x, y int) geojson.Position {
+	n := math.Exp2(float64(tile.Z))
+	tx := float64(tile.X) + float64(x)/float64(extent)
+	ty := float64(tile.Y) + float64(y)/float64(extent)
+
+	lng := tx/n*360 - 180
+	lat := math.Atan(math.Sinh(math.Pi*(1-2*ty/n))) * 180 / math.Pi
+	return geojson.MakePosition(lat, lng)
+}
@@ -0,0 +1,151 @@
+package geojson
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"io"
+	"runtime"
+	"sync"
+)
+
+// NDJSONReader reads newline-delimited Features, with one Feature per line.
+// Lines are decoded concurrently, but Features are returned in the order in which they were read.
+type NDJSONReader struct {
+	results   chan chan ndjsonResult
+	done      chan struct{}
+	closeOnce sync.Once
+}
+
+type ndjsonJob struct {
+	line   int
+	data   []byte
+	result chan<- ndjsonResult
+}
+
+type ndjsonResult struct {
+	feature Feature[Geometry]
+	err     error
+}
+
+// NewNDJSONReader returns a new NDJSONReader that reads from r, decoding lines using the specified number of workers.
+// If workers is less than 1, the value of runtime.GOMAXPROCS is used.
+func NewNDJSONReader(r io.Reader, workers int) *NDJSONReader {
+	if workers < 1 {
+		workers = runtime.GOMAXPROCS(0)
+	}
+
+	reader := &NDJSONReader{
+		results: make(chan chan ndjsonResult, workers*2),
+		done:    make(chan struct{}),
+	}
+
+	jobs := make(chan ndjsonJob, workers)
+	for i := 0; i < workers; i++ {
+		go decodeNDJSON(jobs)
+	}
+
+	go reader.scan(bufio.NewReader(r), jobs)
+	return reader
+}
+
+// Read returns the next Feature.
+// Lines that can't be decoded are reported as a *RecordError, after which reading may continue.
+// It returns io.EOF once all lines have been read.
+func (r *NDJSONReader) Read() (Feature[Geometry], error) {
+	select {
+	case <-r.done:
+		return Feature[Geometry]{}, io.EOF
+	default:
+	}
+
+	select {
+	case result, ok := <-r.results:
+		if !ok {
+			return Feature[Geometry]{}, io.EOF
+		}
+		res := <-result
+		return res.feature, res.err
+	case <-r.done:
+		return Feature[Geometry]{}, io.EOF
+	}
+}
+
+// Close stops reading. It does not close the underlying reader.
+func (r *NDJSONReader) Close() error {
+	r.closeOnce.Do(func() {
+		close(r.done)
+	})
+	return nil
+}
+
+func (r *NDJSONReader) scan(br *bufio.Reader, jobs chan<- ndjsonJob) {
+	defer close(r.results)
+	defer close(jobs)
+
+	for line := 1; ; line++ {
+		data, err := br.ReadBytes('\n')
+		if err != nil && err != io.EOF {
+			result := make(chan ndjsonResult, 1)
+			result <- ndjsonResult{err: err}
+			select {
+			case r.results <- result:
+			case <-r.done:
+			}
+			return
+		}
+
+		if len(bytes.TrimSpace(data)) != 0 {
+			result := make(chan ndjsonResult, 1)
+			select {
+			case jobs <- ndjsonJob{line: line, data: data, result: result}:
+			case <-r.done:
+				return
+			}
+
+			select {
+			case r.results <- result:
+			case <-r.done:
+				return
+			}
+		}
+
+		if err == io.EOF {
+			return
+		}
+	}
+}
+
+func decodeNDJSON(jobs <-chan ndjsonJob) {
+	for job := range jobs {
+		var feature Feature[Geometry]
+		if err := json.Unmarshal(job.data, &feature); err != nil {
+			job.result <- ndjsonResult{err: &RecordError{Record: job.line, Err: err}}
+			continue
+		}
+		job.result <- ndjsonResult{feature: feature}
+	}
+}
+
+// NDJSONWriter writes newline-delimited Features, with one Feature per line.
+type NDJSONWriter[G Geometry] struct {
+	w io.Writer
+}
+
+// NewNDJSONWriter returns a new NDJSONWriter that writes to w.
+func NewNDJSONWriter[G Geometry](w io.Writer) *NDJSONWriter[G] {
+	return &NDJSONWriter[G]{
+		w: w,
+	}
+}
+
+// Write the feature as a single line.
+func (w *NDJSONWriter[G]) Write(feature Feature[G]) error {
+	data, err := json.Marshal(feature)
+	if err != nil {
+		return err
+	}
+
+	_, err = w.w.Write(append(data, '\n'))
+	return err
+}
@@ -0,0 +1,87 @@
+package geojson_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	geojson "github.com/everystreet/go-geojson/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNDJSON(t *testing.T) {
+	var buf bytes.Buffer
+	w := geojson.NewNDJSONWriter[*geojson.Point](&buf)
+
+	for i := 0; i < 100; i++ {
+		err := w.Write(geojson.NewFeature(
+			geojson.NewPoint(float64(i%90), float64(i)),
+			geojson.Property{Name: "index", Value: float64(i)},
+		))
+		require.NoError(t, err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 100)
+	require.Equal(t, `{"type":"Feature","geometry":{"type":"Point","coordinates":[1,1]},"properties":{"index":1}}`, lines[1])
+
+	r := geojson.NewNDJSONReader(&buf, 4)
+	defer r.Close()
+
+	for i := 0; i < 100; i++ {
+		feature, err := r.Read()
+		require.NoError(t, err)
+		require.Equal(t, geojson.NewPoint(float64(i%90), float64(i)), feature.Geometry())
+
+		props := feature.Properties()
+		var index float64
+		err = props.GetValue("index", &index)
+		require.NoError(t, err)
+		require.Equal(t, float64(i), index)
+	}
+
+	_, err := r.Read()
+	require.Equal(t, io.EOF, err)
+}
+
+func TestNDJSONReaderErrors(t *testing.T) {
+	r := geojson.NewNDJSONReader(strings.NewReader(
+		`{"type":"Feature","geometry":{"type":"Point","coordinates":[1, 2]}}`+"\n"+
+			"\n"+
+			`{"type":"Feature","geometry":`+"\n"+
+			`{"type":"Feature","geometry":{"type":"Point","coordinates":[3, 4]}}`,
+	), 0)
+	defer r.Close()
+
+	feature, err := r.Read()
+	require.NoError(t, err)
+	require.Equal(t, geojson.NewPoint(2, 1), feature.Geometry())
+
+	_, err = r.Read()
+	var recordErr *geojson.RecordError
+	require.True(t, errors.As(err, &recordErr))
+	require.Equal(t, 3, recordErr.Record)
+
+	feature, err = r.Read()
+	require.NoError(t, err)
+	require.Equal(t, geojson.NewPoint(4, 3), feature.Geometry())
+
+	_, err = r.Read()
+	require.Equal(t, io.EOF, err)
+}
+
+func TestNDJSONReaderClose(t *testing.T) {
+	r := geojson.NewNDJSONReader(strings.NewReader(strings.Repeat(
+		`{"type":"Feature","geometry":{"type":"Point","coordinates":[1, 2]}}`+"\n", 1000)), 2)
+
+	_, err := r.Read()
+	require.NoError(t, err)
+
+	err = r.Close()
+	require.NoError(t, err)
+
+	_, err = r.Read()
+	require.Equal(t, io.EOF, err)
+}
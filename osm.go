@@ -0,0 +1,374 @@
+package geojson
+
+import (
+	"encoding/xml"
+	"io"
+)
+
+// ReadOSM reads an OpenStreetMap XML document, returning a feature for each tagged node and way, and each multipolygon relation.
+// The tags of each element are stored as string properties.
+//
+// Nodes become Points. Closed ways become Polygons if they are tagged as areas, either explicitly with area=yes
+// or by tags such as building and landuse, and other ways become LineStrings.
+// Multipolygon and boundary relations become MultiPolygons, by joining the ways with the outer and inner roles into rings.
+// Ways and relations that reference elements that aren't in the document are skipped, as are relations whose rings can't be closed.
+func ReadOSM(r io.Reader) (FeatureCollection, error) {
+	data := newOSMData()
+	d := xml.NewDecoder(r)
+	for {
+		t, err := d.Token()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return FeatureCollection{}, err
+		}
+
+		start, ok := t.(xml.StartElement)
+		if !ok {
+			continue
+		}
+
+		switch start.Name.Local {
+		case "node":
+			var node osmXMLNode
+			if err := d.DecodeElement(&node, &start); err != nil {
+				return FeatureCollection{}, err
+			} else if node.Visible != "false" {
+				data.addNode(node.ID, MakePosition(node.Lat, node.Lon), node.Tags.properties())
+			}
+		case "way":
+			var way osmXMLWay
+			if err := d.DecodeElement(&way, &start); err != nil {
+				return FeatureCollection{}, err
+			} else if way.Visible != "false" {
+				refs := make([]int64, len(way.Nodes))
+				for i, nd := range way.Nodes {
+					refs[i] = nd.Ref
+				}
+				data.addWay(way.ID, refs, way.Tags.properties())
+			}
+		case "relation":
+			var relation osmXMLRelation
+			if err := d.DecodeElement(&relation, &start); err != nil {
+				return FeatureCollection{}, err
+			} else if relation.Visible != "false" {
+				members := make([]osmMember, len(relation.Members))
+				for i, member := range relation.Members {
+					members[i] = osmMember{isWay: member.Type == "way", ref: member.Ref, role: member.Role}
+				}
+				data.addRelation(relation.ID, members, relation.Tags.properties())
+			}
+		}
+	}
+	return NewFeatureCollection(data.features()...), nil
+}
+
+type osmXMLNode struct {
+	ID      int64      `xml:"id,attr"`
+	Lat     float64    `xml:"lat,attr"`
+	Lon     float64    `xml:"lon,attr"`
+	Visible string     `xml:"visible,attr"`
+	Tags    osmXMLTags `xml:"tag"`
+}
+
+type osmXMLWay struct {
+	ID      int64      `xml:"id,attr"`
+	Visible string     `xml:"visible,attr"`
+	Nodes   []osmXMLNd `xml:"nd"`
+	Tags    osmXMLTags `xml:"tag"`
+}
+
+type osmXMLNd struct {
+	Ref int64 `xml:"ref,attr"`
+}
+
+type osmXMLRelation struct {
+	ID      int64          `xml:"id,attr"`
+	Visible string         `xml:"visible,attr"`
+	Members []osmXMLMember `xml:"member"`
+	Tags    osmXMLTags     `xml:"tag"`
+}
+
+type osmXMLMember struct {
+	Type string `xml:"type,attr"`
+	Ref  int64  `xml:"ref,attr"`
+	Role string `xml:"role,attr"`
+}
+
+type osmXMLTags []struct {
+	Key   string `xml:"k,attr"`
+	Value string `xml:"v,attr"`
+}
+
+func (t osmXMLTags) properties() []Property {
+	if len(t) == 0 {
+		return nil
+	}
+
+	props := make([]Property, len(t))
+	for i, tag := range t {
+		props[i] = Property{Name: tag.Key, Value: tag.Value}
+	}
+	return props
+}
+
+// osmData holds the elements of an OpenStreetMap document, in the order in which they were added.
+type osmData struct {
+	positions  map[int64]Position
+	ways       map[int64][]int64
+	nodes      []osmNode
+	taggedWays []osmWay
+	relations  []osmRelation
+}
+
+type osmNode struct {
+	id   int64
+	tags []Property
+}
+
+type osmWay struct {
+	id   int64
+	tags []Property
+}
+
+type osmRelation struct {
+	id      int64
+	members []osmMember
+	tags    []Property
+}
+
+type osmMember struct {
+	isWay bool
+	ref   int64
+	role  string
+}
+
+func newOSMData() *osmData {
+	return &osmData{
+		positions: make(map[int64]Position),
+		ways:      make(map[int64][]int64),
+	}
+}
+
+func (d *osmData) addNode(id int64, pos Position, tags []Property) {
+	d.positions[id] = pos
+	if len(tags) != 0 {
+		d.nodes = append(d.nodes, osmNode{id: id, tags: tags})
+	}
+}
+
+func (d *osmData) addWay(id int64, refs []int64, tags []Property) {
+	d.ways[id] = refs
+	if len(tags) != 0 {
+		d.taggedWays = append(d.taggedWays, osmWay{id: id, tags: tags})
+	}
+}
+
+// addRelation adds the relation if it is a multipolygon, as other relations aren't converted to features.
+func (d *osmData) addRelation(id int64, members []osmMember, tags []Property) {
+	switch osmTag(tags, "type") {
+	case "multipolygon", "boundary":
+		d.relations = append(d.relations, osmRelation{id: id, members: members, tags: tags})
+	}
+}
+
+// features returns a feature for each tagged node and way, followed by each multipolygon relation.
+func (d *osmData) features() []Feature[Geometry] {
+	var features []Feature[Geometry]
+	for _, node := range d.nodes {
+		pos := d.positions[node.id]
+		features = append(features, NewFeature[Geometry](NewPointFromPosition(pos), node.tags...))
+	}
+
+	for _, way := range d.taggedWays {
+		refs := d.ways[way.id]
+		positions, ok := d.wayPositions(refs)
+		if !ok {
+			continue
+		}
+
+		var geo Geometry = &LineString{positions: positions}
+		if len(refs) >= 4 && refs[0] == refs[len(refs)-1] && osmIsArea(way.tags) {
+			geo = NewPolygon(assemblePolygons([][]Position{positions}, nil)[0]...)
+		}
+		features = append(features, NewFeature(geo, way.tags...))
+	}
+
+	for _, relation := range d.relations {
+		if polygons, ok := d.multipolygon(relation); ok {
+			features = append(features, NewFeature[Geometry](NewMultiPolygon(polygons...), relation.tags...))
+		}
+	}
+	return features
+}
+
+// wayPositions returns the positions of the nodes, or false if any of them are missing.
+func (d *osmData) wayPositions(refs []int64) ([]Position, bool) {
+	positions := make([]Position, len(refs))
+	for i, ref := range refs {
+		pos, ok := d.positions[ref]
+		if !ok {
+			return nil, false
+		}
+		positions[i] = pos
+	}
+	return positions, true
+}
+
+// multipolygon joins the outer and inner ways of the relation into rings, and assembles them into polygons.
+func (d *osmData) multipolygon(relation osmRelation) ([][][]Position, bool) {
+	var outers, inners [][]int64
+	for _, member := range relation.members {
+		if !member.isWay {
+			continue
+		}
+
+		refs, ok := d.ways[member.ref]
+		if !ok {
+			return nil, false
+		}
+
+		switch member.role {
+		case "outer", "":
+			outers = append(outers, refs)
+		case "inner":
+			inners = append(inners, refs)
+		}
+	}
+
+	var rings [2][][]Position
+	for i, ways := range [][][]int64{outers, inners} {
+		joined, ok := joinOSMRings(ways)
+		if !ok {
+			return nil, false
+		}
+
+		for _, refs := range joined {
+			positions, ok := d.wayPositions(refs)
+			if !ok {
+				return nil, false
+			}
+			rings[i] = append(rings[i], positions)
+		}
+	}
+
+	if len(rings[0]) == 0 {
+		return nil, false
+	}
+	return assemblePolygons(rings[0], rings[1]), true
+}
+
+// joinOSMRings joins ways that share end nodes into closed rings, or returns false if any ring can't be closed.
+func joinOSMRings(ways [][]int64) ([][]int64, bool) {
+	remaining := make([][]int64, 0, len(ways))
+	for _, way := range ways {
+		if len(way) >= 2 {
+			remaining = append(remaining, way)
+		}
+	}
+
+	var rings [][]int64
+	for len(remaining) != 0 {
+		ring := append([]int64{}, remaining[0]...)
+		remaining = remaining[1:]
+
+		for ring[0] != ring[len(ring)-1] {
+			end := ring[len(ring)-1]
+			next := -1
+			for i, way := range remaining {
+				if way[0] == end {
+					ring = append(ring, way[1:]...)
+				} else if way[len(way)-1] == end {
+					for j := len(way) - 2; j >= 0; j-- {
+						ring = append(ring, way[j])
+					}
+				} else {
+					continue
+				}
+				next = i
+				break
+			}
+
+			if next == -1 {
+				return nil, false
+			}
+			remaining = append(remaining[:next], remaining[next+1:]...)
+		}
+
+		if len(ring) >= 4 {
+			rings = append(rings, ring)
+		}
+	}
+	return rings, true
+}
+
+// osmAreaKeys are the keys of tags that indicate that a closed way is an area.
+// If a key has included values, only those values indicate an area, and if it has excluded values, any other value does.
+var osmAreaKeys = map[string]struct {
+	included, excluded []string
+}{
+	"amenity":       {},
+	"building":      {},
+	"building:part": {},
+	"craft":         {},
+	"historic":      {},
+	"landuse":       {},
+	"leisure":       {},
+	"military":      {},
+	"office":        {},
+	"place":         {},
+	"shop":          {},
+	"tourism":       {},
+	"aeroway":       {excluded: []string{"jet_bridge", "parking_position", "runway", "taxiway", "taxilane"}},
+	"man_made":      {excluded: []string{"cutline", "embankment", "pipeline", "breakwater", "groyne"}},
+	"natural":       {excluded: []string{"coastline", "cliff", "ridge", "arete", "tree_row", "valley"}},
+	"power":         {included: []string{"plant", "substation", "generator", "transformer"}},
+	"highway":       {included: []string{"services", "rest_area"}},
+	"railway":       {included: []string{"platform", "station", "turntable"}},
+	"waterway":      {included: []string{"riverbank", "dock", "boatyard", "dam"}},
+}
+
+// osmIsArea reports whether the tags of a closed way indicate that it is an area rather than a line.
+func osmIsArea(tags []Property) bool {
+	switch osmTag(tags, "area") {
+	case "yes":
+		return true
+	case "no":
+		return false
+	}
+
+	for _, tag := range tags {
+		values, ok := osmAreaKeys[tag.Name]
+		if !ok || tag.Value == "no" {
+			continue
+		}
+
+		value := tag.Value.(string)
+		if values.included != nil {
+			if containsString(values.included, value) {
+				return true
+			}
+		} else if !containsString(values.excluded, value) {
+			return true
+		}
+	}
+	return false
+}
+
+func osmTag(tags []Property, key string) string {
+	for _, tag := range tags {
+		if tag.Name == key {
+			return tag.Value.(string)
+		}
+	}
+	return ""
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,146 @@
+package geojson_test
+
+import (
+	"strings"
+	"testing"
+
+	geojson "github.com/everystreet/go-geojson/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadOSM(t *testing.T) {
+	c, err := geojson.ReadOSM(strings.NewReader(`<?xml version="1.0" encoding="UTF-8"?>
+<osm version="0.6" generator="test">
+  <bounds minlat="0" minlon="0" maxlat="10" maxlon="10"/>
+  <node id="1" lat="0" lon="0"/>
+  <node id="2" lat="0" lon="10"/>
+  <node id="3" lat="10" lon="10"/>
+  <node id="4" lat="10" lon="0">
+    <tag k="amenity" v="cafe"/>
+    <tag k="name" v="Corner"/>
+  </node>
+  <node id="5" lat="2" lon="2"/>
+  <node id="6" lat="2" lon="8"/>
+  <node id="7" lat="8" lon="8"/>
+  <node id="8" lat="8" lon="2"/>
+  <node id="9" lat="5" lon="5" visible="false"><tag k="name" v="deleted"/></node>
+  <way id="10">
+    <nd ref="1"/><nd ref="2"/><nd ref="3"/>
+  </way>
+  <way id="11">
+    <nd ref="1"/><nd ref="4"/><nd ref="3"/>
+  </way>
+  <way id="12">
+    <nd ref="5"/><nd ref="6"/><nd ref="7"/><nd ref="8"/><nd ref="5"/>
+    <tag k="building" v="yes"/>
+  </way>
+  <way id="13">
+    <nd ref="5"/><nd ref="6"/><nd ref="7"/><nd ref="8"/><nd ref="5"/>
+    <tag k="highway" v="service"/>
+  </way>
+  <way id="14">
+    <nd ref="1"/><nd ref="100"/>
+    <tag k="highway" v="path"/>
+  </way>
+  <relation id="20">
+    <member type="way" ref="10" role="outer"/>
+    <member type="way" ref="11" role="outer"/>
+    <member type="way" ref="12" role="inner"/>
+    <member type="node" ref="4" role=""/>
+    <tag k="type" v="multipolygon"/>
+    <tag k="landuse" v="forest"/>
+  </relation>
+  <relation id="21">
+    <member type="way" ref="10" role="outer"/>
+    <tag k="type" v="multipolygon"/>
+  </relation>
+  <relation id="22">
+    <member type="way" ref="10" role=""/>
+    <tag k="type" v="route"/>
+  </relation>
+</osm>`))
+	require.NoError(t, err)
+
+	square := []geojson.Position{
+		geojson.MakePosition(2, 2),
+		geojson.MakePosition(2, 8),
+		geojson.MakePosition(8, 8),
+		geojson.MakePosition(8, 2),
+		geojson.MakePosition(2, 2),
+	}
+
+	require.Equal(t, geojson.NewFeatureCollection(
+		geojson.NewFeature[geojson.Geometry](
+			geojson.NewPoint(10, 0),
+			geojson.Property{Name: "amenity", Value: "cafe"},
+			geojson.Property{Name: "name", Value: "Corner"},
+		),
+		geojson.NewFeature[geojson.Geometry](
+			geojson.NewPolygon([]geojson.Position{
+				geojson.MakePosition(2, 2),
+				geojson.MakePosition(8, 2),
+				geojson.MakePosition(8, 8),
+				geojson.MakePosition(2, 8),
+				geojson.MakePosition(2, 2),
+			}),
+			geojson.Property{Name: "building", Value: "yes"},
+		),
+		geojson.NewFeature[geojson.Geometry](
+			geojson.NewLineString(square[0], square[1], square[2:]...),
+			geojson.Property{Name: "highway", Value: "service"},
+		),
+		geojson.NewFeature[geojson.Geometry](
+			geojson.NewMultiPolygon(
+				[][]geojson.Position{
+					{
+						geojson.MakePosition(0, 0),
+						geojson.MakePosition(10, 0),
+						geojson.MakePosition(10, 10),
+						geojson.MakePosition(0, 10),
+						geojson.MakePosition(0, 0),
+					},
+					square,
+				},
+			),
+			geojson.Property{Name: "type", Value: "multipolygon"},
+			geojson.Property{Name: "landuse", Value: "forest"},
+		),
+	), c)
+}
+
+func TestReadOSMAreas(t *testing.T) {
+	for tags, isArea := range map[string]bool{
+		`<tag k="building" v="house"/>`:                            true,
+		`<tag k="natural" v="wood"/>`:                              true,
+		`<tag k="natural" v="coastline"/>`:                         false,
+		`<tag k="waterway" v="riverbank"/>`:                        true,
+		`<tag k="waterway" v="river"/>`:                            false,
+		`<tag k="highway" v="pedestrian"/>`:                        false,
+		`<tag k="highway" v="pedestrian"/><tag k="area" v="yes"/>`: true,
+		`<tag k="building" v="yes"/><tag k="area" v="no"/>`:        false,
+		`<tag k="building" v="no"/>`:                               false,
+	} {
+		t.Run(tags, func(t *testing.T) {
+			c, err := geojson.ReadOSM(strings.NewReader(`<osm>
+  <node id="1" lat="0" lon="0"/>
+  <node id="2" lat="1" lon="0"/>
+  <node id="3" lat="1" lon="1"/>
+  <way id="1"><nd ref="1"/><nd ref="2"/><nd ref="3"/><nd ref="1"/>` + tags + `</way>
+</osm>`))
+			require.NoError(t, err)
+
+			data, err := c.MarshalJSON()
+			require.NoError(t, err)
+			if isArea {
+				require.Contains(t, string(data), `"Polygon"`)
+			} else {
+				require.Contains(t, string(data), `"LineString"`)
+			}
+		})
+	}
+}
+
+func TestReadOSMInvalid(t *testing.T) {
+	_, err := geojson.ReadOSM(strings.NewReader(`<osm><node id="a" lat="0" lon="0"/></osm>`))
+	require.Error(t, err)
+}
@@ -0,0 +1,631 @@
+package geojson
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"runtime"
+)
+
+// OSMPBFOptions configures the reading of an OpenStreetMap PBF file.
+type OSMPBFOptions struct {
+	// Filter reports whether an element with the tags is converted to a feature. If nil, every tagged element is converted.
+	// It is called concurrently, and is only called for elements with tags.
+	Filter func(tags PropertyList) bool
+	// Concurrency is the number of blocks that are decoded at once, which defaults to GOMAXPROCS.
+	Concurrency int
+}
+
+const (
+	osmPBFMaxHeaderSize = 64 << 10
+	osmPBFMaxBlobSize   = 32 << 20
+
+	osmPBFDefaultGranularity = 100
+)
+
+// OSM PBF member types.
+const (
+	osmPBFNode = iota
+	osmPBFWay
+	osmPBFRelation
+)
+
+// ReadOSMPBF reads an OpenStreetMap PBF file, returning the same features as ReadOSM.
+// Blocks are decompressed and decoded concurrently, and only raw and zlib-compressed blocks are supported.
+func ReadOSMPBF(r io.Reader, opts OSMPBFOptions) (FeatureCollection, error) {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = runtime.GOMAXPROCS(0)
+	}
+
+	type result struct {
+		block *osmBlock
+		err   error
+	}
+
+	// Blocks are decoded concurrently, but their results are received in order so that features are in file order.
+	pending := make(chan chan result, opts.Concurrency)
+	stop := make(chan struct{})
+	defer close(stop)
+
+	go func() {
+		defer close(pending)
+		for {
+			kind, blob, err := readOSMPBFBlob(r)
+			if err == io.EOF {
+				return
+			}
+
+			done := make(chan result, 1)
+			select {
+			case pending <- done:
+			case <-stop:
+				return
+			}
+
+			if err != nil {
+				done <- result{err: err}
+				return
+			}
+
+			go func() {
+				block, err := decodeOSMPBFBlob(kind, blob, opts.Filter)
+				done <- result{block: block, err: err}
+			}()
+		}
+	}()
+
+	data := newOSMData()
+	for done := range pending {
+		res := <-done
+		if res.err != nil {
+			return FeatureCollection{}, res.err
+		} else if res.block != nil {
+			res.block.addTo(data)
+		}
+	}
+	return NewFeatureCollection(data.features()...), nil
+}
+
+// readOSMPBFBlob reads the next blob and returns its type, or io.EOF if there are none left.
+// Only a file that ends before the size of a blob header is complete, any other EOF is unexpected.
+func readOSMPBFBlob(r io.Reader) (string, []byte, error) {
+	var size [4]byte
+	if _, err := io.ReadFull(r, size[:]); err != nil {
+		return "", nil, err
+	}
+
+	n := binary.BigEndian.Uint32(size[:])
+	if n > osmPBFMaxHeaderSize {
+		return "", nil, fmt.Errorf("blob header size %d is too large", n)
+	}
+
+	header := make([]byte, n)
+	if _, err := io.ReadFull(r, header); err == io.EOF {
+		return "", nil, io.ErrUnexpectedEOF
+	} else if err != nil {
+		return "", nil, err
+	}
+
+	var kind string
+	var dataSize uint64
+	hr := protoReader{data: header}
+	for hr.more() {
+		field, wire, err := hr.next()
+		if err != nil {
+			return "", nil, err
+		}
+
+		switch {
+		case field == 1 && wire == protoBytes:
+			b, err := hr.bytes()
+			if err != nil {
+				return "", nil, err
+			}
+			kind = string(b)
+		case field == 3 && wire == protoVarint:
+			if dataSize, err = hr.varint(); err != nil {
+				return "", nil, err
+			}
+		default:
+			if err := hr.skip(wire); err != nil {
+				return "", nil, err
+			}
+		}
+	}
+
+	if dataSize > osmPBFMaxBlobSize {
+		return "", nil, fmt.Errorf("blob size %d is too large", dataSize)
+	}
+
+	blob := make([]byte, dataSize)
+	if _, err := io.ReadFull(r, blob); err == io.EOF {
+		return "", nil, io.ErrUnexpectedEOF
+	} else if err != nil {
+		return "", nil, err
+	}
+	return kind, blob, nil
+}
+
+// decodeOSMPBFBlob decompresses and decodes a blob, returning nil if it doesn't contain OSM data.
+func decodeOSMPBFBlob(kind string, blob []byte, filter func(PropertyList) bool) (*osmBlock, error) {
+	data, err := decompressOSMPBFBlob(blob)
+	if err != nil {
+		return nil, err
+	}
+
+	switch kind {
+	case "OSMHeader":
+		return nil, checkOSMPBFHeader(data)
+	case "OSMData":
+		return decodeOSMPBFPrimitiveBlock(data, filter)
+	default:
+		return nil, nil
+	}
+}
+
+func decompressOSMPBFBlob(blob []byte) ([]byte, error) {
+	var rawSize uint64
+	r := protoReader{data: blob}
+	for r.more() {
+		field, wire, err := r.next()
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case field == 1 && wire == protoBytes:
+			return r.bytes()
+		case field == 2 && wire == protoVarint:
+			if rawSize, err = r.varint(); err != nil {
+				return nil, err
+			} else if rawSize > osmPBFMaxBlobSize {
+				return nil, fmt.Errorf("uncompressed blob size %d is too large", rawSize)
+			}
+		case field == 3 && wire == protoBytes:
+			compressed, err := r.bytes()
+			if err != nil {
+				return nil, err
+			}
+
+			zr, err := zlib.NewReader(bytes.NewReader(compressed))
+			if err != nil {
+				return nil, err
+			}
+
+			var buf bytes.Buffer
+			buf.Grow(int(rawSize))
+			if _, err := io.Copy(&buf, io.LimitReader(zr, osmPBFMaxBlobSize+1)); err != nil {
+				return nil, err
+			} else if buf.Len() > osmPBFMaxBlobSize {
+				return nil, fmt.Errorf("uncompressed blob is too large")
+			}
+			return buf.Bytes(), nil
+		case field >= 4 && field <= 7:
+			return nil, fmt.Errorf("unsupported blob compression")
+		default:
+			if err := r.skip(wire); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return nil, fmt.Errorf("blob has no data")
+}
+
+// checkOSMPBFHeader returns an error if the file requires features that aren't supported.
+func checkOSMPBFHeader(data []byte) error {
+	r := protoReader{data: data}
+	for r.more() {
+		field, wire, err := r.next()
+		if err != nil {
+			return err
+		}
+
+		if field == 4 && wire == protoBytes {
+			feature, err := r.bytes()
+			if err != nil {
+				return err
+			}
+
+			switch string(feature) {
+			case "OsmSchema-V0.6", "DenseNodes":
+			default:
+				return fmt.Errorf("unsupported required feature '%s'", feature)
+			}
+		} else if err := r.skip(wire); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// osmBlock holds the elements decoded from a block, before they are added to the data in file order.
+type osmBlock struct {
+	nodes     []osmBlockNode
+	ways      []osmBlockWay
+	relations []osmBlockRelation
+}
+
+type osmBlockNode struct {
+	id   int64
+	pos  Position
+	tags []Property
+}
+
+type osmBlockWay struct {
+	id   int64
+	refs []int64
+	tags []Property
+}
+
+type osmBlockRelation struct {
+	id      int64
+	members []osmMember
+	tags    []Property
+}
+
+func (b *osmBlock) addTo(data *osmData) {
+	for _, node := range b.nodes {
+		data.addNode(node.id, node.pos, node.tags)
+	}
+	for _, way := range b.ways {
+		data.addWay(way.id, way.refs, way.tags)
+	}
+	for _, relation := range b.relations {
+		data.addRelation(relation.id, relation.members, relation.tags)
+	}
+}
+
+// osmPBFDecoder decodes the groups of a primitive block.
+type osmPBFDecoder struct {
+	block       osmBlock
+	strings     []string
+	granularity int64
+	latOffset   int64
+	lonOffset   int64
+	filter      func(PropertyList) bool
+}
+
+func decodeOSMPBFPrimitiveBlock(data []byte, filter func(PropertyList) bool) (*osmBlock, error) {
+	d := osmPBFDecoder{
+		granularity: osmPBFDefaultGranularity,
+		filter:      filter,
+	}
+
+	var groups [][]byte
+	r := protoReader{data: data}
+	for r.more() {
+		field, wire, err := r.next()
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case field == 1 && wire == protoBytes:
+			table, err := r.message()
+			if err != nil {
+				return nil, err
+			}
+
+			for table.more() {
+				field, wire, err := table.next()
+				if err != nil {
+					return nil, err
+				} else if field != 1 || wire != protoBytes {
+					if err := table.skip(wire); err != nil {
+						return nil, err
+					}
+					continue
+				}
+
+				s, err := table.bytes()
+				if err != nil {
+					return nil, err
+				}
+				d.strings = append(d.strings, string(s))
+			}
+		case field == 2 && wire == protoBytes:
+			group, err := r.bytes()
+			if err != nil {
+				return nil, err
+			}
+			groups = append(groups, group)
+		case field == 17 && wire == protoVarint:
+			v, err := r.varint()
+			if err != nil {
+				return nil, err
+			}
+			d.granularity = int64(v)
+		case field == 19 && wire == protoVarint:
+			v, err := r.varint()
+			if err != nil {
+				return nil, err
+			}
+			d.latOffset = int64(v)
+		case field == 20 && wire == protoVarint:
+			v, err := r.varint()
+			if err != nil {
+				return nil, err
+			}
+			d.lonOffset = int64(v)
+		default:
+			if err := r.skip(wire); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	for _, group := range groups {
+		if err := d.group(group); err != nil {
+			return nil, err
+		}
+	}
+	return &d.block, nil
+}
+
+func (d *osmPBFDecoder) group(data []byte) error {
+	r := protoReader{data: data}
+	for r.more() {
+		field, wire, err := r.next()
+		if err != nil {
+			return err
+		} else if wire != protoBytes || field < 1 || field > 4 {
+			if err := r.skip(wire); err != nil {
+				return err
+			}
+			continue
+		}
+
+		msg, err := r.bytes()
+		if err != nil {
+			return err
+		}
+
+		switch field {
+		case 1:
+			err = d.node(msg)
+		case 2:
+			err = d.denseNodes(msg)
+		case 3:
+			err = d.way(msg)
+		case 4:
+			err = d.relation(msg)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (d *osmPBFDecoder) node(data []byte) error {
+	var id, lat, lon int64
+	var keys, vals []uint64
+	r := protoReader{data: data}
+	for r.more() {
+		field, wire, err := r.next()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case field == 1 && wire == protoVarint:
+			id, err = r.sint()
+		case field == 2:
+			keys, err = r.varints(wire, keys)
+		case field == 3:
+			vals, err = r.varints(wire, vals)
+		case field == 8 && wire == protoVarint:
+			lat, err = r.sint()
+		case field == 9 && wire == protoVarint:
+			lon, err = r.sint()
+		default:
+			err = r.skip(wire)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	tags, err := d.tags(keys, vals)
+	if err != nil {
+		return err
+	}
+
+	d.block.nodes = append(d.block.nodes, osmBlockNode{id: id, pos: d.position(lat, lon), tags: tags})
+	return nil
+}
+
+func (d *osmPBFDecoder) denseNodes(data []byte) error {
+	var ids, lats, lons []int64
+	var keysVals []uint64
+	r := protoReader{data: data}
+	for r.more() {
+		field, wire, err := r.next()
+		if err != nil {
+			return err
+		}
+
+		switch field {
+		case 1:
+			ids, err = r.sints(wire, ids)
+		case 8:
+			lats, err = r.sints(wire, lats)
+		case 9:
+			lons, err = r.sints(wire, lons)
+		case 10:
+			keysVals, err = r.varints(wire, keysVals)
+		default:
+			err = r.skip(wire)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(lats) != len(ids) || len(lons) != len(ids) {
+		return fmt.Errorf("dense nodes have %d IDs but %d latitudes and %d longitudes", len(ids), len(lats), len(lons))
+	}
+
+	var id, lat, lon int64
+	for i := range ids {
+		id, lat, lon = id+ids[i], lat+lats[i], lon+lons[i]
+
+		// Tags are stored as alternating keys and values, with each node's terminated by 0.
+		var keys, vals []uint64
+		for len(keysVals) != 0 {
+			if keysVals[0] == 0 {
+				keysVals = keysVals[1:]
+				break
+			} else if len(keysVals) < 2 {
+				return fmt.Errorf("dense node %d has a key without a value", id)
+			}
+			keys, vals = append(keys, keysVals[0]), append(vals, keysVals[1])
+			keysVals = keysVals[2:]
+		}
+
+		tags, err := d.tags(keys, vals)
+		if err != nil {
+			return err
+		}
+		d.block.nodes = append(d.block.nodes, osmBlockNode{id: id, pos: d.position(lat, lon), tags: tags})
+	}
+	return nil
+}
+
+func (d *osmPBFDecoder) way(data []byte) error {
+	var id uint64
+	var keys, vals []uint64
+	var refs []int64
+	r := protoReader{data: data}
+	for r.more() {
+		field, wire, err := r.next()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case field == 1 && wire == protoVarint:
+			id, err = r.varint()
+		case field == 2:
+			keys, err = r.varints(wire, keys)
+		case field == 3:
+			vals, err = r.varints(wire, vals)
+		case field == 8:
+			refs, err = r.sints(wire, refs)
+		default:
+			err = r.skip(wire)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	tags, err := d.tags(keys, vals)
+	if err != nil {
+		return err
+	}
+
+	for i := 1; i < len(refs); i++ {
+		refs[i] += refs[i-1]
+	}
+	d.block.ways = append(d.block.ways, osmBlockWay{id: int64(id), refs: refs, tags: tags})
+	return nil
+}
+
+func (d *osmPBFDecoder) relation(data []byte) error {
+	var id uint64
+	var keys, vals, roles, types []uint64
+	var memberIDs []int64
+	r := protoReader{data: data}
+	for r.more() {
+		field, wire, err := r.next()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case field == 1 && wire == protoVarint:
+			id, err = r.varint()
+		case field == 2:
+			keys, err = r.varints(wire, keys)
+		case field == 3:
+			vals, err = r.varints(wire, vals)
+		case field == 8:
+			roles, err = r.varints(wire, roles)
+		case field == 9:
+			memberIDs, err = r.sints(wire, memberIDs)
+		case field == 10:
+			types, err = r.varints(wire, types)
+		default:
+			err = r.skip(wire)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	tags, err := d.tags(keys, vals)
+	if err != nil {
+		return err
+	} else if len(roles) != len(memberIDs) || len(types) != len(memberIDs) {
+		return fmt.Errorf("relation %d has %d members but %d roles and %d types", id, len(memberIDs), len(roles), len(types))
+	}
+
+	members := make([]osmMember, len(memberIDs))
+	var ref int64
+	for i := range members {
+		ref += memberIDs[i]
+		role, err := d.string(roles[i])
+		if err != nil {
+			return err
+		}
+		members[i] = osmMember{isWay: types[i] == osmPBFWay, ref: ref, role: role}
+	}
+
+	d.block.relations = append(d.block.relations, osmBlockRelation{id: int64(id), members: members, tags: tags})
+	return nil
+}
+
+// tags returns the tags with the keys and values in the string table, or nil if they are rejected by the filter.
+func (d *osmPBFDecoder) tags(keys, vals []uint64) ([]Property, error) {
+	if len(keys) != len(vals) {
+		return nil, fmt.Errorf("element has %d keys but %d values", len(keys), len(vals))
+	} else if len(keys) == 0 {
+		return nil, nil
+	}
+
+	tags := make([]Property, len(keys))
+	for i := range keys {
+		key, err := d.string(keys[i])
+		if err != nil {
+			return nil, err
+		}
+
+		value, err := d.string(vals[i])
+		if err != nil {
+			return nil, err
+		}
+		tags[i] = Property{Name: key, Value: value}
+	}
+
+	if d.filter != nil && !d.filter(tags) {
+		return nil, nil
+	}
+	return tags, nil
+}
+
+func (d *osmPBFDecoder) string(i uint64) (string, error) {
+	if i >= uint64(len(d.strings)) {
+		return "", fmt.Errorf("string index %d is out of range", i)
+	}
+	return d.strings[i], nil
+}
+
+func (d *osmPBFDecoder) position(lat, lon int64) Position {
+	return MakePosition(
+		float64(d.latOffset+d.granularity*lat)/1e9,
+		float64(d.lonOffset+d.granularity*lon)/1e9,
+	)
+}
@@ -0,0 +1,232 @@
+package geojson_test
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"io"
+	"strings"
+	"testing"
+
+	geojson "github.com/everystreet/go-geojson/v3"
+	"github.com/stretchr/testify/require"
+)
+
+const osmPBFTestXML = `<osm>
+  <node id="1" lat="0" lon="0"/>
+  <node id="2" lat="0" lon="10"/>
+  <node id="3" lat="10" lon="10"><tag k="amenity" v="cafe"/></node>
+  <node id="4" lat="10" lon="0"/>
+  <way id="10"><nd ref="1"/><nd ref="2"/><nd ref="3"/><tag k="highway" v="path"/></way>
+  <way id="11"><nd ref="3"/><nd ref="4"/><nd ref="1"/></way>
+  <relation id="20">
+    <member type="way" ref="10" role="outer"/>
+    <member type="way" ref="11" role="outer"/>
+    <member type="node" ref="3" role="label"/>
+    <tag k="type" v="multipolygon"/>
+    <tag k="landuse" v="forest"/>
+  </relation>
+  <node id="-5" lat="51.5" lon="-0.125"><tag k="name" v="London"/></node>
+</osm>`
+
+func TestReadOSMPBF(t *testing.T) {
+	expected, err := geojson.ReadOSM(strings.NewReader(osmPBFTestXML))
+	require.NoError(t, err)
+
+	for _, concurrency := range []int{0, 1, 3} {
+		c, err := geojson.ReadOSMPBF(bytes.NewReader(osmPBFTestFile()), geojson.OSMPBFOptions{Concurrency: concurrency})
+		require.NoError(t, err)
+		require.Equal(t, expected, c)
+	}
+}
+
+func TestReadOSMPBFFilter(t *testing.T) {
+	c, err := geojson.ReadOSMPBF(bytes.NewReader(osmPBFTestFile()), geojson.OSMPBFOptions{
+		Filter: func(tags geojson.PropertyList) bool {
+			_, ok := tags.Get("landuse")
+			return ok
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, geojson.NewFeatureCollection(
+		geojson.NewFeature[geojson.Geometry](
+			geojson.NewMultiPolygon(
+				[][]geojson.Position{
+					{
+						geojson.MakePosition(0, 0),
+						geojson.MakePosition(10, 0),
+						geojson.MakePosition(10, 10),
+						geojson.MakePosition(0, 10),
+						geojson.MakePosition(0, 0),
+					},
+				},
+			),
+			geojson.Property{Name: "type", Value: "multipolygon"},
+			geojson.Property{Name: "landuse", Value: "forest"},
+		),
+	), c)
+}
+
+func TestReadOSMPBFInvalid(t *testing.T) {
+	for name, data := range map[string][]byte{
+		"unsupported feature":     osmPBFBlob("OSMHeader", osmPBFHeader("OsmSchema-V0.6", "HistoricalInformation"), false),
+		"unsupported compression": osmPBFFileBlock("OSMData", osmPBFBytes(4, []byte{0})),
+		"truncated":               osmPBFTestFile()[:40],
+		"invalid string index": osmPBFBlob("OSMData", osmPBFMessage(
+			osmPBFBytes(1, osmPBFBytes(1, nil)),
+			osmPBFBytes(2, osmPBFBytes(1, osmPBFMessage(osmPBFVarint(1, 2), osmPBFPacked(2, 5), osmPBFPacked(3, 0)))),
+		), false),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := geojson.ReadOSMPBF(bytes.NewReader(data), geojson.OSMPBFOptions{})
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestReadOSMPBFTruncated(t *testing.T) {
+	file := osmPBFTestFile()
+	header := 4 + int(binary.BigEndian.Uint32(file))
+	blob := len(osmPBFBlob("OSMHeader", osmPBFHeader("OsmSchema-V0.6", "DenseNodes"), false))
+
+	for name, n := range map[string]int{
+		"size":         2,
+		"after size":   4,
+		"header":       header - 1,
+		"after header": header,
+		"blob":         blob - 1,
+		"next size":    blob + 4,
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := geojson.ReadOSMPBF(bytes.NewReader(file[:n]), geojson.OSMPBFOptions{})
+			require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+		})
+	}
+}
+
+// osmPBFTestFile returns the PBF encoding of osmPBFTestXML.
+func osmPBFTestFile() []byte {
+	strs := osmPBFMessage(
+		osmPBFBytes(1, nil),
+		osmPBFBytes(1, []byte("amenity")),
+		osmPBFBytes(1, []byte("cafe")),
+		osmPBFBytes(1, []byte("highway")),
+		osmPBFBytes(1, []byte("path")),
+		osmPBFBytes(1, []byte("outer")),
+		osmPBFBytes(1, []byte("type")),
+		osmPBFBytes(1, []byte("multipolygon")),
+		osmPBFBytes(1, []byte("landuse")),
+		osmPBFBytes(1, []byte("forest")),
+		osmPBFBytes(1, []byte("label")),
+	)
+
+	// Coordinates are stored in microdegrees, offset by 10 degrees of latitude.
+	dense := osmPBFMessage(
+		osmPBFPackedSints(1, 1, 1, 1, 1),
+		osmPBFPackedSints(8, -10_000_000, 0, 10_000_000, 0),
+		osmPBFPackedSints(9, 0, 10_000_000, 0, -10_000_000),
+		osmPBFPacked(10, 0, 0, 1, 2, 0, 0),
+	)
+	way10 := osmPBFMessage(osmPBFVarint(1, 10), osmPBFPacked(2, 3), osmPBFPacked(3, 4), osmPBFPackedSints(8, 1, 1, 1))
+	way11 := osmPBFMessage(osmPBFVarint(1, 11), osmPBFPackedSints(8, 3, 1, -3))
+	relation := osmPBFMessage(
+		osmPBFVarint(1, 20),
+		osmPBFPacked(2, 6, 8),
+		osmPBFPacked(3, 7, 9),
+		osmPBFPacked(8, 5, 5, 10),
+		osmPBFPackedSints(9, 10, 1, -8),
+		osmPBFPacked(10, 1, 1, 0),
+	)
+
+	block := osmPBFMessage(
+		osmPBFBytes(1, strs),
+		osmPBFBytes(2, osmPBFBytes(2, dense)),
+		osmPBFBytes(2, osmPBFMessage(osmPBFBytes(3, way10), osmPBFBytes(3, way11))),
+		osmPBFBytes(2, osmPBFBytes(4, relation)),
+		osmPBFVarint(17, 1000),
+		osmPBFVarint(19, uint64(10_000_000_000)),
+	)
+
+	// Zigzag encoded ID and coordinates, in units of 100 nanodegrees.
+	node := osmPBFMessage(
+		osmPBFVarint(1, 9),
+		osmPBFPacked(2, 1),
+		osmPBFPacked(3, 2),
+		osmPBFVarint(8, 1_030_000_000),
+		osmPBFVarint(9, 2_499_999),
+	)
+	nodeBlock := osmPBFMessage(
+		osmPBFBytes(1, osmPBFMessage(osmPBFBytes(1, nil), osmPBFBytes(1, []byte("name")), osmPBFBytes(1, []byte("London")))),
+		osmPBFBytes(2, osmPBFBytes(1, node)),
+	)
+
+	var file []byte
+	file = append(file, osmPBFBlob("OSMHeader", osmPBFHeader("OsmSchema-V0.6", "DenseNodes"), false)...)
+	file = append(file, osmPBFBlob("OSMData", block, true)...)
+	file = append(file, osmPBFBlob("Unknown", []byte{1, 2, 3}, false)...)
+	file = append(file, osmPBFBlob("OSMData", nodeBlock, false)...)
+	return file
+}
+
+func osmPBFHeader(features ...string) []byte {
+	var header []byte
+	for _, f := range features {
+		header = append(header, osmPBFBytes(4, []byte(f))...)
+	}
+	return header
+}
+
+func osmPBFBlob(kind string, data []byte, compress bool) []byte {
+	var blob []byte
+	if compress {
+		var buf bytes.Buffer
+		w := zlib.NewWriter(&buf)
+		_, _ = w.Write(data)
+		_ = w.Close()
+		blob = osmPBFMessage(osmPBFVarint(2, uint64(len(data))), osmPBFBytes(3, buf.Bytes()))
+	} else {
+		blob = osmPBFBytes(1, data)
+	}
+	return osmPBFFileBlock(kind, blob)
+}
+
+// osmPBFFileBlock returns the blob preceded by its header.
+func osmPBFFileBlock(kind string, blob []byte) []byte {
+	header := osmPBFMessage(osmPBFBytes(1, []byte(kind)), osmPBFVarint(3, uint64(len(blob))))
+	b := binary.BigEndian.AppendUint32(nil, uint32(len(header)))
+	return append(append(b, header...), blob...)
+}
+
+func osmPBFMessage(fields ...[]byte) []byte {
+	var b []byte
+	for _, f := range fields {
+		b = append(b, f...)
+	}
+	return b
+}
+
+func osmPBFVarint(field int, v uint64) []byte {
+	return binary.AppendUvarint(binary.AppendUvarint(nil, uint64(field)<<3), v)
+}
+
+func osmPBFBytes(field int, data []byte) []byte {
+	b := binary.AppendUvarint(nil, uint64(field)<<3|2)
+	b = binary.AppendUvarint(b, uint64(len(data)))
+	return append(b, data...)
+}
+
+func osmPBFPacked(field int, values ...uint64) []byte {
+	var packed []byte
+	for _, v := range values {
+		packed = binary.AppendUvarint(packed, v)
+	}
+	return osmPBFBytes(field, packed)
+}
+
+func osmPBFPackedSints(field int, values ...int64) []byte {
+	zigzag := make([]uint64, len(values))
+	for i, v := range values {
+		zigzag[i] = uint64(v<<1) ^ uint64(v>>63)
+	}
+	return osmPBFPacked(field, zigzag...)
+}
@@ -2,21 +2,32 @@ package geojson
 
 import (
 	"encoding/json"
+	"fmt"
 )
 
 // Point is a single set of Position.
-type Point Position
+// A Point can't be empty, so it is an error to unmarshal one with empty coordinates,
+// except as the geometry of a Feature, where it is interpreted as a null geometry.
+type Point struct {
+	pos Position
+
+	// Foreign members are stored behind a pointer, which is nil if there are none, so that Point remains comparable.
+	foreign *map[string]json.RawMessage
+}
 
 // NewPoint returns a point with the specified longitude and latitude.
 func NewPoint(lat, lng float64) *Point {
-	pos := MakePosition(lat, lng)
-	return (*Point)(&pos)
+	return NewPointFromPosition(MakePosition(lat, lng))
 }
 
 // NewPointWithElevation returns a Point Feature with the specified longitude, latitude and elevation.
 func NewPointWithElevation(lat, lng, elevation float64) *Point {
-	pos := MakePositionWithElevation(lat, lng, elevation)
-	return (*Point)(&pos)
+	return NewPointFromPosition(MakePositionWithElevation(lat, lng, elevation))
+}
+
+// NewPointFromPosition returns a point at the supplied position.
+func NewPointFromPosition(pos Position) *Point {
+	return &Point{pos: pos}
 }
 
 // Type returns the geometry type.
@@ -24,39 +35,78 @@ func (p Point) Type() GeometryType {
 	return PointGeometryType
 }
 
+// Position returns the position of the point.
+func (p Point) Position() Position {
+	return p.pos
+}
+
+// ForeignMembers returns a copy of the members of the point that aren't defined by RFC 7946, or nil if there are none.
+func (p Point) ForeignMembers() map[string]json.RawMessage {
+	if p.foreign == nil {
+		return nil
+	}
+	return copyForeignMembers(*p.foreign)
+}
+
+// WithForeignMember returns a copy of p with the supplied foreign member, which replaces any existing member with the same name.
+// The name must not be one of the members defined by RFC 7946 for a geometry.
+func (p Point) WithForeignMember(name string, value json.RawMessage) *Point {
+	foreign := withForeignMember(p.ForeignMembers(), name, value)
+	p.foreign = &foreign
+	return &p
+}
+
 // Validate the Point.
 func (p Point) Validate() error {
 	return nil
 }
 
 // MarshalJSON returns the JSON encoding of the Point.
+// Foreign members follow the members defined by RFC 7946, sorted by name.
 func (p Point) MarshalJSON() ([]byte, error) {
-	return json.Marshal(geometry{
-		Type:        PointGeometryType,
-		Coordinates: Position(p),
-	})
+	return marshalGeometry(PointGeometryType, p.pos, p.ForeignMembers())
 }
 
 // UnmarshalJSON parses the JSON-encoded data and stores the result.
 func (p *Point) UnmarshalJSON(data []byte) error {
 	var geo struct {
-		Coordinates Position `json:"coordinates"`
+		Coordinates []json.RawMessage `json:"coordinates"`
 	}
 
 	if err := json.Unmarshal(data, &geo); err != nil {
 		return err
+	} else if len(geo.Coordinates) == 0 {
+		return errEmptyPoint
+	}
+
+	var pos struct {
+		Coordinates Position `json:"coordinates"`
+	}
+	if err := json.Unmarshal(data, &pos); err != nil {
+		return err
 	}
 
-	*p = Point(geo.Coordinates)
+	foreign, err := unmarshalForeignMembers(data, geometryMembers)
+	if err != nil {
+		return err
+	}
+
+	*p = Point{pos: pos.Coordinates}
+	if foreign != nil {
+		p.foreign = &foreign
+	}
 	return nil
 }
 
-// MultiPoint is a set of Position.
-type MultiPoint []Position
+// MultiPoint is a set of Position, which is empty if there are none.
+type MultiPoint struct {
+	positions []Position
+	foreign   map[string]json.RawMessage
+}
 
 // NewMultiPoint returns a multipoint with the specified set of positions.
 func NewMultiPoint(pos ...Position) *MultiPoint {
-	return (*MultiPoint)(&pos)
+	return &MultiPoint{positions: pos}
 }
 
 // Type returns the geometry type.
@@ -64,17 +114,36 @@ func (m MultiPoint) Type() GeometryType {
 	return MultiPointGeometryType
 }
 
+// Positions returns the positions of the points.
+func (m MultiPoint) Positions() []Position {
+	return m.positions
+}
+
+// ForeignMembers returns a copy of the members of the multipoint that aren't defined by RFC 7946, or nil if there are none.
+func (m MultiPoint) ForeignMembers() map[string]json.RawMessage {
+	return copyForeignMembers(m.foreign)
+}
+
+// WithForeignMember returns a copy of m with the supplied foreign member, which replaces any existing member with the same name.
+// The name must not be one of the members defined by RFC 7946 for a geometry.
+func (m MultiPoint) WithForeignMember(name string, value json.RawMessage) *MultiPoint {
+	m.foreign = withForeignMember(m.foreign, name, value)
+	return &m
+}
+
 // Validate the MultiPoint.
 func (m MultiPoint) Validate() error {
 	return nil
 }
 
 // MarshalJSON returns the JSON encoding of the MultiPoint.
+// Foreign members follow the members defined by RFC 7946, sorted by name.
 func (m MultiPoint) MarshalJSON() ([]byte, error) {
-	return json.Marshal(geometry{
-		Type:        MultiPointGeometryType,
-		Coordinates: []Position(m),
-	})
+	positions := m.positions
+	if positions == nil {
+		positions = []Position{}
+	}
+	return marshalGeometry(MultiPointGeometryType, positions, m.foreign)
 }
 
 // UnmarshalJSON parses the JSON-encoded data and stores the result.
@@ -87,6 +156,13 @@ func (m *MultiPoint) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
-	*m = MultiPoint(geo.Coordinates)
+	foreign, err := unmarshalForeignMembers(data, geometryMembers)
+	if err != nil {
+		return err
+	}
+
+	*m = MultiPoint{positions: geo.Coordinates, foreign: foreign}
 	return nil
 }
+
+var errEmptyPoint = fmt.Errorf("empty point can't be represented")
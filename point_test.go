@@ -85,3 +85,13 @@ func TestMultiPoint(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, feature, unmarshalled)
 }
+
+func TestPointEmpty(t *testing.T) {
+	var point geojson.Point
+	err := json.Unmarshal([]byte(`{"type": "Point", "coordinates": []}`), &point)
+	require.Error(t, err)
+
+	var collection geojson.GeometryCollection
+	err = json.Unmarshal([]byte(`{"type": "GeometryCollection", "geometries": [{"type": "Point", "coordinates": []}]}`), &collection)
+	require.Error(t, err)
+}
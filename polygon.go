@@ -5,12 +5,15 @@ import (
 	"fmt"
 )
 
-// Polygon is a set of linear rings (closed LineStrings).
-type Polygon [][]Position
+// Polygon is a set of linear rings (closed LineStrings), which is empty if there are none.
+type Polygon struct {
+	rings   [][]Position
+	foreign map[string]json.RawMessage
+}
 
 // NewPolygon returns a new Polygon from the supplied linear rings.
 func NewPolygon(rings ...[]Position) *Polygon {
-	return (*Polygon)(&rings)
+	return &Polygon{rings: rings}
 }
 
 // Type returns the geometry type.
@@ -18,30 +21,36 @@ func (p Polygon) Type() GeometryType {
 	return PolygonGeometryType
 }
 
+// Rings returns the linear rings of the polygon, starting with the exterior ring.
+func (p Polygon) Rings() [][]Position {
+	return p.rings
+}
+
+// ForeignMembers returns a copy of the members of the polygon that aren't defined by RFC 7946, or nil if there are none.
+func (p Polygon) ForeignMembers() map[string]json.RawMessage {
+	return copyForeignMembers(p.foreign)
+}
+
+// WithForeignMember returns a copy of p with the supplied foreign member, which replaces any existing member with the same name.
+// The name must not be one of the members defined by RFC 7946 for a geometry.
+func (p Polygon) WithForeignMember(name string, value json.RawMessage) *Polygon {
+	p.foreign = withForeignMember(p.foreign, name, value)
+	return &p
+}
+
 // Validate the Polygon.
 func (p Polygon) Validate() error {
-	for i, ring := range p {
-		if len(ring) < 4 {
-			return fmt.Errorf("polygon ring is too short - must contain at least 4 positions")
-		} else if ring[len(ring)-1] != ring[0] {
-			return fmt.Errorf("polygon ring must be closed")
-		}
-
-		if angle := LoopToS2(ring).TurningAngle(); i == 0 && angle >= 0 { // CCW
-			return fmt.Errorf("exterior ring must be clockwise but angle is %f", angle)
-		} else if i > 0 && angle <= 0 { // CW
-			return fmt.Errorf("interior ring must be counter-clockwise but angle is %f", angle)
-		}
-	}
-	return nil
+	return validateRings(p.rings)
 }
 
 // MarshalJSON returns the JSON encoding of the Polygon.
+// Foreign members follow the members defined by RFC 7946, sorted by name.
 func (p Polygon) MarshalJSON() ([]byte, error) {
-	return json.Marshal(geometry{
-		Type:        PolygonGeometryType,
-		Coordinates: [][]Position(p),
-	})
+	rings := p.rings
+	if rings == nil {
+		rings = [][]Position{}
+	}
+	return marshalGeometry(PolygonGeometryType, rings, p.foreign)
 }
 
 // UnmarshalJSON parses the JSON-encoded data and stores the result.
@@ -54,16 +63,24 @@ func (p *Polygon) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
-	*p = Polygon(geo.Coordinates)
+	foreign, err := unmarshalForeignMembers(data, geometryMembers)
+	if err != nil {
+		return err
+	}
+
+	*p = Polygon{rings: geo.Coordinates, foreign: foreign}
 	return nil
 }
 
-// MultiPolygon is a set of Polygons.
-type MultiPolygon [][][]Position
+// MultiPolygon is a set of Polygons, which is empty if there are none.
+type MultiPolygon struct {
+	polygons [][][]Position
+	foreign  map[string]json.RawMessage
+}
 
 // NewMultiPolygon returns a new MultiPolygon from the supplied polygons.
 func NewMultiPolygon(p ...[][]Position) *MultiPolygon {
-	return (*MultiPolygon)(&p)
+	return &MultiPolygon{polygons: p}
 }
 
 // Type returns the geometry type.
@@ -71,10 +88,27 @@ func (m MultiPolygon) Type() GeometryType {
 	return MultiPolygonGeometryType
 }
 
+// Polygons returns the linear rings of each polygon.
+func (m MultiPolygon) Polygons() [][][]Position {
+	return m.polygons
+}
+
+// ForeignMembers returns a copy of the members of the multipolygon that aren't defined by RFC 7946, or nil if there are none.
+func (m MultiPolygon) ForeignMembers() map[string]json.RawMessage {
+	return copyForeignMembers(m.foreign)
+}
+
+// WithForeignMember returns a copy of m with the supplied foreign member, which replaces any existing member with the same name.
+// The name must not be one of the members defined by RFC 7946 for a geometry.
+func (m MultiPolygon) WithForeignMember(name string, value json.RawMessage) *MultiPolygon {
+	m.foreign = withForeignMember(m.foreign, name, value)
+	return &m
+}
+
 // Validate the MultiPolygon.
 func (m MultiPolygon) Validate() error {
-	for _, polygon := range m {
-		if err := Polygon(polygon).Validate(); err != nil {
+	for _, polygon := range m.polygons {
+		if err := validateRings(polygon); err != nil {
 			return err
 		}
 	}
@@ -82,11 +116,13 @@ func (m MultiPolygon) Validate() error {
 }
 
 // MarshalJSON returns the JSON encoding of the MultiPolygon.
+// Foreign members follow the members defined by RFC 7946, sorted by name.
 func (m MultiPolygon) MarshalJSON() ([]byte, error) {
-	return json.Marshal(geometry{
-		Type:        MultiPolygonGeometryType,
-		Coordinates: [][][]Position(m),
-	})
+	polygons := m.polygons
+	if polygons == nil {
+		polygons = [][][]Position{}
+	}
+	return marshalGeometry(MultiPolygonGeometryType, polygons, m.foreign)
 }
 
 // UnmarshalJSON parses the JSON-encoded data and stores the result.
@@ -99,6 +135,100 @@ func (m *MultiPolygon) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
-	*m = MultiPolygon(geo.Coordinates)
+	foreign, err := unmarshalForeignMembers(data, geometryMembers)
+	if err != nil {
+		return err
+	}
+
+	*m = MultiPolygon{polygons: geo.Coordinates, foreign: foreign}
 	return nil
 }
+
+// validateRings validates the linear rings of a polygon.
+func validateRings(rings [][]Position) error {
+	for i, ring := range rings {
+		if len(ring) < 4 {
+			return fmt.Errorf("polygon ring is too short - must contain at least 4 positions")
+		} else if !ring[len(ring)-1].Equal(ring[0]) {
+			return fmt.Errorf("polygon ring must be closed")
+		}
+
+		if angle := LoopToS2(ring).TurningAngle(); i == 0 && angle >= 0 { // CCW
+			return fmt.Errorf("exterior ring must be clockwise but angle is %f", angle)
+		} else if i > 0 && angle <= 0 { // CW
+			return fmt.Errorf("interior ring must be counter-clockwise but angle is %f", angle)
+		}
+	}
+	return nil
+}
+
+// assemblePolygons returns polygons with the exterior rings oriented clockwise,
+// and each hole oriented counter-clockwise in the smallest exterior ring that contains it.
+// Holes that aren't contained by any exterior ring become exterior rings.
+func assemblePolygons(exteriors, holes [][]Position) [][][]Position {
+	polygons := make([][][]Position, 0, len(exteriors))
+	areas := make([]float64, 0, len(exteriors))
+	addExterior := func(ring []Position) {
+		area := ringArea(ring)
+		if area > 0 {
+			ring, area = reverseRing(ring), -area
+		}
+		polygons = append(polygons, [][]Position{ring})
+		areas = append(areas, -area)
+	}
+
+	for _, ring := range exteriors {
+		addExterior(ring)
+	}
+
+	for _, hole := range holes {
+		parent := -1
+		if len(hole) != 0 {
+			for i, polygon := range polygons {
+				if (parent == -1 || areas[i] < areas[parent]) && ringContains(polygon[0], hole[0]) {
+					parent = i
+				}
+			}
+		}
+
+		if parent == -1 {
+			addExterior(hole)
+		} else if ringArea(hole) < 0 {
+			polygons[parent] = append(polygons[parent], reverseRing(hole))
+		} else {
+			polygons[parent] = append(polygons[parent], hole)
+		}
+	}
+	return polygons
+}
+
+// ringArea returns the signed planar area of the ring in degrees, which is positive if it is counter-clockwise.
+func ringArea(ring []Position) float64 {
+	var area float64
+	for i := 0; i+1 < len(ring); i++ {
+		area += ring[i].lng*ring[i+1].lat - ring[i+1].lng*ring[i].lat
+	}
+	return area / 2
+}
+
+// ringContains reports whether the position is inside the ring, using the even-odd rule.
+func ringContains(ring []Position, pos Position) bool {
+	x, y := pos.lng, pos.lat
+	var inside bool
+	for i, j := 0, len(ring)-1; i < len(ring); j, i = i, i+1 {
+		xi, yi := ring[i].lng, ring[i].lat
+		xj, yj := ring[j].lng, ring[j].lat
+		if (yi > y) != (yj > y) && x < (xj-xi)*(y-yi)/(yj-yi)+xi {
+			inside = !inside
+		}
+	}
+	return inside
+}
+
+func reverseRing(ring []Position) []Position {
+	reversed := make([]Position, len(ring))
+	for i, pos := range ring {
+		reversed[len(ring)-1-i] = pos
+	}
+	return reversed
+}
@@ -65,6 +65,23 @@ func TestPolygon(t *testing.T) {
 	require.Equal(t, feature, unmarshalled)
 }
 
+func TestPolygonWithElevation(t *testing.T) {
+	polygon := geojson.NewPolygon([]geojson.Position{
+		geojson.MakePositionWithElevation(7, 7, 1),
+		geojson.MakePositionWithElevation(4, 8, 2),
+		geojson.MakePositionWithElevation(3, 4, 3),
+		geojson.MakePositionWithElevation(5, 2, 4),
+		geojson.MakePositionWithElevation(7, 3, 5),
+		geojson.MakePositionWithElevation(7, 7, 1),
+	})
+	require.NoError(t, polygon.Validate())
+
+	polygon.Rings()[0][5] = polygon.Rings()[0][5].WithElevation(6)
+	err := polygon.Validate()
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "must be closed")
+}
+
 func TestMultiPolygon(t *testing.T) {
 	feature := geojson.NewFeature(
 		geojson.NewMultiPolygon(
@@ -0,0 +1,225 @@
+package geojson
+
+import (
+	"fmt"
+	"math"
+	"strings"
+)
+
+const (
+	// Maximum number of decimal digits of encoded polyline coordinates.
+	polylineMaxPrecision = 15
+
+	flexiblePolylineVersion   = 1
+	flexiblePolylineElevation = 3
+	flexiblePolylineAltitude  = 2
+	flexiblePolylineAlphabet  = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_"
+)
+
+// EncodePolyline returns the line in the Encoded Polyline Algorithm Format, with coordinates rounded to the specified number of decimal digits.
+// Google's polyline5 and polyline6 use precisions of 5 and 6 respectively. Elevation is discarded.
+func EncodePolyline(line LineString, precision int) (string, error) {
+	if precision < 0 || precision > polylineMaxPrecision {
+		return "", fmt.Errorf("precision must be between 0 and %d", polylineMaxPrecision)
+	}
+
+	scale := math.Pow10(precision)
+	var b strings.Builder
+	var prev [2]int64
+	for _, pos := range line.positions {
+		values := [2]int64{
+			int64(math.Round(pos.lat * scale)),
+			int64(math.Round(pos.lng * scale)),
+		}
+
+		for i, v := range values {
+			for n := zigzag(v - prev[i]); ; n >>= 5 {
+				if n < 0x20 {
+					b.WriteByte(byte(n) + 63)
+					break
+				}
+				b.WriteByte(byte(n&0x1F|0x20) + 63)
+			}
+		}
+		prev = values
+	}
+	return b.String(), nil
+}
+
+// EncodeMultiPolyline returns each line of the MultiLineString as an encoded polyline.
+func EncodeMultiPolyline(lines MultiLineString, precision int) ([]string, error) {
+	encoded := make([]string, len(lines.lines))
+	for i, line := range lines.lines {
+		s, err := EncodePolyline(LineString{positions: line}, precision)
+		if err != nil {
+			return nil, err
+		}
+		encoded[i] = s
+	}
+	return encoded, nil
+}
+
+// DecodePolyline parses a line in the Encoded Polyline Algorithm Format, with coordinates of the specified number of decimal digits.
+func DecodePolyline(s string, precision int) (*LineString, error) {
+	if precision < 0 || precision > polylineMaxPrecision {
+		return nil, fmt.Errorf("precision must be between 0 and %d", polylineMaxPrecision)
+	}
+
+	scale := math.Pow10(precision)
+	var line LineString
+	var values [2]int64
+	for i := 0; i < len(s); {
+		for j := range values {
+			var n uint64
+			for shift := 0; ; shift += 5 {
+				if i == len(s) {
+					return nil, fmt.Errorf("unexpected end of polyline")
+				} else if s[i] < 63 || s[i] > 126 {
+					return nil, fmt.Errorf("invalid character '%c' at offset %d", s[i], i)
+				} else if shift > 60 {
+					return nil, fmt.Errorf("value at offset %d is too large", i)
+				}
+
+				c := uint64(s[i] - 63)
+				i++
+				n |= (c & 0x1F) << shift
+				if c < 0x20 {
+					break
+				}
+			}
+			values[j] += unzigzag(n)
+		}
+		line.positions = append(line.positions, MakePosition(float64(values[0])/scale, float64(values[1])/scale))
+	}
+	return &line, nil
+}
+
+// EncodeFlexiblePolyline returns the line in HERE's Flexible Polyline format, with coordinates rounded to the specified number of decimal digits.
+// If the positions have elevation, it is encoded as the third dimension with zPrecision decimal digits.
+func EncodeFlexiblePolyline(line LineString, precision int, zPrecision int) (string, error) {
+	if precision < 0 || precision > polylineMaxPrecision {
+		return "", fmt.Errorf("precision must be between 0 and %d", polylineMaxPrecision)
+	} else if zPrecision < 0 || zPrecision > polylineMaxPrecision {
+		return "", fmt.Errorf("elevation precision must be between 0 and %d", polylineMaxPrecision)
+	}
+
+	hasZ, _, err := dimension([][]Position{line.positions})
+	if err != nil {
+		return "", err
+	}
+
+	header := uint64(precision)
+	if hasZ {
+		header |= flexiblePolylineElevation<<4 | uint64(zPrecision)<<7
+	}
+
+	var b strings.Builder
+	writeFlexiblePolylineVarint(&b, flexiblePolylineVersion)
+	writeFlexiblePolylineVarint(&b, header)
+
+	scale, zScale := math.Pow10(precision), math.Pow10(zPrecision)
+	var prev [3]int64
+	for _, pos := range line.positions {
+		values := [3]int64{
+			int64(math.Round(pos.lat * scale)),
+			int64(math.Round(pos.lng * scale)),
+		}
+
+		dims := 2
+		if hasZ {
+			values[2] = int64(math.Round(*pos.elevation * zScale))
+			dims = 3
+		}
+
+		for i := 0; i < dims; i++ {
+			writeFlexiblePolylineVarint(&b, zigzag(values[i]-prev[i]))
+		}
+		prev = values
+	}
+	return b.String(), nil
+}
+
+func writeFlexiblePolylineVarint(b *strings.Builder, n uint64) {
+	for ; n >= 0x20; n >>= 5 {
+		b.WriteByte(flexiblePolylineAlphabet[n&0x1F|0x20])
+	}
+	b.WriteByte(flexiblePolylineAlphabet[n])
+}
+
+// DecodeFlexiblePolyline parses a line in HERE's Flexible Polyline format.
+// An elevation or altitude third dimension is stored as the elevation of each position.
+func DecodeFlexiblePolyline(s string) (*LineString, error) {
+	r := flexiblePolylineReader{s: s}
+	version, err := r.varint()
+	if err != nil {
+		return nil, err
+	} else if version != flexiblePolylineVersion {
+		return nil, fmt.Errorf("unsupported version %d", version)
+	}
+
+	header, err := r.varint()
+	if err != nil {
+		return nil, err
+	}
+
+	precision := int(header & 0x0F)
+	dims := 2
+	switch thirdDim := header >> 4 & 0x07; thirdDim {
+	case 0:
+	case flexiblePolylineElevation, flexiblePolylineAltitude:
+		dims = 3
+	default:
+		return nil, fmt.Errorf("unsupported third dimension %d", thirdDim)
+	}
+
+	scale, zScale := math.Pow10(precision), math.Pow10(int(header>>7&0x0F))
+	var line LineString
+	var values [3]int64
+	for r.more() {
+		for i := 0; i < dims; i++ {
+			n, err := r.varint()
+			if err != nil {
+				return nil, err
+			}
+			values[i] += unzigzag(n)
+		}
+
+		if dims == 3 {
+			line.positions = append(line.positions, MakePositionWithElevation(float64(values[0])/scale, float64(values[1])/scale, float64(values[2])/zScale))
+		} else {
+			line.positions = append(line.positions, MakePosition(float64(values[0])/scale, float64(values[1])/scale))
+		}
+	}
+	return &line, nil
+}
+
+type flexiblePolylineReader struct {
+	s   string
+	pos int
+}
+
+func (r *flexiblePolylineReader) more() bool {
+	return r.pos < len(r.s)
+}
+
+func (r *flexiblePolylineReader) varint() (uint64, error) {
+	var n uint64
+	for shift := 0; ; shift += 5 {
+		if r.pos == len(r.s) {
+			return 0, fmt.Errorf("unexpected end of polyline")
+		} else if shift > 60 {
+			return 0, fmt.Errorf("value at offset %d is too large", r.pos)
+		}
+
+		c := strings.IndexByte(flexiblePolylineAlphabet, r.s[r.pos])
+		if c == -1 {
+			return 0, fmt.Errorf("invalid character '%c' at offset %d", r.s[r.pos], r.pos)
+		}
+
+		r.pos++
+		n |= uint64(c&0x1F) << shift
+		if c < 0x20 {
+			return n, nil
+		}
+	}
+}
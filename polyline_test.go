@@ -0,0 +1,121 @@
+package geojson_test
+
+import (
+	"testing"
+
+	geojson "github.com/everystreet/go-geojson/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPolyline(t *testing.T) {
+	line := geojson.NewLineString(
+		geojson.MakePosition(38.5, -120.2),
+		geojson.MakePosition(40.7, -120.95),
+		geojson.MakePosition(43.252, -126.453),
+	)
+
+	s, err := geojson.EncodePolyline(*line, 5)
+	require.NoError(t, err)
+	require.Equal(t, "_p~iF~ps|U_ulLnnqC_mqNvxq`@", s)
+
+	decoded, err := geojson.DecodePolyline(s, 5)
+	require.NoError(t, err)
+	require.Equal(t, line, decoded)
+
+	s, err = geojson.EncodePolyline(*line, 6)
+	require.NoError(t, err)
+	require.Equal(t, "_izlhA~rlgdF_{geC~ywl@_kwzCn`{nI", s)
+
+	decoded, err = geojson.DecodePolyline(s, 6)
+	require.NoError(t, err)
+	require.Equal(t, line, decoded)
+}
+
+func TestMultiPolyline(t *testing.T) {
+	lines, err := geojson.EncodeMultiPolyline(*geojson.NewMultiLineString(
+		[]geojson.Position{
+			geojson.MakePosition(38.5, -120.2),
+			geojson.MakePosition(40.7, -120.95),
+		},
+		[]geojson.Position{
+			geojson.MakePosition(43.252, -126.453),
+		},
+	), 5)
+	require.NoError(t, err)
+	require.Equal(t, []string{"_p~iF~ps|U_ulLnnqC", "_t~fGfzxbW"}, lines)
+}
+
+func TestDecodePolylineInvalid(t *testing.T) {
+	for name, s := range map[string]string{
+		"truncated":         "_p~iF~ps|U_ulLnnqC_mqN",
+		"invalid character": "_p~iF~ps|U_ulL nnqC",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := geojson.DecodePolyline(s, 5)
+			require.Error(t, err)
+		})
+	}
+
+	_, err := geojson.DecodePolyline("", 16)
+	require.Error(t, err)
+}
+
+func TestFlexiblePolyline(t *testing.T) {
+	line := geojson.NewLineString(
+		geojson.MakePosition(50.10228, 8.69821),
+		geojson.MakePosition(50.10201, 8.69567),
+		geojson.MakePosition(50.10063, 8.6915),
+		geojson.MakePosition(50.09878, 8.68752),
+	)
+
+	s, err := geojson.EncodeFlexiblePolyline(*line, 5, 0)
+	require.NoError(t, err)
+	require.Equal(t, "BFoz5xJ67i1B1B7PzIhaxL7Y", s)
+
+	decoded, err := geojson.DecodeFlexiblePolyline(s)
+	require.NoError(t, err)
+	require.Equal(t, line, decoded)
+}
+
+func TestFlexiblePolylineElevation(t *testing.T) {
+	line := geojson.NewLineString(
+		geojson.MakePositionWithElevation(50.10228, 8.69821, 10),
+		geojson.MakePositionWithElevation(50.10201, 8.69567, 20),
+		geojson.MakePositionWithElevation(50.10063, 8.6915, 30),
+		geojson.MakePositionWithElevation(50.09878, 8.68752, 40),
+	)
+
+	s, err := geojson.EncodeFlexiblePolyline(*line, 5, 0)
+	require.NoError(t, err)
+	require.Equal(t, "B1Boz5xJ67i1BU1B7PUzIhaUxL7YU", s)
+
+	decoded, err := geojson.DecodeFlexiblePolyline(s)
+	require.NoError(t, err)
+	require.Equal(t, line, decoded)
+
+	// Altitude is also decoded as elevation.
+	decoded, err = geojson.DecodeFlexiblePolyline("BlBoz5xJ67i1BU1B7PUzIhaUxL7YU")
+	require.NoError(t, err)
+	require.Equal(t, line, decoded)
+
+	_, err = geojson.EncodeFlexiblePolyline(*geojson.NewLineString(
+		geojson.MakePosition(50.10228, 8.69821),
+		geojson.MakePositionWithElevation(50.10201, 8.69567, 20),
+	), 5, 0)
+	require.Error(t, err)
+}
+
+func TestDecodeFlexiblePolylineInvalid(t *testing.T) {
+	for name, s := range map[string]string{
+		"empty":               "",
+		"unsupported version": "CFoz5xJ67i1B1B7PzIhaxL7Y",
+		"truncated":           "BFoz5xJ67i1B1B7PzI",
+		"invalid character":   "BFoz5xJ67i1B1B7P.zIhaxL7Y",
+		"level":               "BVoz5xJ67i1BU1B7PUzIhaUxL7YU",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := geojson.DecodeFlexiblePolyline(s)
+			require.Error(t, err)
+		})
+	}
+}
@@ -8,55 +8,163 @@ import (
 )
 
 // Position represents a longitude and latitude with optional elevation/altitude.
+// Positions with elevation may also have extra ordinates, such as a measure, which follow the elevation in GeoJSON.
+// Coordinates are stored in degrees exactly as supplied, so that they are unchanged by a round-trip through JSON.
+// Positions are comparable, but == compares the elevation and extra ordinates by reference, whereas Equal compares their values.
 type Position struct {
-	pos       s2.LatLng
+	lat, lng  float64
 	elevation *float64
+
+	// Extra ordinates are stored behind a pointer, which is nil if there are none, so that Position remains comparable.
+	extra *[]float64
 }
 
 // MakePosition from longitude and latitude.
 func MakePosition(lat, lng float64) Position {
 	return Position{
-		pos: s2.LatLngFromDegrees(lat, lng),
+		lat: lat,
+		lng: lng,
 	}
 }
 
 // MakePositionWithElevation from longitude, latitude and elevation.
 func MakePositionWithElevation(lat, lng, elevation float64) Position {
 	return Position{
-		pos:       s2.LatLngFromDegrees(lat, lng),
+		lat:       lat,
+		lng:       lng,
 		elevation: &elevation,
 	}
 }
 
+// Lat returns the latitude in degrees.
+func (p Position) Lat() float64 {
+	return p.lat
+}
+
+// Lng returns the longitude in degrees.
+func (p Position) Lng() float64 {
+	return p.lng
+}
+
+// LatLng returns the position as an s2.LatLng, discarding elevation.
+func (p Position) LatLng() s2.LatLng {
+	return s2.LatLngFromDegrees(p.lat, p.lng)
+}
+
+// HasElevation reports whether the position has an elevation.
+func (p Position) HasElevation() bool {
+	return p.elevation != nil
+}
+
+// Elevation returns the elevation, or 0 if the position has none.
+func (p Position) Elevation() float64 {
+	if p.elevation == nil {
+		return 0
+	}
+	return *p.elevation
+}
+
+// Extra returns a copy of the ordinates that follow the elevation, or nil if there are none.
+func (p Position) Extra() []float64 {
+	if p.extra == nil {
+		return nil
+	}
+	return append([]float64{}, *p.extra...)
+}
+
+// WithLat returns a copy of p with the supplied latitude.
+func (p Position) WithLat(lat float64) Position {
+	p.lat = lat
+	return p
+}
+
+// WithLng returns a copy of p with the supplied longitude.
+func (p Position) WithLng(lng float64) Position {
+	p.lng = lng
+	return p
+}
+
+// WithElevation returns a copy of p with the supplied elevation.
+func (p Position) WithElevation(elevation float64) Position {
+	p.elevation = &elevation
+	return p
+}
+
+// WithoutElevation returns a copy of p without elevation, or extra ordinates as they can't be represented without it.
+func (p Position) WithoutElevation() Position {
+	p.elevation = nil
+	p.extra = nil
+	return p
+}
+
+// WithExtra returns a copy of p with the supplied ordinates following the elevation, replacing any existing ones.
+// The position must have an elevation for it to be valid.
+func (p Position) WithExtra(ordinates ...float64) Position {
+	p.extra = nil
+	if len(ordinates) != 0 {
+		extra := append([]float64{}, ordinates...)
+		p.extra = &extra
+	}
+	return p
+}
+
+// Equal reports whether the positions have the same coordinates, elevation and extra ordinates.
+func (p Position) Equal(other Position) bool {
+	if p.lat != other.lat || p.lng != other.lng || (p.elevation == nil) != (other.elevation == nil) {
+		return false
+	} else if p.elevation != nil && *p.elevation != *other.elevation {
+		return false
+	}
+
+	extra, otherExtra := p.Extra(), other.Extra()
+	if len(extra) != len(otherExtra) {
+		return false
+	}
+	for i := range extra {
+		if extra[i] != otherExtra[i] {
+			return false
+		}
+	}
+	return true
+}
+
 func (p Position) String() string {
 	if p.elevation != nil {
-		return fmt.Sprintf("[%G, %G, %G]", p.pos.Lng.Degrees(), p.pos.Lat.Degrees(), *p.elevation)
+		s := fmt.Sprintf("[%G, %G, %G", p.lng, p.lat, *p.elevation)
+		for _, v := range p.Extra() {
+			s += fmt.Sprintf(", %G", v)
+		}
+		return s + "]"
 	}
-	return fmt.Sprintf("[%G, %G]", p.pos.Lng.Degrees(), p.pos.Lat.Degrees())
+	return fmt.Sprintf("[%G, %G]", p.lng, p.lat)
 }
 
 // Validate the position.
 func (p Position) Validate() error {
-	if !p.pos.IsValid() {
+	if !p.LatLng().IsValid() {
 		return fmt.Errorf("invalid latlng")
+	} else if p.elevation == nil && p.extra != nil {
+		return errExtraWithoutElevation
 	}
 	return nil
 }
 
 // MarshalJSON returns the JSON encoding of the Position.
-// The JSON encoding is an array of numbers with the longitude followed by the latitude, and optional elevation.
+// The JSON encoding is an array of numbers with the longitude followed by the latitude, and optional elevation and extra ordinates.
 func (p Position) MarshalJSON() ([]byte, error) {
 	if p.elevation != nil {
-		return json.Marshal(&position{
-			p.pos.Lng.Degrees(),
-			p.pos.Lat.Degrees(),
+		return json.Marshal(append(position{
+			p.lng,
+			p.lat,
 			*p.elevation,
-		})
+		}, p.Extra()...))
+	} else if p.extra != nil {
+		return nil, errExtraWithoutElevation
 	}
 
 	return json.Marshal(&position{
-		p.pos.Lng.Degrees(),
-		p.pos.Lat.Degrees(),
+		p.lng,
+		p.lat,
 	})
 }
 
@@ -67,16 +175,73 @@ func (p *Position) UnmarshalJSON(data []byte) error {
 		return err
 	}
 
-	switch len(pos) {
-	case 3:
-		p.elevation = &pos[2]
-		fallthrough
-	case 2:
-		p.pos = s2.LatLngFromDegrees(pos[1], pos[0])
-	default:
+	if len(pos) < 2 {
 		return fmt.Errorf("invalid position")
 	}
+
+	*p = MakePosition(pos[1], pos[0])
+	if len(pos) >= 3 {
+		p.elevation = &pos[2]
+	}
+	if len(pos) > 3 {
+		extra := []float64(pos[3:])
+		p.extra = &extra
+	}
 	return nil
 }
 
+// dimension reports whether the positions have elevation, which must be consistent across all positions.
+func dimension(positions [][]Position) (hasZ bool, empty bool, err error) {
+	empty = true
+	for _, list := range positions {
+		for _, pos := range list {
+			if empty {
+				hasZ = pos.elevation != nil
+				empty = false
+			} else if hasZ != (pos.elevation != nil) {
+				return false, false, fmt.Errorf("positions must be in the same dimension")
+			}
+		}
+	}
+	return hasZ, empty, nil
+}
+
+// eachPosition calls fn for every position in the geometry, including those of nested geometries.
+func eachPosition(geo Geometry, fn func(Position)) {
+	eachList := func(lists ...[]Position) {
+		for _, list := range lists {
+			for _, pos := range list {
+				fn(pos)
+			}
+		}
+	}
+
+	if isNilGeometry(geo) {
+		return
+	}
+
+	switch g := geo.(type) {
+	case *Point:
+		fn(g.pos)
+	case *MultiPoint:
+		eachList(g.positions)
+	case *LineString:
+		eachList(g.positions)
+	case *MultiLineString:
+		eachList(g.lines...)
+	case *Polygon:
+		eachList(g.rings...)
+	case *MultiPolygon:
+		for _, polygon := range g.polygons {
+			eachList(polygon...)
+		}
+	case *GeometryCollection:
+		for _, geo := range g.geometries {
+			eachPosition(geo, fn)
+		}
+	}
+}
+
 type position []float64
+
+var errExtraWithoutElevation = fmt.Errorf("position can't have extra ordinates without elevation")
@@ -33,3 +33,87 @@ func TestPositionWithElevation(t *testing.T) {
 	require.NoError(t, err)
 	require.Equal(t, &pos, &unmarshalled)
 }
+
+func TestPositionAccessors(t *testing.T) {
+	pos := geojson.MakePosition(45.4642035, 9.189982)
+	require.Equal(t, 45.4642035, pos.Lat())
+	require.Equal(t, 9.189982, pos.Lng())
+	require.False(t, pos.HasElevation())
+	require.Zero(t, pos.Elevation())
+	require.Nil(t, pos.Extra())
+
+	moved := pos.WithLat(41.9).WithLng(12.5).WithElevation(21).WithExtra(3)
+	require.Equal(t, 41.9, moved.Lat())
+	require.Equal(t, 12.5, moved.Lng())
+	require.True(t, moved.HasElevation())
+	require.Equal(t, 21.0, moved.Elevation())
+	require.Equal(t, []float64{3}, moved.Extra())
+	require.Equal(t, geojson.MakePosition(45.4642035, 9.189982), pos)
+
+	flat := moved.WithoutElevation()
+	require.False(t, flat.HasElevation())
+	require.Nil(t, flat.Extra())
+	require.True(t, moved.HasElevation())
+}
+
+func TestPositionExtra(t *testing.T) {
+	var pos geojson.Position
+	err := json.Unmarshal([]byte("[9.189982, 45.4642035, 125, 7.5, 1]"), &pos)
+	require.NoError(t, err)
+	require.Equal(t, 125.0, pos.Elevation())
+	require.Equal(t, []float64{7.5, 1}, pos.Extra())
+
+	data, err := json.Marshal(pos)
+	require.NoError(t, err)
+	require.JSONEq(t, "[9.189982, 45.4642035, 125, 7.5, 1]", string(data))
+
+	extra := pos.Extra()
+	extra[0] = 0
+	require.Equal(t, []float64{7.5, 1}, pos.Extra())
+
+	invalid := geojson.MakePosition(1, 2).WithExtra(3)
+	require.Error(t, invalid.Validate())
+	_, err = json.Marshal(invalid)
+	require.Error(t, err)
+}
+
+func TestPositionEqual(t *testing.T) {
+	pos := geojson.MakePositionWithElevation(45.4642035, 9.189982, 125)
+	require.True(t, pos.Equal(geojson.MakePositionWithElevation(45.4642035, 9.189982, 125)))
+	require.False(t, pos.Equal(geojson.MakePosition(45.4642035, 9.189982)))
+	require.False(t, pos.Equal(pos.WithElevation(126)))
+	require.False(t, pos.Equal(pos.WithExtra(1)))
+	require.True(t, pos.WithExtra(1).Equal(pos.WithExtra(1)))
+
+	// Positions, and the types that contain them, remain comparable.
+	flat := geojson.MakePosition(45.4642035, 9.189982)
+	seen := map[geojson.Position]bool{flat: true}
+	require.True(t, seen[geojson.MakePosition(45.4642035, 9.189982)])
+	require.True(t, *geojson.NewPoint(45.4642035, 9.189982) == *geojson.NewPointFromPosition(flat))
+	require.True(t, geojson.BoundingBox{BottomLeft: flat, TopRight: flat} == geojson.BoundingBox{BottomLeft: flat, TopRight: flat})
+}
+
+func TestPositionExact(t *testing.T) {
+	for _, data := range []string{
+		"[12.3,45.6]",
+		"[-0.1,51.5,12.7]",
+		"[179.99999999999997,-89.00000000000001]",
+		"[1e-7,0.30000000000000004,1,2]",
+	} {
+		t.Run(data, func(t *testing.T) {
+			var pos geojson.Position
+			err := json.Unmarshal([]byte(data), &pos)
+			require.NoError(t, err)
+
+			marshalled, err := json.Marshal(pos)
+			require.NoError(t, err)
+			require.Equal(t, data, string(marshalled))
+		})
+	}
+
+	pos := geojson.MakePosition(12.3, 45.6)
+	require.Equal(t, 12.3, pos.Lat())
+	require.Equal(t, 45.6, pos.Lng())
+	require.InDelta(t, 12.3, pos.LatLng().Lat.Degrees(), 1e-12)
+	require.InDelta(t, 45.6, pos.LatLng().Lng.Degrees(), 1e-12)
+}
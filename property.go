@@ -90,3 +90,13 @@ func (l *PropertyList) GetValue(name string, dest interface{}) error {
 }
 
 type properties map[string]interface{}
+
+// stringProperty returns the value of the named property if it is a string.
+func stringProperty(props PropertyList, name string) string {
+	if prop, ok := props.Get(name); ok {
+		if s, ok := prop.Value.(string); ok {
+			return s
+		}
+	}
+	return ""
+}
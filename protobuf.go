@@ -0,0 +1,199 @@
+package geojson
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// Protocol Buffers wire types.
+const (
+	protoVarint  = 0
+	protoFixed64 = 1
+	protoBytes   = 2
+	protoFixed32 = 5
+)
+
+func appendProtoTag(b []byte, field int, wire int) []byte {
+	return binary.AppendUvarint(b, uint64(field)<<3|uint64(wire))
+}
+
+func appendProtoVarint(b []byte, field int, v uint64) []byte {
+	b = appendProtoTag(b, field, protoVarint)
+	return binary.AppendUvarint(b, v)
+}
+
+func appendProtoSint(b []byte, field int, v int64) []byte {
+	return appendProtoVarint(b, field, zigzag(v))
+}
+
+func appendProtoDouble(b []byte, field int, v float64) []byte {
+	b = appendProtoTag(b, field, protoFixed64)
+	return binary.LittleEndian.AppendUint64(b, math.Float64bits(v))
+}
+
+func appendProtoFloat(b []byte, field int, v float32) []byte {
+	b = appendProtoTag(b, field, protoFixed32)
+	return binary.LittleEndian.AppendUint32(b, math.Float32bits(v))
+}
+
+func appendProtoBytes(b []byte, field int, data []byte) []byte {
+	b = appendProtoTag(b, field, protoBytes)
+	b = binary.AppendUvarint(b, uint64(len(data)))
+	return append(b, data...)
+}
+
+func appendProtoString(b []byte, field int, s string) []byte {
+	b = appendProtoTag(b, field, protoBytes)
+	b = binary.AppendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+func appendProtoPackedVarints(b []byte, field int, values []uint64) []byte {
+	var packed []byte
+	for _, v := range values {
+		packed = binary.AppendUvarint(packed, v)
+	}
+	return appendProtoBytes(b, field, packed)
+}
+
+func appendProtoPackedSints(b []byte, field int, values []int64) []byte {
+	var packed []byte
+	for _, v := range values {
+		packed = binary.AppendUvarint(packed, zigzag(v))
+	}
+	return appendProtoBytes(b, field, packed)
+}
+
+// protoReader reads fields from a Protocol Buffers message.
+type protoReader struct {
+	data []byte
+	pos  int
+}
+
+func (r *protoReader) more() bool {
+	return r.pos < len(r.data)
+}
+
+func (r *protoReader) next() (field int, wire int, err error) {
+	tag, err := r.varint()
+	if err != nil {
+		return 0, 0, err
+	}
+	return int(tag >> 3), int(tag & 0x07), nil
+}
+
+func (r *protoReader) varint() (uint64, error) {
+	v, n := binary.Uvarint(r.data[r.pos:])
+	if n <= 0 {
+		return 0, fmt.Errorf("invalid varint at offset %d", r.pos)
+	}
+	r.pos += n
+	return v, nil
+}
+
+func (r *protoReader) sint() (int64, error) {
+	v, err := r.varint()
+	return unzigzag(v), err
+}
+
+func (r *protoReader) fixed64() (uint64, error) {
+	if len(r.data)-r.pos < 8 {
+		return 0, fmt.Errorf("unexpected end of message")
+	}
+	v := binary.LittleEndian.Uint64(r.data[r.pos:])
+	r.pos += 8
+	return v, nil
+}
+
+func (r *protoReader) fixed32() (uint32, error) {
+	if len(r.data)-r.pos < 4 {
+		return 0, fmt.Errorf("unexpected end of message")
+	}
+	v := binary.LittleEndian.Uint32(r.data[r.pos:])
+	r.pos += 4
+	return v, nil
+}
+
+func (r *protoReader) double() (float64, error) {
+	v, err := r.fixed64()
+	return math.Float64frombits(v), err
+}
+
+func (r *protoReader) float() (float32, error) {
+	v, err := r.fixed32()
+	return math.Float32frombits(v), err
+}
+
+func (r *protoReader) bytes() ([]byte, error) {
+	n, err := r.varint()
+	if err != nil {
+		return nil, err
+	} else if n > uint64(len(r.data)-r.pos) {
+		return nil, fmt.Errorf("unexpected end of message")
+	}
+
+	data := r.data[r.pos : r.pos+int(n)]
+	r.pos += int(n)
+	return data, nil
+}
+
+func (r *protoReader) message() (*protoReader, error) {
+	data, err := r.bytes()
+	if err != nil {
+		return nil, err
+	}
+	return &protoReader{data: data}, nil
+}
+
+// varints reads a repeated varint field, which may or may not be packed.
+func (r *protoReader) varints(wire int, values []uint64) ([]uint64, error) {
+	if wire == protoVarint {
+		v, err := r.varint()
+		return append(values, v), err
+	}
+
+	packed, err := r.message()
+	if err != nil {
+		return nil, err
+	}
+
+	for packed.more() {
+		v, err := packed.varint()
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+// sints reads a repeated zigzag-encoded varint field, which may or may not be packed.
+func (r *protoReader) sints(wire int, values []int64) ([]int64, error) {
+	raw, err := r.varints(wire, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, v := range raw {
+		values = append(values, unzigzag(v))
+	}
+	return values, nil
+}
+
+func (r *protoReader) skip(wire int) error {
+	var err error
+	switch wire {
+	case protoVarint:
+		_, err = r.varint()
+	case protoFixed64:
+		_, err = r.fixed64()
+	case protoBytes:
+		_, err = r.bytes()
+	case protoFixed32:
+		_, err = r.fixed32()
+	default:
+		err = fmt.Errorf("unsupported wire type %d", wire)
+	}
+	return err
+}
@@ -8,9 +8,9 @@ import (
 
 // LineStringToS2 returns an S2 Geometry polyline.
 func LineStringToS2(linestring LineString) (*s2.Polyline, error) {
-	latlngs := make([]s2.LatLng, len(linestring))
-	for i, pos := range linestring {
-		latlngs[i] = pos.pos
+	latlngs := make([]s2.LatLng, len(linestring.positions))
+	for i, pos := range linestring.positions {
+		latlngs[i] = pos.LatLng()
 	}
 	polyline := s2.PolylineFromLatLngs(latlngs)
 	return polyline, polyline.Validate()
@@ -22,8 +22,8 @@ func PolygonToS2(polygon Polygon) (*s2.Polygon, error) {
 		return nil, err
 	}
 
-	loops := make([]*s2.Loop, len(polygon))
-	for i, loop := range polygon {
+	loops := make([]*s2.Loop, len(polygon.rings))
+	for i, loop := range polygon.rings {
 		loops[i] = LoopToS2(loop)
 		if err := loops[i].Validate(); err != nil {
 			return nil, fmt.Errorf("invalid loop '%d': %w", i, err)
@@ -38,12 +38,12 @@ func LoopToS2(loop []Position) *s2.Loop {
 	if n := len(loop); n == 0 {
 		return s2.EmptyLoop()
 	} else if n == 1 {
-		return s2.LoopFromPoints([]s2.Point{s2.PointFromLatLng(loop[0].pos)})
+		return s2.LoopFromPoints([]s2.Point{s2.PointFromLatLng(loop[0].LatLng())})
 	}
 
 	points := make([]s2.Point, len(loop)-1)
 	for i := 0; i < len(loop)-1; i++ {
-		points[i] = s2.PointFromLatLng(loop[i].pos)
+		points[i] = s2.PointFromLatLng(loop[i].LatLng())
 	}
 	return s2.LoopFromPoints(points)
 }
@@ -0,0 +1,115 @@
+package geojson
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// SeqMediaType is the media type of GeoJSON Text Sequences, as standardized by RFC 8142.
+const SeqMediaType = "application/geo+json-seq"
+
+const recordSeparator = 0x1E
+
+// ErrTruncatedRecord is reported when a record in a sequence was not terminated correctly.
+var ErrTruncatedRecord = errors.New("record is truncated")
+
+// RecordError describes a problem with a single record of a sequence.
+// Reading may continue after a RecordError has been returned.
+type RecordError struct {
+	Record int
+	Err    error
+}
+
+func (e *RecordError) Error() string {
+	return fmt.Sprintf("record %d: %v", e.Record, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *RecordError) Unwrap() error {
+	return e.Err
+}
+
+// SeqReader reads Features from a GeoJSON Text Sequence.
+type SeqReader struct {
+	r       *bufio.Reader
+	started bool
+	record  int
+}
+
+// NewSeqReader returns a new SeqReader that reads from r.
+func NewSeqReader(r io.Reader) *SeqReader {
+	return &SeqReader{
+		r: bufio.NewReader(r),
+	}
+}
+
+// Read returns the next Feature in the sequence.
+// Malformed or truncated records are reported as a *RecordError, after which reading may continue.
+// It returns io.EOF once the end of the sequence has been reached.
+func (r *SeqReader) Read() (Feature[Geometry], error) {
+	for {
+		data, err := r.r.ReadBytes(recordSeparator)
+		if err != nil && err != io.EOF {
+			return Feature[Geometry]{}, err
+		} else if len(data) == 0 && err == io.EOF {
+			return Feature[Geometry]{}, io.EOF
+		}
+		data = bytes.TrimSuffix(data, []byte{recordSeparator})
+
+		if !r.started {
+			// Anything before the first separator can't be a valid record.
+			r.started = true
+			if len(bytes.TrimSpace(data)) != 0 {
+				r.record++
+				return Feature[Geometry]{}, &RecordError{Record: r.record, Err: ErrTruncatedRecord}
+			}
+			continue
+		} else if len(data) == 0 {
+			// Consecutive separators don't denote empty records.
+			continue
+		}
+
+		r.record++
+		if data[len(data)-1] != '\n' {
+			return Feature[Geometry]{}, &RecordError{Record: r.record, Err: ErrTruncatedRecord}
+		}
+
+		var feature Feature[Geometry]
+		if err := json.Unmarshal(data, &feature); err != nil {
+			return Feature[Geometry]{}, &RecordError{Record: r.record, Err: err}
+		}
+		return feature, nil
+	}
+}
+
+// SeqWriter writes Features as a GeoJSON Text Sequence.
+type SeqWriter[G Geometry] struct {
+	w io.Writer
+}
+
+// NewSeqWriter returns a new SeqWriter that writes to w.
+func NewSeqWriter[G Geometry](w io.Writer) *SeqWriter[G] {
+	return &SeqWriter[G]{
+		w: w,
+	}
+}
+
+// Write the feature as a single record.
+func (w *SeqWriter[G]) Write(feature Feature[G]) error {
+	data, err := json.Marshal(feature)
+	if err != nil {
+		return err
+	}
+
+	record := make([]byte, 0, len(data)+2)
+	record = append(record, recordSeparator)
+	record = append(record, data...)
+	record = append(record, '\n')
+
+	_, err = w.w.Write(record)
+	return err
+}
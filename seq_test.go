@@ -0,0 +1,71 @@
+package geojson_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	geojson "github.com/everystreet/go-geojson/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSeq(t *testing.T) {
+	var buf bytes.Buffer
+	w := geojson.NewSeqWriter[*geojson.Point](&buf)
+
+	err := w.Write(geojson.NewFeature(geojson.NewPoint(45.4642035, 9.189982)))
+	require.NoError(t, err)
+	err = w.Write(geojson.NewFeature(geojson.NewPoint(13.0473748, 79.9288064)))
+	require.NoError(t, err)
+
+	require.Equal(t,
+		"\x1e"+`{"type":"Feature","geometry":{"type":"Point","coordinates":[9.189982,45.4642035]}}`+"\n"+
+			"\x1e"+`{"type":"Feature","geometry":{"type":"Point","coordinates":[79.9288064,13.0473748]}}`+"\n",
+		buf.String())
+
+	r := geojson.NewSeqReader(&buf)
+
+	feature, err := r.Read()
+	require.NoError(t, err)
+	require.Equal(t, geojson.NewPoint(45.4642035, 9.189982), feature.Geometry())
+
+	feature, err = r.Read()
+	require.NoError(t, err)
+	require.Equal(t, geojson.NewPoint(13.0473748, 79.9288064), feature.Geometry())
+
+	_, err = r.Read()
+	require.Equal(t, io.EOF, err)
+}
+
+func TestSeqReaderRecovery(t *testing.T) {
+	r := geojson.NewSeqReader(strings.NewReader(
+		"\x1e" + `{"type":"Feature","geometry":{"type":"Point","coordinates":[1, 2]}}` + "\n" +
+			"\x1e" + `{"type":"Feature","geometry":{"type":"Po` +
+			"\x1e\x1e" + `{"type":"Feature","geometry":{"type":"Triangle"}}` + "\n" +
+			"\x1e" + `{"type":"Feature","geometry":{"type":"Point","coordinates":[3, 4]}}` + "\n",
+	))
+
+	feature, err := r.Read()
+	require.NoError(t, err)
+	require.Equal(t, geojson.NewPoint(2, 1), feature.Geometry())
+
+	_, err = r.Read()
+	var recordErr *geojson.RecordError
+	require.True(t, errors.As(err, &recordErr))
+	require.Equal(t, 2, recordErr.Record)
+	require.True(t, errors.Is(err, geojson.ErrTruncatedRecord))
+
+	_, err = r.Read()
+	require.True(t, errors.As(err, &recordErr))
+	require.Equal(t, 3, recordErr.Record)
+	require.Contains(t, err.Error(), "unknown geometry type")
+
+	feature, err = r.Read()
+	require.NoError(t, err)
+	require.Equal(t, geojson.NewPoint(4, 3), feature.Geometry())
+
+	_, err = r.Read()
+	require.Equal(t, io.EOF, err)
+}
@@ -0,0 +1,476 @@
+package geojson
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"math"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Shapefile shape types.
+const (
+	shpNull        = 0
+	shpPoint       = 1
+	shpPolyLine    = 3
+	shpPolygon     = 5
+	shpMultiPoint  = 8
+	shpPointZ      = 11
+	shpPolyLineZ   = 13
+	shpPolygonZ    = 15
+	shpMultiPointZ = 18
+	shpPointM      = 21
+	shpPolyLineM   = 23
+	shpPolygonM    = 25
+	shpMultiPointM = 28
+)
+
+const (
+	shpFileCode   = 9994
+	shpVersion    = 1000
+	shpHeaderSize = 100
+
+	dbfHeaderSize      = 32
+	dbfFieldSize       = 32
+	dbfFieldTerminator = 0x0D
+	dbfDeleted         = '*'
+)
+
+// ShapefileReader reads Features from an ESRI Shapefile, with properties from its dBASE (.dbf) table.
+// Records are read sequentially, so the .shx index isn't required.
+type ShapefileReader struct {
+	shp       io.Reader
+	dbf       *dbfReader
+	prj       string
+	shapeType int32
+	box       *BoundingBox
+	remaining int64
+}
+
+// ReadShapefile reads all of the features in the shapefile at path, which is the path of the .shp file.
+// The .dbf and .prj files with the same base name are also read if they exist, and the content of the .prj file is returned
+// so that callers can determine whether the coordinates need to be reprojected.
+func ReadShapefile(path string) (FeatureCollection, string, error) {
+	shp, err := os.Open(path)
+	if err != nil {
+		return FeatureCollection{}, "", err
+	}
+	defer shp.Close()
+
+	var optional [2]io.Reader
+	for i, ext := range []string{".dbf", ".prj"} {
+		if f, err := openShapefileSibling(path, ext); err != nil {
+			return FeatureCollection{}, "", err
+		} else if f != nil {
+			defer f.Close()
+			optional[i] = f
+		}
+	}
+
+	r, err := NewShapefileReader(shp, optional[0], optional[1])
+	if err != nil {
+		return FeatureCollection{}, "", err
+	}
+
+	var features []Feature[Geometry]
+	for {
+		f, err := r.Read()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return FeatureCollection{}, "", err
+		}
+		features = append(features, f)
+	}
+	return NewFeatureCollection(features...), r.Projection(), nil
+}
+
+// openShapefileSibling opens the file with the same base name as the .shp file and the specified extension,
+// in the same case as the .shp extension. It returns nil if the file doesn't exist.
+func openShapefileSibling(path, ext string) (*os.File, error) {
+	shpExt := filepath.Ext(path)
+	if shpExt != "" && shpExt == strings.ToUpper(shpExt) {
+		ext = strings.ToUpper(ext)
+	}
+
+	f, err := os.Open(strings.TrimSuffix(path, shpExt) + ext)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	return f, err
+}
+
+// NewShapefileReader returns a new ShapefileReader that reads shapes from shp, after reading the file headers.
+// If dbf is not nil, each feature has the properties of the corresponding record, and records marked as deleted are skipped.
+// If prj is not nil, its content is returned by Projection.
+func NewShapefileReader(shp, dbf, prj io.Reader) (*ShapefileReader, error) {
+	header := make([]byte, shpHeaderSize)
+	if _, err := io.ReadFull(shp, header); err != nil {
+		return nil, err
+	} else if code := binary.BigEndian.Uint32(header[0:]); code != shpFileCode {
+		return nil, fmt.Errorf("not a shapefile")
+	} else if version := binary.LittleEndian.Uint32(header[28:]); version != shpVersion {
+		return nil, fmt.Errorf("unsupported shapefile version %d", version)
+	}
+
+	r := ShapefileReader{
+		shp:       shp,
+		shapeType: int32(binary.LittleEndian.Uint32(header[32:])),
+		remaining: int64(binary.BigEndian.Uint32(header[24:]))*2 - shpHeaderSize,
+	}
+
+	if r.shapeType != shpNull {
+		r.box = &BoundingBox{
+			BottomLeft: MakePosition(shpFloat64(header[44:]), shpFloat64(header[36:])),
+			TopRight:   MakePosition(shpFloat64(header[60:]), shpFloat64(header[52:])),
+		}
+	}
+
+	if dbf != nil {
+		var err error
+		if r.dbf, err = newDBFReader(dbf); err != nil {
+			return nil, err
+		}
+	}
+
+	if prj != nil {
+		data, err := io.ReadAll(prj)
+		if err != nil {
+			return nil, err
+		}
+		r.prj = strings.TrimSpace(string(data))
+	}
+	return &r, nil
+}
+
+// Projection returns the content of the .prj file, which describes the coordinate reference system in Well-Known Text,
+// or an empty string if unknown.
+func (r *ShapefileReader) Projection() string {
+	return r.prj
+}
+
+// BoundingBox returns the extent of the shapes, or nil if the file has no shapes.
+func (r *ShapefileReader) BoundingBox() *BoundingBox {
+	return r.box
+}
+
+// Read returns the next feature in the file, or io.EOF if there are none left.
+// Polygon rings are oriented with clockwise exterior rings, and holes are assigned to the exterior ring that contains them.
+// Z values are stored as elevation, and M values are discarded. Null shapes become features without a geometry.
+func (r *ShapefileReader) Read() (Feature[Geometry], error) {
+	for {
+		if r.remaining <= 0 {
+			return Feature[Geometry]{}, io.EOF
+		}
+
+		var header [8]byte
+		if _, err := io.ReadFull(r.shp, header[:]); err == io.EOF {
+			return Feature[Geometry]{}, io.EOF
+		} else if err != nil {
+			return Feature[Geometry]{}, err
+		}
+
+		number := binary.BigEndian.Uint32(header[0:])
+		size := int64(binary.BigEndian.Uint32(header[4:])) * 2
+		if size > r.remaining {
+			return Feature[Geometry]{}, fmt.Errorf("record %d: invalid length %d", number, size)
+		}
+		r.remaining -= int64(len(header)) + size
+
+		data := make([]byte, size)
+		if _, err := io.ReadFull(r.shp, data); err != nil {
+			return Feature[Geometry]{}, err
+		}
+
+		var props []Property
+		if r.dbf != nil {
+			var deleted bool
+			var err error
+			if props, deleted, err = r.dbf.next(); err != nil {
+				return Feature[Geometry]{}, fmt.Errorf("record %d: %w", number, err)
+			} else if deleted {
+				continue
+			}
+		}
+
+		geo, err := decodeShape(data)
+		if err != nil {
+			return Feature[Geometry]{}, fmt.Errorf("record %d: %w", number, err)
+		}
+		return NewFeature(geo, props...), nil
+	}
+}
+
+func decodeShape(data []byte) (Geometry, error) {
+	if len(data) < 4 {
+		return nil, fmt.Errorf("record is too short")
+	}
+
+	shapeType := int32(binary.LittleEndian.Uint32(data))
+	data = data[4:]
+
+	var hasZ bool
+	switch shapeType {
+	case shpPointZ, shpPolyLineZ, shpPolygonZ, shpMultiPointZ:
+		hasZ = true
+	}
+
+	switch shapeType {
+	case shpNull:
+		return nil, nil
+	case shpPoint, shpPointZ, shpPointM:
+		size := 16
+		if hasZ {
+			size = 24
+		}
+		if len(data) < size {
+			return nil, fmt.Errorf("record is too short")
+		}
+
+		pos := MakePosition(shpFloat64(data[8:]), shpFloat64(data[0:]))
+		if hasZ {
+			pos = MakePositionWithElevation(shpFloat64(data[8:]), shpFloat64(data[0:]), shpFloat64(data[16:]))
+		}
+		return NewPointFromPosition(pos), nil
+	case shpMultiPoint, shpMultiPointZ, shpMultiPointM:
+		if len(data) < 36 {
+			return nil, fmt.Errorf("record is too short")
+		}
+
+		positions, err := decodeShapePositions(data[36:], int(binary.LittleEndian.Uint32(data[32:])), hasZ)
+		if err != nil {
+			return nil, err
+		}
+		return NewMultiPoint(positions...), nil
+	case shpPolyLine, shpPolyLineZ, shpPolyLineM, shpPolygon, shpPolygonZ, shpPolygonM:
+		parts, err := decodeShapeParts(data, hasZ)
+		if err != nil {
+			return nil, err
+		}
+
+		switch shapeType {
+		case shpPolygon, shpPolygonZ, shpPolygonM:
+			polygons := shapePolygons(parts)
+			if len(polygons) == 1 {
+				return NewPolygon(polygons[0]...), nil
+			}
+			return NewMultiPolygon(polygons...), nil
+		default:
+			if len(parts) == 1 {
+				return &LineString{positions: parts[0]}, nil
+			}
+			return NewMultiLineString(parts...), nil
+		}
+	default:
+		return nil, fmt.Errorf("unsupported shape type %d", shapeType)
+	}
+}
+
+// decodeShapeParts returns the positions of each part of a PolyLine or Polygon.
+func decodeShapeParts(data []byte, hasZ bool) ([][]Position, error) {
+	if len(data) < 40 {
+		return nil, fmt.Errorf("record is too short")
+	}
+
+	numParts := int(binary.LittleEndian.Uint32(data[32:]))
+	numPoints := int(binary.LittleEndian.Uint32(data[36:]))
+	data = data[40:]
+	if numParts < 1 || numParts > len(data)/4 {
+		return nil, fmt.Errorf("invalid number of parts %d", numParts)
+	}
+
+	positions, err := decodeShapePositions(data[numParts*4:], numPoints, hasZ)
+	if err != nil {
+		return nil, err
+	}
+
+	parts := make([][]Position, numParts)
+	for i := range parts {
+		start, end := int(binary.LittleEndian.Uint32(data[i*4:])), numPoints
+		if i+1 < numParts {
+			end = int(binary.LittleEndian.Uint32(data[(i+1)*4:]))
+		}
+
+		if start < 0 || start > end || end > numPoints {
+			return nil, fmt.Errorf("invalid part %d", i)
+		}
+		parts[i] = positions[start:end:end]
+	}
+	return parts, nil
+}
+
+// decodeShapePositions returns the positions from an array of points, which is followed by the Z range and values if hasZ is true.
+func decodeShapePositions(data []byte, n int, hasZ bool) ([]Position, error) {
+	size := 16
+	if hasZ {
+		size = 24
+	}
+
+	if n < 0 || n > len(data)/size {
+		return nil, fmt.Errorf("invalid number of points %d", n)
+	} else if hasZ && len(data) < n*size+16 {
+		return nil, fmt.Errorf("record is too short")
+	}
+
+	positions := make([]Position, n)
+	for i := range positions {
+		x, y := shpFloat64(data[i*16:]), shpFloat64(data[i*16+8:])
+		if hasZ {
+			positions[i] = MakePositionWithElevation(y, x, shpFloat64(data[n*16+16+i*8:]))
+		} else {
+			positions[i] = MakePosition(y, x)
+		}
+	}
+	return positions, nil
+}
+
+// shapePolygons groups the rings of a Polygon shape into polygons.
+// Clockwise rings are exterior rings, and counter-clockwise rings are holes.
+func shapePolygons(rings [][]Position) [][][]Position {
+	var exteriors, holes [][]Position
+	for _, ring := range rings {
+		if ringArea(ring) > 0 {
+			holes = append(holes, ring)
+		} else {
+			exteriors = append(exteriors, ring)
+		}
+	}
+	return assemblePolygons(exteriors, holes)
+}
+
+func shpFloat64(b []byte) float64 {
+	return math.Float64frombits(binary.LittleEndian.Uint64(b))
+}
+
+type dbfReader struct {
+	r         io.Reader
+	fields    []dbfField
+	count     uint32
+	read      uint32
+	recordLen int
+}
+
+type dbfField struct {
+	name     string
+	kind     byte
+	size     int
+	decimals int
+}
+
+func newDBFReader(r io.Reader) (*dbfReader, error) {
+	header := make([]byte, dbfHeaderSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, err
+	}
+
+	headerLen := int(binary.LittleEndian.Uint16(header[8:]))
+	if headerLen < dbfHeaderSize+1 {
+		return nil, fmt.Errorf("invalid dbf header length %d", headerLen)
+	}
+
+	descriptors := make([]byte, headerLen-dbfHeaderSize)
+	if _, err := io.ReadFull(r, descriptors); err != nil {
+		return nil, err
+	}
+
+	d := dbfReader{
+		r:         r,
+		count:     binary.LittleEndian.Uint32(header[4:]),
+		recordLen: int(binary.LittleEndian.Uint16(header[10:])),
+	}
+
+	size := 1
+	for i := 0; i+dbfFieldSize <= len(descriptors) && descriptors[i] != dbfFieldTerminator; i += dbfFieldSize {
+		desc := descriptors[i : i+dbfFieldSize]
+		name := desc[:11]
+		if end := strings.IndexByte(string(name), 0); end != -1 {
+			name = name[:end]
+		}
+
+		field := dbfField{
+			name:     string(name),
+			kind:     desc[11],
+			size:     int(desc[16]),
+			decimals: int(desc[17]),
+		}
+		d.fields = append(d.fields, field)
+		size += field.size
+	}
+
+	if size > d.recordLen {
+		return nil, fmt.Errorf("dbf fields are longer than the record length %d", d.recordLen)
+	}
+	return &d, nil
+}
+
+// next returns the properties of the next record, and whether it is marked as deleted.
+func (d *dbfReader) next() ([]Property, bool, error) {
+	if d.read == d.count {
+		return nil, false, fmt.Errorf("dbf has fewer records than the shapefile")
+	}
+	d.read++
+
+	record := make([]byte, d.recordLen)
+	if _, err := io.ReadFull(d.r, record); err != nil {
+		return nil, false, err
+	} else if record[0] == dbfDeleted {
+		return nil, true, nil
+	}
+
+	var props []Property
+	offset := 1
+	for _, field := range d.fields {
+		value, err := field.parse(string(record[offset : offset+field.size]))
+		if err != nil {
+			return nil, false, fmt.Errorf("field '%s': %w", field.name, err)
+		} else if value != nil {
+			props = append(props, Property{Name: field.name, Value: value})
+		}
+		offset += field.size
+	}
+	return props, false, nil
+}
+
+// parse returns the value of the field, or nil if it is empty.
+// Numbers without decimal places are int64, other numbers are float64, logicals are bool, dates are time.Time and anything else is a string.
+func (f dbfField) parse(s string) (interface{}, error) {
+	s = strings.TrimSpace(strings.TrimRight(s, "\x00"))
+	if s == "" {
+		return nil, nil
+	}
+
+	switch f.kind {
+	case 'N', 'F':
+		// Numbers that don't fit in the field are filled with asterisks.
+		if strings.HasPrefix(s, "*") {
+			return nil, nil
+		} else if f.decimals == 0 {
+			if v, err := strconv.ParseInt(s, 10, 64); err == nil {
+				return v, nil
+			}
+		}
+		return strconv.ParseFloat(s, 64)
+	case 'L':
+		switch s {
+		case "Y", "y", "T", "t":
+			return true, nil
+		case "N", "n", "F", "f":
+			return false, nil
+		default:
+			return nil, nil
+		}
+	case 'D':
+		if strings.Trim(s, "0") == "" {
+			return nil, nil
+		}
+		return time.Parse("20060102", s)
+	default:
+		return s, nil
+	}
+}
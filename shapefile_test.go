@@ -0,0 +1,339 @@
+package geojson_test
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	geojson "github.com/everystreet/go-geojson/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestShapefile(t *testing.T) {
+	shp := shapefile(5,
+		partsShape(5, []int{0, 5}, nil,
+			// Exterior ring.
+			[2]float64{0, 0}, [2]float64{0, 10}, [2]float64{10, 10}, [2]float64{10, 0}, [2]float64{0, 0},
+			// Hole, stored after another exterior ring.
+			[2]float64{2, 2}, [2]float64{8, 2}, [2]float64{8, 8}, [2]float64{2, 8}, [2]float64{2, 2},
+		),
+		partsShape(5, []int{0, 5}, nil,
+			[2]float64{20, 20}, [2]float64{20, 30}, [2]float64{30, 30}, [2]float64{30, 20}, [2]float64{20, 20},
+			// Counter-clockwise ring that isn't inside the exterior ring.
+			[2]float64{0, 0}, [2]float64{1, 0}, [2]float64{0, 1}, [2]float64{0, 0},
+		),
+		partsShape(5, []int{0}, nil,
+			[2]float64{0, 0}, [2]float64{0, 1}, [2]float64{1, 0}, [2]float64{0, 0},
+		),
+	)
+
+	dbf := dbfTable(
+		[]dbfTestField{{"NAME", 'C', 10, 0}, {"COUNT", 'N', 5, 0}, {"AREA", 'N', 8, 2}, {"OK", 'L', 1, 0}, {"DATE", 'D', 8, 0}},
+		[]string{" ", "a", "3", "1.50", "T", "20210601"},
+		[]string{" ", "", "", "", "?", ""},
+		[]string{"*", "deleted", "", "", "", ""},
+	)
+
+	r, err := geojson.NewShapefileReader(bytes.NewReader(shp), bytes.NewReader(dbf), bytes.NewReader([]byte("GEOGCS[\"GCS_WGS_1984\"]\n")))
+	require.NoError(t, err)
+	require.Equal(t, `GEOGCS["GCS_WGS_1984"]`, r.Projection())
+	require.Equal(t, &geojson.BoundingBox{
+		BottomLeft: geojson.MakePosition(-1, -2),
+		TopRight:   geojson.MakePosition(30, 40),
+	}, r.BoundingBox())
+
+	f, err := r.Read()
+	require.NoError(t, err)
+	require.Equal(t, geojson.NewFeature[geojson.Geometry](
+		geojson.NewPolygon(
+			[]geojson.Position{
+				geojson.MakePosition(0, 0),
+				geojson.MakePosition(10, 0),
+				geojson.MakePosition(10, 10),
+				geojson.MakePosition(0, 10),
+				geojson.MakePosition(0, 0),
+			},
+			[]geojson.Position{
+				geojson.MakePosition(2, 2),
+				geojson.MakePosition(2, 8),
+				geojson.MakePosition(8, 8),
+				geojson.MakePosition(8, 2),
+				geojson.MakePosition(2, 2),
+			},
+		),
+		geojson.Property{Name: "NAME", Value: "a"},
+		geojson.Property{Name: "COUNT", Value: int64(3)},
+		geojson.Property{Name: "AREA", Value: 1.5},
+		geojson.Property{Name: "OK", Value: true},
+		geojson.Property{Name: "DATE", Value: time.Date(2021, 6, 1, 0, 0, 0, 0, time.UTC)},
+	), f)
+	require.NoError(t, f.Geometry().Validate())
+
+	f, err = r.Read()
+	require.NoError(t, err)
+	require.Equal(t, geojson.NewFeature[geojson.Geometry](
+		geojson.NewMultiPolygon(
+			[][]geojson.Position{
+				{
+					geojson.MakePosition(20, 20),
+					geojson.MakePosition(30, 20),
+					geojson.MakePosition(30, 30),
+					geojson.MakePosition(20, 30),
+					geojson.MakePosition(20, 20),
+				},
+			},
+			[][]geojson.Position{
+				{
+					geojson.MakePosition(0, 0),
+					geojson.MakePosition(1, 0),
+					geojson.MakePosition(0, 1),
+					geojson.MakePosition(0, 0),
+				},
+			},
+		),
+	), f)
+	require.NoError(t, f.Geometry().Validate())
+
+	// The third record is deleted.
+	_, err = r.Read()
+	require.Equal(t, io.EOF, err)
+}
+
+func TestShapefileGeometries(t *testing.T) {
+	shp := shapefile(0,
+		pointShape(1, 9.25, 45.5),
+		pointShape(11, 9.25, 45.5, 125, 0),
+		multiPointShape(8, [][]float64{{1, 2}, {3, 4}}, nil),
+		multiPointShape(18, [][]float64{{1, 2}, {3, 4}}, []float64{5, 6}),
+		partsShape(3, []int{0}, nil, [2]float64{1, 2}, [2]float64{3, 4}),
+		partsShape(13, []int{0, 2}, []float64{7, 8, 9}, [2]float64{1, 2}, [2]float64{3, 4}, [2]float64{5, 6}),
+	)
+
+	r, err := geojson.NewShapefileReader(bytes.NewReader(shp), nil, nil)
+	require.NoError(t, err)
+	require.Empty(t, r.Projection())
+
+	for _, expected := range []geojson.Geometry{
+		geojson.NewPoint(45.5, 9.25),
+		geojson.NewPointWithElevation(45.5, 9.25, 125),
+		geojson.NewMultiPoint(
+			geojson.MakePosition(2, 1),
+			geojson.MakePosition(4, 3),
+		),
+		geojson.NewMultiPoint(
+			geojson.MakePositionWithElevation(2, 1, 5),
+			geojson.MakePositionWithElevation(4, 3, 6),
+		),
+		geojson.NewLineString(
+			geojson.MakePosition(2, 1),
+			geojson.MakePosition(4, 3),
+		),
+		geojson.NewMultiLineString(
+			[]geojson.Position{
+				geojson.MakePositionWithElevation(2, 1, 7),
+				geojson.MakePositionWithElevation(4, 3, 8),
+			},
+			[]geojson.Position{
+				geojson.MakePositionWithElevation(6, 5, 9),
+			},
+		),
+	} {
+		f, err := r.Read()
+		require.NoError(t, err)
+		require.Equal(t, expected, f.Geometry())
+	}
+
+	_, err = r.Read()
+	require.Equal(t, io.EOF, err)
+}
+
+func TestReadShapefile(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "data.SHP"), shapefile(1, pointShape(1, 9.25, 45.5)), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "data.DBF"), dbfTable(
+		[]dbfTestField{{"NAME", 'C', 10, 0}},
+		[]string{" ", "Milan"},
+	), 0o600))
+
+	c, prj, err := geojson.ReadShapefile(filepath.Join(dir, "data.SHP"))
+	require.NoError(t, err)
+	require.Empty(t, prj)
+	require.Equal(t, geojson.NewFeatureCollection(
+		geojson.NewFeature[geojson.Geometry](
+			geojson.NewPoint(45.5, 9.25),
+			geojson.Property{Name: "NAME", Value: "Milan"},
+		),
+	), c)
+}
+
+func TestShapefileNullShape(t *testing.T) {
+	r, err := geojson.NewShapefileReader(
+		bytes.NewReader(shapefile(1, pointShape(1, 9.25, 45.5), []byte{0, 0, 0, 0})),
+		bytes.NewReader(dbfTable([]dbfTestField{{"NAME", 'C', 10, 0}}, []string{" ", "Milan"}, []string{" ", "Unknown"})),
+		nil,
+	)
+	require.NoError(t, err)
+
+	f, err := r.Read()
+	require.NoError(t, err)
+	require.True(t, f.HasGeometry())
+
+	f, err = r.Read()
+	require.NoError(t, err)
+	require.False(t, f.HasGeometry())
+	require.Equal(t, geojson.PropertyList{{Name: "NAME", Value: "Unknown"}}, f.Properties())
+
+	_, err = r.Read()
+	require.Equal(t, io.EOF, err)
+}
+
+func TestShapefileInvalid(t *testing.T) {
+	_, err := geojson.NewShapefileReader(bytes.NewReader(make([]byte, 100)), nil, nil)
+	require.Error(t, err)
+
+	for name, shp := range map[string][]byte{
+		"short record":  shapefile(1, pointShape(1, 1, 2)[:12]),
+		"invalid parts": shapefile(3, partsShape(3, []int{0, 5}, nil, [2]float64{1, 2}, [2]float64{3, 4})),
+	} {
+		t.Run(name, func(t *testing.T) {
+			r, err := geojson.NewShapefileReader(bytes.NewReader(shp), nil, nil)
+			require.NoError(t, err)
+
+			_, err = r.Read()
+			require.Error(t, err)
+		})
+	}
+
+	r, err := geojson.NewShapefileReader(
+		bytes.NewReader(shapefile(1, pointShape(1, 1, 2), pointShape(1, 3, 4))),
+		bytes.NewReader(dbfTable([]dbfTestField{{"A", 'C', 1, 0}}, []string{" ", "a"})),
+		nil,
+	)
+	require.NoError(t, err)
+
+	_, err = r.Read()
+	require.NoError(t, err)
+
+	_, err = r.Read()
+	require.Error(t, err)
+}
+
+// shapefile returns a .shp file containing the shapes, with a bounding box from (-1, -2) to (30, 40).
+func shapefile(shapeType int32, shapes ...[]byte) []byte {
+	header := make([]byte, 100)
+	binary.BigEndian.PutUint32(header[0:], 9994)
+	binary.LittleEndian.PutUint32(header[28:], 1000)
+	binary.LittleEndian.PutUint32(header[32:], uint32(shapeType))
+	for i, v := range []float64{-2, -1, 40, 30} {
+		binary.LittleEndian.PutUint64(header[36+i*8:], math.Float64bits(v))
+	}
+
+	data := header
+	for i, shape := range shapes {
+		record := make([]byte, 8)
+		binary.BigEndian.PutUint32(record[0:], uint32(i+1))
+		binary.BigEndian.PutUint32(record[4:], uint32(len(shape)/2))
+		data = append(append(data, record...), shape...)
+	}
+	binary.BigEndian.PutUint32(data[24:], uint32(len(data)/2))
+	return data
+}
+
+func pointShape(shapeType int32, values ...float64) []byte {
+	return appendShapeFloats(appendShapeInts(nil, shapeType), values...)
+}
+
+func multiPointShape(shapeType int32, points [][]float64, z []float64) []byte {
+	b := appendShapeInts(nil, shapeType)
+	b = appendShapeFloats(b, 0, 0, 0, 0)
+	b = appendShapeInts(b, int32(len(points)))
+	for _, p := range points {
+		b = appendShapeFloats(b, p...)
+	}
+	if z != nil {
+		b = appendShapeFloats(b, 0, 0)
+		b = appendShapeFloats(b, z...)
+	}
+	return b
+}
+
+// partsShape returns a PolyLine or Polygon shape, with Z values if z isn't nil.
+func partsShape(shapeType int32, parts []int, z []float64, points ...[2]float64) []byte {
+	b := appendShapeInts(nil, shapeType)
+	b = appendShapeFloats(b, 0, 0, 0, 0)
+	b = appendShapeInts(b, int32(len(parts)), int32(len(points)))
+	for _, part := range parts {
+		b = appendShapeInts(b, int32(part))
+	}
+	for _, p := range points {
+		b = appendShapeFloats(b, p[0], p[1])
+	}
+	if z != nil {
+		b = appendShapeFloats(b, 0, 0)
+		b = appendShapeFloats(b, z...)
+	}
+	return b
+}
+
+func appendShapeInts(b []byte, values ...int32) []byte {
+	for _, v := range values {
+		b = binary.LittleEndian.AppendUint32(b, uint32(v))
+	}
+	return b
+}
+
+func appendShapeFloats(b []byte, values ...float64) []byte {
+	for _, v := range values {
+		b = binary.LittleEndian.AppendUint64(b, math.Float64bits(v))
+	}
+	return b
+}
+
+type dbfTestField struct {
+	name     string
+	kind     byte
+	size     int
+	decimals int
+}
+
+// dbfTable returns a .dbf file with the fields, where each record starts with the deletion flag.
+func dbfTable(fields []dbfTestField, records ...[]string) []byte {
+	recordLen := 1
+	for _, f := range fields {
+		recordLen += f.size
+	}
+
+	header := make([]byte, 32)
+	header[0] = 3
+	binary.LittleEndian.PutUint32(header[4:], uint32(len(records)))
+	binary.LittleEndian.PutUint16(header[8:], uint16(32+len(fields)*32+1))
+	binary.LittleEndian.PutUint16(header[10:], uint16(recordLen))
+
+	data := header
+	for _, f := range fields {
+		desc := make([]byte, 32)
+		copy(desc, f.name)
+		desc[11] = f.kind
+		desc[16] = byte(f.size)
+		desc[17] = byte(f.decimals)
+		data = append(data, desc...)
+	}
+	data = append(data, 0x0D)
+
+	for _, record := range records {
+		data = append(data, record[0]...)
+		for i, f := range fields {
+			value := []byte(record[i+1])
+			for len(value) < f.size {
+				value = append(value, ' ')
+			}
+			data = append(data, value...)
+		}
+	}
+	return append(data, 0x1A)
+}
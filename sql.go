@@ -0,0 +1,244 @@
+package geojson
+
+import (
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// Geometries implement sql.Scanner and driver.Valuer, so that they can be read from and written to spatial database columns.
+// Values are written as hex-encoded EWKB, which PostGIS accepts as geometry input.
+// Values can be scanned from EWKB, hex-encoded EWKB or GeoJSON text.
+
+// Scan implements the sql.Scanner interface.
+func (p *Point) Scan(src interface{}) error {
+	geo, err := scanGeometryType[*Point](src)
+	if err != nil {
+		return err
+	}
+	*p = *geo
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+func (p Point) Value() (driver.Value, error) {
+	return geometryValue(&p)
+}
+
+// Scan implements the sql.Scanner interface.
+func (m *MultiPoint) Scan(src interface{}) error {
+	geo, err := scanGeometryType[*MultiPoint](src)
+	if err != nil {
+		return err
+	}
+	*m = *geo
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+func (m MultiPoint) Value() (driver.Value, error) {
+	return geometryValue(&m)
+}
+
+// Scan implements the sql.Scanner interface.
+func (l *LineString) Scan(src interface{}) error {
+	geo, err := scanGeometryType[*LineString](src)
+	if err != nil {
+		return err
+	}
+	*l = *geo
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+func (l LineString) Value() (driver.Value, error) {
+	return geometryValue(&l)
+}
+
+// Scan implements the sql.Scanner interface.
+func (m *MultiLineString) Scan(src interface{}) error {
+	geo, err := scanGeometryType[*MultiLineString](src)
+	if err != nil {
+		return err
+	}
+	*m = *geo
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+func (m MultiLineString) Value() (driver.Value, error) {
+	return geometryValue(&m)
+}
+
+// Scan implements the sql.Scanner interface.
+func (p *Polygon) Scan(src interface{}) error {
+	geo, err := scanGeometryType[*Polygon](src)
+	if err != nil {
+		return err
+	}
+	*p = *geo
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+func (p Polygon) Value() (driver.Value, error) {
+	return geometryValue(&p)
+}
+
+// Scan implements the sql.Scanner interface.
+func (m *MultiPolygon) Scan(src interface{}) error {
+	geo, err := scanGeometryType[*MultiPolygon](src)
+	if err != nil {
+		return err
+	}
+	*m = *geo
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+func (m MultiPolygon) Value() (driver.Value, error) {
+	return geometryValue(&m)
+}
+
+// Scan implements the sql.Scanner interface.
+func (c *GeometryCollection) Scan(src interface{}) error {
+	geo, err := scanGeometryType[*GeometryCollection](src)
+	if err != nil {
+		return err
+	}
+	*c = *geo
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+func (c GeometryCollection) Value() (driver.Value, error) {
+	return geometryValue(&c)
+}
+
+// SQLGeometry holds a Geometry of any type, so that columns containing mixed geometry types can be scanned.
+// A nil Geometry corresponds to NULL.
+type SQLGeometry struct {
+	Geometry Geometry
+}
+
+// Scan implements the sql.Scanner interface.
+func (g *SQLGeometry) Scan(src interface{}) error {
+	geo, err := scanGeometry(src)
+	if err != nil {
+		return err
+	}
+	g.Geometry = geo
+	return nil
+}
+
+// Value implements the driver.Valuer interface.
+func (g SQLGeometry) Value() (driver.Value, error) {
+	if g.Geometry == nil {
+		return nil, nil
+	}
+	return geometryValue(g.Geometry)
+}
+
+// Scan implements the sql.Scanner interface.
+// Features are stored as GeoJSON text.
+func (f *Feature[G]) Scan(src interface{}) error {
+	data, err := sqlBytes(src)
+	if err != nil {
+		return err
+	} else if data == nil {
+		return fmt.Errorf("cannot scan NULL into %T", f)
+	}
+	return json.Unmarshal(data, f)
+}
+
+// Value implements the driver.Valuer interface.
+func (f Feature[G]) Value() (driver.Value, error) {
+	data, err := json.Marshal(f)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+// Scan implements the sql.Scanner interface.
+// Feature collections are stored as GeoJSON text.
+func (c *FeatureCollection) Scan(src interface{}) error {
+	data, err := sqlBytes(src)
+	if err != nil {
+		return err
+	} else if data == nil {
+		return fmt.Errorf("cannot scan NULL into %T", c)
+	}
+	return json.Unmarshal(data, c)
+}
+
+// Value implements the driver.Valuer interface.
+func (c FeatureCollection) Value() (driver.Value, error) {
+	data, err := json.Marshal(c)
+	if err != nil {
+		return nil, err
+	}
+	return string(data), nil
+}
+
+func geometryValue(geo Geometry) (driver.Value, error) {
+	data, err := MarshalEWKB(geo, binary.LittleEndian, 0)
+	if err != nil {
+		return nil, err
+	}
+	return hex.EncodeToString(data), nil
+}
+
+func scanGeometryType[G Geometry](src interface{}) (G, error) {
+	var zero G
+	geo, err := scanGeometry(src)
+	if err != nil {
+		return zero, err
+	} else if geo == nil {
+		return zero, fmt.Errorf("cannot scan NULL into %T", zero)
+	}
+
+	g, ok := geo.(G)
+	if !ok {
+		return zero, fmt.Errorf("cannot scan '%s' into %T", geo.Type(), zero)
+	}
+	return g, nil
+}
+
+// scanGeometry decodes EWKB, hex-encoded EWKB or GeoJSON, returning nil for NULL.
+func scanGeometry(src interface{}) (Geometry, error) {
+	data, err := sqlBytes(src)
+	if err != nil || data == nil {
+		return nil, err
+	} else if len(data) == 0 {
+		return nil, fmt.Errorf("cannot scan empty value into geometry")
+	}
+
+	switch data[0] {
+	case 0, 1:
+		return UnmarshalWKB(data)
+	case '{':
+		return unmarshalGeometry(data)
+	default:
+		wkb := make([]byte, hex.DecodedLen(len(data)))
+		if _, err := hex.Decode(wkb, data); err != nil {
+			return nil, fmt.Errorf("failed to decode hex: %w", err)
+		}
+		return UnmarshalWKB(wkb)
+	}
+}
+
+func sqlBytes(src interface{}) ([]byte, error) {
+	switch v := src.(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		return v, nil
+	case string:
+		return []byte(v), nil
+	default:
+		return nil, fmt.Errorf("cannot scan %T", src)
+	}
+}
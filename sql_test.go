@@ -0,0 +1,113 @@
+package geojson_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"encoding/hex"
+	"testing"
+
+	geojson "github.com/everystreet/go-geojson/v3"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	_ sql.Scanner   = &geojson.Polygon{}
+	_ driver.Valuer = geojson.Polygon{}
+	_ sql.Scanner   = &geojson.SQLGeometry{}
+	_ driver.Valuer = geojson.SQLGeometry{}
+	_ sql.Scanner   = &geojson.Feature[geojson.Geometry]{}
+	_ driver.Valuer = geojson.Feature[geojson.Geometry]{}
+)
+
+func TestSQLGeometry(t *testing.T) {
+	line := geojson.NewLineString(
+		geojson.MakePosition(12, 34),
+		geojson.MakePosition(56, 78),
+	)
+
+	value, err := line.Value()
+	require.NoError(t, err)
+	require.Equal(t, "0102000000020000000000000000004140000000000000284000000000008053400000000000004c40", value)
+
+	t.Run("hex", func(t *testing.T) {
+		var scanned geojson.LineString
+		err := scanned.Scan(value)
+		require.NoError(t, err)
+		require.Equal(t, *line, scanned)
+	})
+
+	t.Run("bytes", func(t *testing.T) {
+		data, err := hex.DecodeString(value.(string))
+		require.NoError(t, err)
+
+		var scanned geojson.LineString
+		err = scanned.Scan(data)
+		require.NoError(t, err)
+		require.Equal(t, *line, scanned)
+	})
+
+	t.Run("geojson", func(t *testing.T) {
+		var scanned geojson.LineString
+		err := scanned.Scan([]byte(`{"type":"LineString","coordinates":[[34,12],[78,56]]}`))
+		require.NoError(t, err)
+		require.Equal(t, *line, scanned)
+	})
+
+	t.Run("wrong type", func(t *testing.T) {
+		var scanned geojson.Polygon
+		err := scanned.Scan(value)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "cannot scan 'LineString'")
+	})
+
+	t.Run("null", func(t *testing.T) {
+		var scanned geojson.Point
+		err := scanned.Scan(nil)
+		require.Error(t, err)
+	})
+}
+
+func TestSQLGeometryWrapper(t *testing.T) {
+	var geo geojson.SQLGeometry
+	err := geo.Scan("0101000020e6100000000000000000f03f0000000000000040")
+	require.NoError(t, err)
+	require.Equal(t, geojson.NewPoint(2, 1), geo.Geometry)
+
+	value, err := geo.Value()
+	require.NoError(t, err)
+	require.Equal(t, "0101000000000000000000f03f0000000000000040", value)
+
+	err = geo.Scan(nil)
+	require.NoError(t, err)
+	require.Nil(t, geo.Geometry)
+
+	value, err = geo.Value()
+	require.NoError(t, err)
+	require.Nil(t, value)
+}
+
+func TestSQLFeature(t *testing.T) {
+	feature := geojson.NewFeature[geojson.Geometry](
+		geojson.NewPoint(45.4642035, 9.189982),
+		geojson.Property{Name: "city", Value: "Milan"},
+	)
+
+	value, err := feature.Value()
+	require.NoError(t, err)
+	require.JSONEq(t, `
+		{
+			"type": "Feature",
+			"geometry": {
+				"type": "Point",
+				"coordinates": [9.189982, 45.4642035]
+			},
+			"properties": {
+				"city": "Milan"
+			}
+		}`, value.(string))
+
+	var scanned geojson.Feature[geojson.Geometry]
+	err = scanned.Scan([]byte(value.(string)))
+	require.NoError(t, err)
+	require.Equal(t, feature, scanned)
+}
@@ -0,0 +1,285 @@
+package geojson
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// SVGProjection projects a position onto a plane, with y increasing northwards.
+type SVGProjection func(pos Position) (x, y float64)
+
+// SVGOptions configures the rendering of features as SVG.
+type SVGOptions struct {
+	// Width and Height are the size of the image, which default to 800 and 600.
+	Width, Height float64
+	// Padding is the space around the rendered area, in the same units as Width and Height.
+	Padding float64
+	// Projection projects positions onto the image, which defaults to EquirectangularProjection.
+	Projection SVGProjection
+	// Bounds is the area that is scaled to fit the image, preserving the aspect ratio, which defaults to the extent of the features.
+	Bounds *BoundingBox
+	// Style returns the style of a feature from its properties. Fields of the style that aren't set use the defaults.
+	Style func(props PropertyList) SVGStyle
+}
+
+// SVGStyle is the presentation of a feature.
+type SVGStyle struct {
+	// Fill is the color of Polygons and Points, which defaults to "#3388ff". LineStrings aren't filled.
+	Fill string
+	// Stroke is the color of lines and outlines, which defaults to "#000000".
+	Stroke string
+	// StrokeWidth is the width of lines and outlines, which defaults to 1.
+	StrokeWidth float64
+	// Radius is the radius of the circle drawn for each Point, which defaults to 3.
+	Radius float64
+}
+
+const (
+	svgNamespace     = "http://www.w3.org/2000/svg"
+	svgDefaultWidth  = 800
+	svgDefaultHeight = 600
+)
+
+var svgDefaultStyle = SVGStyle{
+	Fill:        "#3388ff",
+	Stroke:      "#000000",
+	StrokeWidth: 1,
+	Radius:      3,
+}
+
+// EquirectangularProjection uses longitude and latitude as x and y.
+func EquirectangularProjection(pos Position) (x, y float64) {
+	return pos.lng, pos.lat
+}
+
+// WebMercatorProjection projects positions using Web Mercator, with x in degrees of longitude at the equator.
+// Latitudes are clamped to the limits of Web Mercator, approximately 85.05 degrees.
+func WebMercatorProjection(pos Position) (x, y float64) {
+	lat := math.Max(-mvtMaxLatitude, math.Min(mvtMaxLatitude, pos.lat))
+	y = math.Log(math.Tan(math.Pi/4+lat*math.Pi/360)) * 180 / math.Pi
+	return pos.lng, y
+}
+
+// MarshalSVGFeatureCollection returns an SVG image of the features, in the order in which they appear in the collection.
+// Polygon holes are rendered using the even-odd fill rule, and features without a geometry are omitted.
+func MarshalSVGFeatureCollection(c FeatureCollection, opts SVGOptions) ([]byte, error) {
+	return marshalSVG(c.features, opts)
+}
+
+// MarshalSVGFeature returns an SVG image of the feature.
+func MarshalSVGFeature(f Feature[Geometry], opts SVGOptions) ([]byte, error) {
+	return marshalSVG([]Feature[Geometry]{f}, opts)
+}
+
+type svgRenderer struct {
+	buf        bytes.Buffer
+	projection SVGProjection
+	scale      float64
+	minX, maxY float64
+	offsetX    float64
+	offsetY    float64
+}
+
+func marshalSVG(features []Feature[Geometry], opts SVGOptions) ([]byte, error) {
+	if opts.Width == 0 {
+		opts.Width = svgDefaultWidth
+	}
+	if opts.Height == 0 {
+		opts.Height = svgDefaultHeight
+	}
+	if opts.Projection == nil {
+		opts.Projection = EquirectangularProjection
+	}
+
+	r := svgRenderer{projection: opts.Projection}
+	r.fit(features, opts)
+
+	fmt.Fprintf(&r.buf, `<svg xmlns="%s" width="%s" height="%s" viewBox="0 0 %s %s">`+"\n",
+		svgNamespace, formatSVGNumber(opts.Width), formatSVGNumber(opts.Height), formatSVGNumber(opts.Width), formatSVGNumber(opts.Height))
+
+	for _, f := range features {
+		if !f.HasGeometry() {
+			continue
+		}
+
+		style := svgDefaultStyle
+		if opts.Style != nil {
+			style = style.merge(opts.Style(f.properties))
+		}
+
+		if err := r.geometry(f.geometry, style); err != nil {
+			return nil, err
+		}
+	}
+
+	r.buf.WriteString("</svg>\n")
+	return r.buf.Bytes(), nil
+}
+
+// fit sets the transformation from projected coordinates to the image, so that the bounds fit inside the padding.
+func (r *svgRenderer) fit(features []Feature[Geometry], opts SVGOptions) {
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	extend := func(pos Position) {
+		x, y := r.projection(pos)
+		minX, minY = math.Min(minX, x), math.Min(minY, y)
+		maxX, maxY = math.Max(maxX, x), math.Max(maxY, y)
+	}
+
+	if opts.Bounds != nil {
+		extend(opts.Bounds.BottomLeft)
+		extend(opts.Bounds.TopRight)
+	} else {
+		for _, f := range features {
+			eachPosition(f.geometry, extend)
+		}
+	}
+
+	r.scale = 1
+	if minX > maxX {
+		return
+	}
+
+	width, height := opts.Width-2*opts.Padding, opts.Height-2*opts.Padding
+	dx, dy := maxX-minX, maxY-minY
+	switch {
+	case dx > 0 && dy > 0:
+		r.scale = math.Min(width/dx, height/dy)
+	case dx > 0:
+		r.scale = width / dx
+	case dy > 0:
+		r.scale = height / dy
+	}
+
+	// Center the bounds in the image.
+	r.minX, r.maxY = minX, maxY
+	r.offsetX = opts.Padding + (width-dx*r.scale)/2
+	r.offsetY = opts.Padding + (height-dy*r.scale)/2
+}
+
+func (r *svgRenderer) point(pos Position) (x, y float64) {
+	x, y = r.projection(pos)
+	return (x-r.minX)*r.scale + r.offsetX, (r.maxY-y)*r.scale + r.offsetY
+}
+
+func (r *svgRenderer) geometry(geo Geometry, style SVGStyle) error {
+	switch g := geo.(type) {
+	case *Point:
+		r.circle(g.pos, style)
+	case *MultiPoint:
+		for _, pos := range g.positions {
+			r.circle(pos, style)
+		}
+	case *LineString:
+		r.path(r.lines([][]Position{g.positions}, false), "none", "", style)
+	case *MultiLineString:
+		r.path(r.lines(g.lines, false), "none", "", style)
+	case *Polygon:
+		r.path(r.lines(g.rings, true), style.Fill, "evenodd", style)
+	case *MultiPolygon:
+		var rings [][]Position
+		for _, polygon := range g.polygons {
+			rings = append(rings, polygon...)
+		}
+		r.path(r.lines(rings, true), style.Fill, "evenodd", style)
+	case *GeometryCollection:
+		for _, geo := range g.geometries {
+			if err := r.geometry(geo, style); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported geometry type '%T'", geo)
+	}
+	return nil
+}
+
+func (r *svgRenderer) circle(pos Position, style SVGStyle) {
+	x, y := r.point(pos)
+	fmt.Fprintf(&r.buf, `  <circle cx="%s" cy="%s" r="%s"`, formatSVGNumber(x), formatSVGNumber(y), formatSVGNumber(style.Radius))
+	r.attr("fill", style.Fill)
+	r.attr("stroke", style.Stroke)
+	r.attr("stroke-width", formatSVGNumber(style.StrokeWidth))
+	r.buf.WriteString("/>\n")
+}
+
+// lines returns path data for the lines, which are closed if they are polygon rings.
+func (r *svgRenderer) lines(lines [][]Position, closed bool) string {
+	var d bytes.Buffer
+	for _, line := range lines {
+		for i, pos := range line {
+			if closed && i > 0 && i == len(line)-1 {
+				break
+			}
+
+			cmd := 'L'
+			if i == 0 {
+				cmd = 'M'
+				if d.Len() != 0 {
+					d.WriteByte(' ')
+				}
+			} else {
+				d.WriteByte(' ')
+			}
+
+			x, y := r.point(pos)
+			fmt.Fprintf(&d, "%c%s %s", cmd, formatSVGNumber(x), formatSVGNumber(y))
+		}
+
+		if closed && len(line) != 0 {
+			d.WriteString(" Z")
+		}
+	}
+	return d.String()
+}
+
+func (r *svgRenderer) path(d, fill, fillRule string, style SVGStyle) {
+	r.buf.WriteString("  <path")
+	r.attr("d", d)
+	r.attr("fill", fill)
+	r.attr("fill-rule", fillRule)
+	r.attr("stroke", style.Stroke)
+	r.attr("stroke-width", formatSVGNumber(style.StrokeWidth))
+	r.buf.WriteString("/>\n")
+}
+
+// attr writes the attribute if the value isn't empty.
+func (r *svgRenderer) attr(name, value string) {
+	if value == "" {
+		return
+	}
+
+	fmt.Fprintf(&r.buf, ` %s="`, name)
+	_ = xml.EscapeText(&r.buf, []byte(value))
+	r.buf.WriteByte('"')
+}
+
+// merge returns the style with the fields that are set in other replaced.
+func (s SVGStyle) merge(other SVGStyle) SVGStyle {
+	if other.Fill != "" {
+		s.Fill = other.Fill
+	}
+	if other.Stroke != "" {
+		s.Stroke = other.Stroke
+	}
+	if other.StrokeWidth != 0 {
+		s.StrokeWidth = other.StrokeWidth
+	}
+	if other.Radius != 0 {
+		s.Radius = other.Radius
+	}
+	return s
+}
+
+// formatSVGNumber formats the number with at most 2 decimal places.
+func formatSVGNumber(v float64) string {
+	v = math.Round(v*100) / 100
+	if v == 0 {
+		// Avoid formatting negative zero.
+		v = 0
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
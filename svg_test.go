@@ -0,0 +1,107 @@
+package geojson_test
+
+import (
+	"encoding/xml"
+	"testing"
+
+	geojson "github.com/everystreet/go-geojson/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMarshalSVGFeatureCollection(t *testing.T) {
+	data, err := geojson.MarshalSVGFeatureCollection(geojson.NewFeatureCollection(
+		geojson.NewFeature[geojson.Geometry](
+			geojson.NewPolygon(
+				[]geojson.Position{
+					geojson.MakePosition(0, 0),
+					geojson.MakePosition(10, 0),
+					geojson.MakePosition(10, 10),
+					geojson.MakePosition(0, 10),
+					geojson.MakePosition(0, 0),
+				},
+				[]geojson.Position{
+					geojson.MakePosition(2, 2),
+					geojson.MakePosition(2, 8),
+					geojson.MakePosition(8, 8),
+					geojson.MakePosition(8, 2),
+					geojson.MakePosition(2, 2),
+				},
+			),
+			geojson.Property{Name: "kind", Value: "park"},
+		),
+		geojson.NewFeature[geojson.Geometry](
+			geojson.NewLineString(
+				geojson.MakePosition(0, 0),
+				geojson.MakePosition(5, 2.5),
+			),
+			geojson.Property{Name: "kind", Value: "road"},
+		),
+		geojson.NewFeature[geojson.Geometry](nil),
+		geojson.NewFeature[geojson.Geometry](
+			geojson.NewGeometryCollection(
+				geojson.NewPoint(5, 5),
+				geojson.NewMultiPoint(geojson.MakePosition(1, 1)),
+			),
+		),
+	), geojson.SVGOptions{
+		Width:   120,
+		Height:  100,
+		Padding: 10,
+		Style: func(props geojson.PropertyList) geojson.SVGStyle {
+			prop, ok := props.Get("kind")
+			if !ok {
+				return geojson.SVGStyle{Radius: 1.5}
+			} else if prop.Value == "park" {
+				return geojson.SVGStyle{Fill: "green"}
+			}
+			return geojson.SVGStyle{Stroke: "#a0a0a0", StrokeWidth: 2}
+		},
+	})
+	require.NoError(t, err)
+	require.Equal(t, `<svg xmlns="http://www.w3.org/2000/svg" width="120" height="100" viewBox="0 0 120 100">
+  <path d="M20 90 L20 10 L100 10 L100 90 Z M36 74 L84 74 L84 26 L36 26 Z" fill="green" fill-rule="evenodd" stroke="#000000" stroke-width="1"/>
+  <path d="M20 90 L40 50" fill="none" stroke="#a0a0a0" stroke-width="2"/>
+  <circle cx="60" cy="50" r="1.5" fill="#3388ff" stroke="#000000" stroke-width="1"/>
+  <circle cx="28" cy="82" r="1.5" fill="#3388ff" stroke="#000000" stroke-width="1"/>
+</svg>
+`, string(data))
+	require.NoError(t, xml.Unmarshal(data, new(interface{})))
+}
+
+func TestMarshalSVGFeature(t *testing.T) {
+	data, err := geojson.MarshalSVGFeature(
+		geojson.NewFeature[geojson.Geometry](geojson.NewMultiLineString(
+			[]geojson.Position{
+				geojson.MakePosition(0, 0),
+				geojson.MakePosition(0, 1),
+			},
+			[]geojson.Position{
+				geojson.MakePosition(80, 0),
+				geojson.MakePosition(80, 1),
+			},
+		)),
+		geojson.SVGOptions{
+			Width:      100,
+			Height:     100,
+			Projection: geojson.WebMercatorProjection,
+			Bounds: &geojson.BoundingBox{
+				BottomLeft: geojson.MakePosition(-90, -180),
+				TopRight:   geojson.MakePosition(90, 180),
+			},
+			Style: func(geojson.PropertyList) geojson.SVGStyle {
+				return geojson.SVGStyle{Stroke: `a"b`}
+			},
+		},
+	)
+	require.NoError(t, err)
+	require.Equal(t, `<svg xmlns="http://www.w3.org/2000/svg" width="100" height="100" viewBox="0 0 100 100">
+  <path d="M50 50 L50.28 50 M50 11.23 L50.28 11.23" fill="none" stroke="a&#34;b" stroke-width="1"/>
+</svg>
+`, string(data))
+
+	data, err = geojson.MarshalSVGFeature(geojson.Feature[geojson.Geometry]{}, geojson.SVGOptions{})
+	require.NoError(t, err)
+	require.Equal(t, `<svg xmlns="http://www.w3.org/2000/svg" width="800" height="600" viewBox="0 0 800 600">
+</svg>
+`, string(data))
+}
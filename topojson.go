@@ -0,0 +1,674 @@
+package geojson
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+)
+
+// TypePropTopology is the "type" property of a TopoJSON topology.
+const TypePropTopology = "Topology"
+
+// TopologyOptions configures the conversion of FeatureCollections to TopoJSON.
+type TopologyOptions struct {
+	// Quantization is the number of distinct values that each coordinate is rounded to, which must be at least 2.
+	// If set, positions are stored as delta-encoded integers, at the cost of precision.
+	// The default of 0 disables quantization.
+	Quantization int
+}
+
+// MarshalTopoJSON returns the TopoJSON encoding of the named FeatureCollections, each of which is stored as a GeometryCollection object.
+// Lines and polygon rings are split into arcs where they meet, and each arc that is shared by several geometries is stored once.
+// Features without a geometry are stored as geometry objects with a null type, and feature IDs are stored as their "id" members.
+func MarshalTopoJSON(objects map[string]FeatureCollection, opts TopologyOptions) ([]byte, error) {
+	if opts.Quantization == 1 || opts.Quantization < 0 {
+		return nil, fmt.Errorf("quantization must be at least 2")
+	}
+
+	e := topoEncoder{
+		quantization: opts.Quantization,
+		bbox:         [4]float64{math.Inf(1), math.Inf(1), math.Inf(-1), math.Inf(-1)},
+	}
+	for _, c := range objects {
+		for _, f := range c.features {
+			eachPosition(f.geometry, func(pos Position) {
+				e.bbox[0] = math.Min(e.bbox[0], pos.lng)
+				e.bbox[1] = math.Min(e.bbox[1], pos.lat)
+				e.bbox[2] = math.Max(e.bbox[2], pos.lng)
+				e.bbox[3] = math.Max(e.bbox[3], pos.lat)
+			})
+		}
+	}
+	e.setTransform()
+
+	names := make([]string, 0, len(objects))
+	for name := range objects {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	topo := topology{
+		Type:    TypePropTopology,
+		Objects: make(map[string]*topoGeometry, len(objects)),
+	}
+
+	for _, name := range names {
+		geometries := make([]*topoGeometry, len(objects[name].features))
+		for i, f := range objects[name].features {
+			geo := &topoGeometry{}
+			var err error
+			if f.HasGeometry() {
+				if geo, err = e.geometry(f.geometry); err != nil {
+					return nil, fmt.Errorf("object '%s': %w", name, err)
+				}
+			}
+
+			geo.ID = f.id
+			if len(f.properties) != 0 {
+				if geo.Properties, err = json.Marshal(f.properties); err != nil {
+					return nil, err
+				}
+			}
+			geometries[i] = geo
+		}
+
+		typ := string(GeometryCollectionType)
+		topo.Objects[name] = &topoGeometry{
+			Type:       &typ,
+			Geometries: &geometries,
+		}
+	}
+
+	if !math.IsInf(e.bbox[0], 1) {
+		topo.BBox = e.bbox[:]
+	}
+	topo.Transform = e.transform
+	topo.Arcs = e.arcs()
+
+	for _, name := range names {
+		if err := e.resolve(topo.Objects[name]); err != nil {
+			return nil, err
+		}
+	}
+	return json.Marshal(&topo)
+}
+
+// UnmarshalTopoJSON parses the TopoJSON encoded data, and returns a FeatureCollection for each object in the topology.
+// The geometries of GeometryCollection objects become separate features, whereas other objects become a single feature.
+// Geometry objects with a null type become features without a geometry, and "id" members become feature IDs.
+func UnmarshalTopoJSON(data []byte) (map[string]FeatureCollection, error) {
+	var topo struct {
+		Type      string                  `json:"type"`
+		Transform *topoTransform          `json:"transform"`
+		Objects   map[string]topoGeometry `json:"objects"`
+		Arcs      [][][]float64           `json:"arcs"`
+	}
+
+	if err := json.Unmarshal(data, &topo); err != nil {
+		return nil, err
+	} else if topo.Type != TypePropTopology {
+		return nil, fmt.Errorf("type is '%s', expecting '%s'", topo.Type, TypePropTopology)
+	}
+
+	d := topoDecoder{
+		transform: topo.Transform,
+		arcs:      make([][]Position, len(topo.Arcs)),
+	}
+	for i, arc := range topo.Arcs {
+		positions, err := d.positions(arc, true)
+		if err != nil {
+			return nil, fmt.Errorf("arc %d: %w", i, err)
+		}
+		d.arcs[i] = positions
+	}
+
+	collections := make(map[string]FeatureCollection, len(topo.Objects))
+	for name, object := range topo.Objects {
+		members := []topoGeometry{object}
+		if object.Type != nil && *object.Type == string(GeometryCollectionType) && object.Geometries != nil {
+			members = make([]topoGeometry, len(*object.Geometries))
+			for i, member := range *object.Geometries {
+				members[i] = *member
+			}
+		}
+
+		features := make([]Feature[Geometry], len(members))
+		for i, member := range members {
+			var geo Geometry
+			if member.Type != nil {
+				var err error
+				if geo, err = d.geometry(member); err != nil {
+					return nil, fmt.Errorf("object '%s': %w", name, err)
+				}
+			}
+
+			var props PropertyList
+			if len(member.Properties) != 0 && string(member.Properties) != "null" {
+				if err := json.Unmarshal(member.Properties, &props); err != nil {
+					return nil, err
+				}
+			}
+			features[i] = NewFeature(geo, props...)
+			if member.ID != nil {
+				features[i] = features[i].WithID(*member.ID)
+			}
+		}
+		collections[name] = NewFeatureCollection(features...)
+	}
+	return collections, nil
+}
+
+type topology struct {
+	Type      string                   `json:"type"`
+	BBox      []float64                `json:"bbox,omitempty"`
+	Transform *topoTransform           `json:"transform,omitempty"`
+	Objects   map[string]*topoGeometry `json:"objects"`
+	Arcs      [][][]float64            `json:"arcs"`
+}
+
+type topoTransform struct {
+	Scale     [2]float64 `json:"scale"`
+	Translate [2]float64 `json:"translate"`
+}
+
+type topoGeometry struct {
+	// Type is nil if the geometry is null.
+	Type        *string          `json:"type"`
+	Arcs        json.RawMessage  `json:"arcs,omitempty"`
+	Coordinates json.RawMessage  `json:"coordinates,omitempty"`
+	Geometries  *[]*topoGeometry `json:"geometries,omitempty"`
+	ID          *FeatureID       `json:"id,omitempty"`
+	Properties  json.RawMessage  `json:"properties,omitempty"`
+
+	// Indexes of the lines that make up the geometry, nested in the same way as its arcs.
+	lines interface{}
+}
+
+// topoPoint is a position, which is quantized if the topology has a transform.
+type topoPoint struct {
+	x, y, z float64
+	hasZ    bool
+}
+
+func (p topoPoint) less(other topoPoint) bool {
+	if p.x != other.x {
+		return p.x < other.x
+	} else if p.y != other.y {
+		return p.y < other.y
+	}
+	return p.z < other.z
+}
+
+type topoLine struct {
+	points []topoPoint
+	ring   bool
+	arcs   []int
+}
+
+type topoEncoder struct {
+	quantization int
+	bbox         [4]float64
+	transform    *topoTransform
+	lines        []topoLine
+}
+
+func (e *topoEncoder) setTransform() {
+	if e.quantization == 0 || math.IsInf(e.bbox[0], 1) {
+		return
+	}
+
+	n := float64(e.quantization - 1)
+	kx, ky := (e.bbox[2]-e.bbox[0])/n, (e.bbox[3]-e.bbox[1])/n
+	if kx == 0 {
+		kx = 1
+	}
+	if ky == 0 {
+		ky = 1
+	}
+
+	e.transform = &topoTransform{
+		Scale:     [2]float64{kx, ky},
+		Translate: [2]float64{e.bbox[0], e.bbox[1]},
+	}
+}
+
+func (e *topoEncoder) point(pos Position) topoPoint {
+	p := topoPoint{x: pos.lng, y: pos.lat}
+	if e.transform != nil {
+		p.x = math.Round((p.x - e.transform.Translate[0]) / e.transform.Scale[0])
+		p.y = math.Round((p.y - e.transform.Translate[1]) / e.transform.Scale[1])
+	}
+	if pos.elevation != nil {
+		p.z = *pos.elevation
+		p.hasZ = true
+	}
+	return p
+}
+
+func (e *topoEncoder) coordinates(positions ...Position) [][]float64 {
+	coords := make([][]float64, len(positions))
+	for i, pos := range positions {
+		coords[i] = e.point(pos).coordinates()
+	}
+	return coords
+}
+
+func (p topoPoint) coordinates() []float64 {
+	if p.hasZ {
+		return []float64{p.x, p.y, p.z}
+	}
+	return []float64{p.x, p.y}
+}
+
+// line records the positions of a line or ring, and returns its index.
+func (e *topoEncoder) line(positions []Position, ring bool) int {
+	var points []topoPoint
+	for _, pos := range positions {
+		if p := e.point(pos); len(points) == 0 || points[len(points)-1] != p {
+			points = append(points, p)
+		}
+	}
+
+	if ring && len(points) > 1 && points[0] == points[len(points)-1] {
+		points = points[:len(points)-1]
+	}
+
+	e.lines = append(e.lines, topoLine{points: points, ring: ring})
+	return len(e.lines) - 1
+}
+
+func (e *topoEncoder) lineList(lines [][]Position, ring bool) []int {
+	indexes := make([]int, len(lines))
+	for i, line := range lines {
+		indexes[i] = e.line(line, ring)
+	}
+	return indexes
+}
+
+// geometry converts the geometry to a TopoJSON geometry object, which refers to lines whose arcs are determined later.
+func (e *topoEncoder) geometry(geo Geometry) (*topoGeometry, error) {
+	typ := string(geo.Type())
+	t := topoGeometry{Type: &typ}
+
+	var err error
+	switch g := geo.(type) {
+	case *Point:
+		t.Coordinates, err = json.Marshal(e.coordinates(g.pos)[0])
+	case *MultiPoint:
+		t.Coordinates, err = json.Marshal(e.coordinates(g.positions...))
+	case *LineString:
+		t.lines = e.line(g.positions, false)
+	case *MultiLineString:
+		t.lines = e.lineList(g.lines, false)
+	case *Polygon:
+		t.lines = e.lineList(g.rings, true)
+	case *MultiPolygon:
+		polygons := make([][]int, len(g.polygons))
+		for i, polygon := range g.polygons {
+			polygons[i] = e.lineList(polygon, true)
+		}
+		t.lines = polygons
+	case *GeometryCollection:
+		geometries := make([]*topoGeometry, len(g.geometries))
+		for i, geo := range g.geometries {
+			if geometries[i], err = e.geometry(geo); err != nil {
+				return nil, err
+			}
+		}
+		t.Geometries = &geometries
+	default:
+		return nil, fmt.Errorf("unsupported geometry type '%T'", geo)
+	}
+	return &t, err
+}
+
+// arcs splits the lines into arcs at junctions, where lines meet or diverge, and removes duplicate arcs.
+func (e *topoEncoder) arcs() [][][]float64 {
+	junctions := e.junctions()
+
+	var arcs [][][]float64
+	index := map[string]int{}
+	for i, line := range e.lines {
+		for _, arc := range cutTopoLine(line, junctions) {
+			if j, ok := index[topoArcKey(arc, false)]; ok {
+				e.lines[i].arcs = append(e.lines[i].arcs, j)
+				continue
+			} else if j, ok := index[topoArcKey(arc, true)]; ok {
+				e.lines[i].arcs = append(e.lines[i].arcs, ^j)
+				continue
+			}
+
+			index[topoArcKey(arc, false)] = len(arcs)
+			e.lines[i].arcs = append(e.lines[i].arcs, len(arcs))
+			arcs = append(arcs, e.encodeArc(arc))
+		}
+	}
+
+	if arcs == nil {
+		arcs = [][][]float64{}
+	}
+	return arcs
+}
+
+// junctions returns the points at which lines start or end, or where the neighbours of a point differ between lines.
+func (e *topoEncoder) junctions() map[topoPoint]bool {
+	junctions := map[topoPoint]bool{}
+	neighbours := map[topoPoint][2]topoPoint{}
+
+	visit := func(p, prev, next topoPoint) {
+		if seen, ok := neighbours[p]; !ok {
+			neighbours[p] = [2]topoPoint{prev, next}
+		} else if seen != [2]topoPoint{prev, next} && seen != [2]topoPoint{next, prev} {
+			junctions[p] = true
+		}
+	}
+
+	for _, line := range e.lines {
+		n := len(line.points)
+		if n == 0 {
+			continue
+		}
+
+		if !line.ring {
+			junctions[line.points[0]] = true
+			junctions[line.points[n-1]] = true
+			for i := 1; i < n-1; i++ {
+				visit(line.points[i], line.points[i-1], line.points[i+1])
+			}
+			continue
+		}
+
+		for i, p := range line.points {
+			visit(p, line.points[(i+n-1)%n], line.points[(i+1)%n])
+		}
+	}
+	return junctions
+}
+
+// cutTopoLine splits the line into arcs at junctions. Rings are closed, and rings without junctions form a single arc,
+// which starts at the smallest point so that identical rings produce identical arcs.
+func cutTopoLine(line topoLine, junctions map[topoPoint]bool) [][]topoPoint {
+	points := line.points
+	if len(points) == 0 {
+		return nil
+	}
+
+	if line.ring {
+		start := -1
+		for i, p := range points {
+			if junctions[p] {
+				start = i
+				break
+			}
+		}
+
+		if start == -1 {
+			start = 0
+			for i, p := range points {
+				if p.less(points[start]) {
+					start = i
+				}
+			}
+		}
+
+		rotated := make([]topoPoint, 0, len(points)+1)
+		rotated = append(rotated, points[start:]...)
+		rotated = append(rotated, points[:start]...)
+		points = append(rotated, points[start])
+	}
+
+	var arcs [][]topoPoint
+	begin := 0
+	for i := 1; i < len(points)-1; i++ {
+		if junctions[points[i]] {
+			arcs = append(arcs, points[begin:i+1])
+			begin = i
+		}
+	}
+	return append(arcs, points[begin:])
+}
+
+func topoArcKey(arc []topoPoint, reverse bool) string {
+	key := make([]byte, 0, 25*len(arc))
+	for i := range arc {
+		p := arc[i]
+		if reverse {
+			p = arc[len(arc)-1-i]
+		}
+
+		key = binary.LittleEndian.AppendUint64(key, math.Float64bits(p.x))
+		key = binary.LittleEndian.AppendUint64(key, math.Float64bits(p.y))
+		key = binary.LittleEndian.AppendUint64(key, math.Float64bits(p.z))
+		if p.hasZ {
+			key = append(key, 1)
+		} else {
+			key = append(key, 0)
+		}
+	}
+	return string(key)
+}
+
+// encodeArc returns the coordinates of the arc, which are delta-encoded if quantized.
+func (e *topoEncoder) encodeArc(arc []topoPoint) [][]float64 {
+	coords := make([][]float64, len(arc))
+	var prev topoPoint
+	for i, p := range arc {
+		coords[i] = p.coordinates()
+		if e.transform != nil {
+			coords[i][0] -= prev.x
+			coords[i][1] -= prev.y
+		}
+		prev = p
+	}
+	return coords
+}
+
+// resolve replaces the line indexes of the geometry and its children with their arcs.
+func (e *topoEncoder) resolve(t *topoGeometry) error {
+	if t.Geometries != nil {
+		for _, geo := range *t.Geometries {
+			if err := e.resolve(geo); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	var arcs interface{}
+	switch lines := t.lines.(type) {
+	case int:
+		arcs = e.lines[lines].arcs
+	case []int:
+		arcs = e.lineArcs(lines)
+	case [][]int:
+		polygons := make([][][]int, len(lines))
+		for i, polygon := range lines {
+			polygons[i] = e.lineArcs(polygon)
+		}
+		arcs = polygons
+	default:
+		return nil
+	}
+
+	var err error
+	t.Arcs, err = json.Marshal(arcs)
+	return err
+}
+
+func (e *topoEncoder) lineArcs(lines []int) [][]int {
+	arcs := make([][]int, len(lines))
+	for i, line := range lines {
+		arcs[i] = e.lines[line].arcs
+		if arcs[i] == nil {
+			arcs[i] = []int{}
+		}
+	}
+	return arcs
+}
+
+type topoDecoder struct {
+	transform *topoTransform
+	arcs      [][]Position
+}
+
+// positions converts coordinates to positions, which are delta-encoded if quantized arcs.
+func (d *topoDecoder) positions(coords [][]float64, delta bool) ([]Position, error) {
+	positions := make([]Position, len(coords))
+	var x, y float64
+	for i, c := range coords {
+		if len(c) < 2 {
+			return nil, fmt.Errorf("position must have at least 2 values")
+		}
+
+		if d.transform == nil {
+			x, y = c[0], c[1]
+		} else if delta {
+			x, y = x+c[0], y+c[1]
+		} else {
+			x, y = c[0], c[1]
+		}
+
+		lng, lat := x, y
+		if d.transform != nil {
+			lng = x*d.transform.Scale[0] + d.transform.Translate[0]
+			lat = y*d.transform.Scale[1] + d.transform.Translate[1]
+		}
+
+		if len(c) > 2 {
+			positions[i] = MakePositionWithElevation(lat, lng, c[2])
+		} else {
+			positions[i] = MakePosition(lat, lng)
+		}
+	}
+	return positions, nil
+}
+
+// line joins the arcs into a single line, in which the shared points between consecutive arcs appear once.
+func (d *topoDecoder) line(arcs []int) ([]Position, error) {
+	var line []Position
+	for _, i := range arcs {
+		reverse := i < 0
+		if reverse {
+			i = ^i
+		}
+
+		if i >= len(d.arcs) {
+			return nil, fmt.Errorf("arc index %d is out of range", i)
+		}
+
+		arc := d.arcs[i]
+		if reverse {
+			reversed := make([]Position, len(arc))
+			for j, pos := range arc {
+				reversed[len(arc)-1-j] = pos
+			}
+			arc = reversed
+		}
+
+		if len(line) != 0 && len(arc) != 0 {
+			arc = arc[1:]
+		}
+		line = append(line, arc...)
+	}
+	return line, nil
+}
+
+func (d *topoDecoder) lines(arcs [][]int) ([][]Position, error) {
+	lines := make([][]Position, len(arcs))
+	for i, line := range arcs {
+		positions, err := d.line(line)
+		if err != nil {
+			return nil, err
+		}
+		lines[i] = positions
+	}
+	return lines, nil
+}
+
+func (d *topoDecoder) geometry(t topoGeometry) (Geometry, error) {
+	if t.Type == nil {
+		return nil, fmt.Errorf("geometry collection must not contain null geometries")
+	}
+
+	switch GeometryType(*t.Type) {
+	case PointGeometryType:
+		var coords []float64
+		if err := json.Unmarshal(t.Coordinates, &coords); err != nil {
+			return nil, err
+		}
+
+		positions, err := d.positions([][]float64{coords}, false)
+		if err != nil {
+			return nil, err
+		}
+		return NewPointFromPosition(positions[0]), nil
+	case MultiPointGeometryType:
+		var coords [][]float64
+		if err := json.Unmarshal(t.Coordinates, &coords); err != nil {
+			return nil, err
+		}
+
+		positions, err := d.positions(coords, false)
+		if err != nil {
+			return nil, err
+		}
+		return NewMultiPoint(positions...), nil
+	case LineStringGeometryType:
+		var arcs []int
+		if err := json.Unmarshal(t.Arcs, &arcs); err != nil {
+			return nil, err
+		}
+
+		line, err := d.line(arcs)
+		if err != nil {
+			return nil, err
+		}
+		return &LineString{positions: line}, nil
+	case MultiLineStringGeometryType, PolygonGeometryType:
+		var arcs [][]int
+		if err := json.Unmarshal(t.Arcs, &arcs); err != nil {
+			return nil, err
+		}
+
+		lines, err := d.lines(arcs)
+		if err != nil {
+			return nil, err
+		}
+
+		if GeometryType(*t.Type) == PolygonGeometryType {
+			return NewPolygon(lines...), nil
+		}
+		return NewMultiLineString(lines...), nil
+	case MultiPolygonGeometryType:
+		var arcs [][][]int
+		if err := json.Unmarshal(t.Arcs, &arcs); err != nil {
+			return nil, err
+		}
+
+		polygons := make([][][]Position, len(arcs))
+		for i, polygon := range arcs {
+			rings, err := d.lines(polygon)
+			if err != nil {
+				return nil, err
+			}
+			polygons[i] = rings
+		}
+		return NewMultiPolygon(polygons...), nil
+	case GeometryCollectionType:
+		var geometries []Geometry
+		if t.Geometries != nil {
+			for _, member := range *t.Geometries {
+				geo, err := d.geometry(*member)
+				if err != nil {
+					return nil, err
+				}
+				geometries = append(geometries, geo)
+			}
+		}
+		return NewGeometryCollection(geometries...), nil
+	default:
+		return nil, fmt.Errorf("unsupported geometry type '%s'", *t.Type)
+	}
+}
@@ -0,0 +1,196 @@
+package geojson_test
+
+import (
+	"testing"
+
+	geojson "github.com/everystreet/go-geojson/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTopoJSON(t *testing.T) {
+	objects := map[string]geojson.FeatureCollection{
+		"regions": geojson.NewFeatureCollection(
+			geojson.NewFeature[geojson.Geometry](
+				geojson.NewPolygon([]geojson.Position{
+					geojson.MakePosition(1, 1),
+					geojson.MakePosition(0, 1),
+					geojson.MakePosition(0, 0),
+					geojson.MakePosition(1, 0),
+					geojson.MakePosition(1, 1),
+				}),
+				geojson.Property{Name: "name", Value: "a"},
+			).WithID(geojson.StringFeatureID("a")),
+			geojson.NewFeature[geojson.Geometry](
+				geojson.NewPolygon([]geojson.Position{
+					geojson.MakePosition(0, 1),
+					geojson.MakePosition(1, 1),
+					geojson.MakePosition(1, 2),
+					geojson.MakePosition(0, 2),
+					geojson.MakePosition(0, 1),
+				}),
+				geojson.Property{Name: "name", Value: "b"},
+			),
+			geojson.NewFeature[geojson.Geometry](
+				nil,
+				geojson.Property{Name: "name", Value: "c"},
+			).WithID(geojson.IntFeatureID(3)),
+		),
+	}
+
+	data, err := geojson.MarshalTopoJSON(objects, geojson.TopologyOptions{Quantization: 3})
+	require.NoError(t, err)
+	require.JSONEq(t, `
+		{
+			"type": "Topology",
+			"bbox": [0, 0, 2, 1],
+			"transform": {"scale": [1, 0.5], "translate": [0, 0]},
+			"objects": {
+				"regions": {
+					"type": "GeometryCollection",
+					"geometries": [
+						{"type": "Polygon", "arcs": [[0, 1]], "id": "a", "properties": {"name": "a"}},
+						{"type": "Polygon", "arcs": [[-1, 2]], "properties": {"name": "b"}},
+						{"type": null, "id": 3, "properties": {"name": "c"}}
+					]
+				}
+			},
+			"arcs": [
+				[[1, 2], [0, -2]],
+				[[1, 0], [-1, 0], [0, 2], [1, 0]],
+				[[1, 2], [1, 0], [0, -2], [-1, 0]]
+			]
+		}`, string(data))
+
+	unmarshalled, err := geojson.UnmarshalTopoJSON(data)
+	require.NoError(t, err)
+	require.Equal(t, objects, unmarshalled)
+}
+
+func TestTopoJSONGeometries(t *testing.T) {
+	objects := map[string]geojson.FeatureCollection{
+		"points": geojson.NewFeatureCollection(
+			geojson.NewFeature[geojson.Geometry](geojson.NewPoint(45.4642035, 9.189982)),
+			geojson.NewFeature[geojson.Geometry](geojson.NewMultiPoint(
+				geojson.MakePositionWithElevation(12, 34, 1),
+				geojson.MakePositionWithElevation(56, 78, 2),
+			)),
+		),
+		"lines": geojson.NewFeatureCollection(
+			geojson.NewFeature[geojson.Geometry](geojson.NewLineString(
+				geojson.MakePosition(0, 0),
+				geojson.MakePosition(1, 1),
+				geojson.MakePosition(2, 2),
+				geojson.MakePosition(3, 3),
+			)),
+			geojson.NewFeature[geojson.Geometry](geojson.NewMultiLineString(
+				[]geojson.Position{
+					geojson.MakePosition(5, 0),
+					geojson.MakePosition(1, 1),
+					geojson.MakePosition(2, 2),
+					geojson.MakePosition(5, 5),
+				},
+				[]geojson.Position{
+					geojson.MakePosition(3, 3),
+					geojson.MakePosition(2, 2),
+				},
+			)),
+		),
+		"polygons": geojson.NewFeatureCollection(
+			geojson.NewFeature[geojson.Geometry](geojson.NewMultiPolygon(
+				[][]geojson.Position{
+					{
+						geojson.MakePosition(3, 4),
+						geojson.MakePosition(7, 7),
+						geojson.MakePosition(4, 8),
+						geojson.MakePosition(3, 4),
+					},
+				},
+				[][]geojson.Position{
+					{
+						geojson.MakePosition(3, 4),
+						geojson.MakePosition(4, 8),
+						geojson.MakePosition(7, 7),
+						geojson.MakePosition(3, 4),
+					},
+				},
+			)),
+			geojson.NewFeature[geojson.Geometry](geojson.NewGeometryCollection(
+				geojson.NewPoint(1, 2),
+				geojson.NewLineString(
+					geojson.MakePosition(1, 1),
+					geojson.MakePosition(2, 2),
+				),
+			)),
+		),
+	}
+
+	data, err := geojson.MarshalTopoJSON(objects, geojson.TopologyOptions{})
+	require.NoError(t, err)
+
+	unmarshalled, err := geojson.UnmarshalTopoJSON(data)
+	require.NoError(t, err)
+	require.Equal(t, objects, unmarshalled)
+}
+
+func TestTopoJSONSharedArcs(t *testing.T) {
+	ring := []geojson.Position{
+		geojson.MakePosition(7, 7),
+		geojson.MakePosition(4, 8),
+		geojson.MakePosition(3, 4),
+		geojson.MakePosition(7, 7),
+	}
+
+	data, err := geojson.MarshalTopoJSON(map[string]geojson.FeatureCollection{
+		"a": geojson.NewFeatureCollection(geojson.NewFeature[geojson.Geometry](geojson.NewPolygon(ring))),
+		"b": geojson.NewFeatureCollection(geojson.NewFeature[geojson.Geometry](geojson.NewPolygon(ring))),
+	}, geojson.TopologyOptions{})
+	require.NoError(t, err)
+	require.JSONEq(t, `
+		{
+			"type": "Topology",
+			"bbox": [4, 3, 8, 7],
+			"objects": {
+				"a": {"type": "GeometryCollection", "geometries": [{"type": "Polygon", "arcs": [[0]]}]},
+				"b": {"type": "GeometryCollection", "geometries": [{"type": "Polygon", "arcs": [[0]]}]}
+			},
+			"arcs": [[[4, 3], [7, 7], [8, 4], [4, 3]]]
+		}`, string(data))
+}
+
+func TestUnmarshalTopoJSON(t *testing.T) {
+	collections, err := geojson.UnmarshalTopoJSON([]byte(`
+		{
+			"type": "Topology",
+			"transform": {"scale": [0.5, 2], "translate": [100, 10]},
+			"objects": {
+				"line": {"type": "LineString", "arcs": [0, -2], "properties": {"name": "a"}},
+				"point": {"type": "Point", "coordinates": [4, 2], "id": 12345678901234567890}
+			},
+			"arcs": [
+				[[0, 0], [2, 1]],
+				[[4, 3], [-2, -2]]
+			]
+		}`))
+	require.NoError(t, err)
+	require.Equal(t, map[string]geojson.FeatureCollection{
+		"line": geojson.NewFeatureCollection(
+			geojson.NewFeature[geojson.Geometry](
+				geojson.NewLineString(
+					geojson.MakePosition(10, 100),
+					geojson.MakePosition(12, 101),
+					geojson.MakePosition(16, 102),
+				),
+				geojson.Property{Name: "name", Value: "a"},
+			),
+		),
+		"point": geojson.NewFeatureCollection(
+			geojson.NewFeature[geojson.Geometry](geojson.NewPoint(14, 102)).WithID(geojson.UintFeatureID(12345678901234567890)),
+		),
+	}, collections)
+
+	_, err = geojson.UnmarshalTopoJSON([]byte(`{"type": "Topology", "objects": {"a": {"type": "LineString", "arcs": [3]}}, "arcs": []}`))
+	require.Error(t, err)
+
+	_, err = geojson.UnmarshalTopoJSON([]byte(`{"type": "FeatureCollection", "features": []}`))
+	require.Error(t, err)
+}
@@ -0,0 +1,549 @@
+package geojson
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// TWKBOptions control the Tiny Well-Known Binary encoding of a geometry.
+type TWKBOptions struct {
+	// Precision is the number of decimal digits retained for longitude and latitude, between -8 and 7.
+	Precision int
+	// ZPrecision is the number of decimal digits retained for elevation, between 0 and 7.
+	ZPrecision int
+	// BoundingBox includes the bounding box of the geometry.
+	BoundingBox bool
+	// Size includes the size of the encoded geometry, allowing readers to skip over it.
+	Size bool
+	// IDs of each member of a multi-geometry or geometry collection.
+	IDs []int64
+}
+
+const (
+	twkbBoundingBox = 1 << iota
+	twkbSize
+	twkbIDList
+	twkbExtendedPrecision
+	twkbEmpty
+)
+
+// MarshalTWKB returns the Tiny Well-Known Binary encoding of the geometry.
+func MarshalTWKB(geo Geometry, opts TWKBOptions) ([]byte, error) {
+	if opts.Precision < -8 || opts.Precision > 7 {
+		return nil, fmt.Errorf("precision must be between -8 and 7")
+	} else if opts.ZPrecision < 0 || opts.ZPrecision > 7 {
+		return nil, fmt.Errorf("z precision must be between 0 and 7")
+	}
+
+	var typ uint32
+	var positions [][]Position
+	var members int
+
+	switch g := geo.(type) {
+	case *Point:
+		typ, positions = wkbPoint, [][]Position{{g.pos}}
+	case *MultiPoint:
+		typ, positions, members = wkbMultiPoint, [][]Position{g.positions}, len(g.positions)
+	case *LineString:
+		typ, positions = wkbLineString, [][]Position{g.positions}
+	case *MultiLineString:
+		typ, positions, members = wkbMultiLineString, g.lines, len(g.lines)
+	case *Polygon:
+		typ, positions = wkbPolygon, g.rings
+	case *MultiPolygon:
+		typ, members = wkbMultiPolygon, len(g.polygons)
+		for _, polygon := range g.polygons {
+			positions = append(positions, polygon...)
+		}
+	case *GeometryCollection:
+		typ, members = wkbGeometryCollection, len(g.geometries)
+	default:
+		return nil, fmt.Errorf("unsupported geometry type '%T'", geo)
+	}
+
+	if opts.IDs != nil && len(opts.IDs) != members {
+		return nil, fmt.Errorf("expecting %d IDs but have %d", members, len(opts.IDs))
+	}
+
+	hasZ, empty, err := dimension(positions)
+	if err != nil {
+		return nil, err
+	}
+	if c, ok := geo.(*GeometryCollection); ok {
+		empty = len(c.geometries) == 0
+	}
+
+	w := twkbWriter{
+		scale:  math.Pow10(opts.Precision),
+		zScale: math.Pow10(opts.ZPrecision),
+		hasZ:   hasZ,
+	}
+
+	var body []byte
+	if !empty {
+		if opts.BoundingBox && typ != wkbGeometryCollection {
+			body = w.boundingBox(positions)
+		}
+
+		if body, err = w.body(body, geo, opts); err != nil {
+			return nil, err
+		}
+	}
+
+	var metadata byte
+	switch {
+	case empty:
+		metadata |= twkbEmpty
+	case opts.BoundingBox && typ != wkbGeometryCollection:
+		metadata |= twkbBoundingBox
+	}
+	if opts.Size {
+		metadata |= twkbSize
+	}
+	if opts.IDs != nil && !empty {
+		metadata |= twkbIDList
+	}
+	if hasZ {
+		metadata |= twkbExtendedPrecision
+	}
+
+	data := []byte{byte(typ) | byte(zigzag(int64(opts.Precision)))<<4, metadata}
+	if hasZ {
+		data = append(data, 1|byte(opts.ZPrecision)<<2)
+	}
+	if opts.Size {
+		data = binary.AppendUvarint(data, uint64(len(body)))
+	}
+	return append(data, body...), nil
+}
+
+// UnmarshalTWKB parses the Tiny Well-Known Binary encoded geometry, returning the IDs of its members if present.
+// M values are discarded.
+func UnmarshalTWKB(data []byte) (Geometry, []int64, error) {
+	r := twkbReader{data: data}
+	geo, ids, err := r.geometry()
+	if err != nil {
+		return nil, nil, err
+	} else if r.pos != len(r.data) {
+		return nil, nil, fmt.Errorf("unexpected %d bytes after geometry", len(r.data)-r.pos)
+	}
+	return geo, ids, nil
+}
+
+type twkbWriter struct {
+	scale  float64
+	zScale float64
+	hasZ   bool
+	prev   [3]int64
+}
+
+func (w *twkbWriter) body(data []byte, geo Geometry, opts TWKBOptions) ([]byte, error) {
+	appendIDs := func(n int) {
+		data = binary.AppendUvarint(data, uint64(n))
+		for _, id := range opts.IDs {
+			data = binary.AppendVarint(data, id)
+		}
+	}
+
+	switch g := geo.(type) {
+	case *Point:
+		data = w.position(data, g.pos)
+	case *MultiPoint:
+		appendIDs(len(g.positions))
+		for _, pos := range g.positions {
+			data = w.position(data, pos)
+		}
+	case *LineString:
+		data = w.positions(data, g.positions)
+	case *MultiLineString:
+		appendIDs(len(g.lines))
+		for _, line := range g.lines {
+			data = w.positions(data, line)
+		}
+	case *Polygon:
+		data = w.rings(data, g.rings)
+	case *MultiPolygon:
+		appendIDs(len(g.polygons))
+		for _, polygon := range g.polygons {
+			data = w.rings(data, polygon)
+		}
+	case *GeometryCollection:
+		appendIDs(len(g.geometries))
+		for _, geo := range g.geometries {
+			member, err := MarshalTWKB(geo, TWKBOptions{
+				Precision:   opts.Precision,
+				ZPrecision:  opts.ZPrecision,
+				BoundingBox: opts.BoundingBox,
+				Size:        opts.Size,
+			})
+			if err != nil {
+				return nil, err
+			}
+			data = append(data, member...)
+		}
+	}
+	return data, nil
+}
+
+func (w *twkbWriter) rings(data []byte, rings [][]Position) []byte {
+	data = binary.AppendUvarint(data, uint64(len(rings)))
+	for _, ring := range rings {
+		data = w.positions(data, ring)
+	}
+	return data
+}
+
+func (w *twkbWriter) positions(data []byte, positions []Position) []byte {
+	data = binary.AppendUvarint(data, uint64(len(positions)))
+	for _, pos := range positions {
+		data = w.position(data, pos)
+	}
+	return data
+}
+
+func (w *twkbWriter) position(data []byte, pos Position) []byte {
+	coords := w.quantize(pos)
+	for i := 0; i < w.dims(); i++ {
+		data = binary.AppendVarint(data, coords[i]-w.prev[i])
+		w.prev[i] = coords[i]
+	}
+	return data
+}
+
+func (w *twkbWriter) boundingBox(positions [][]Position) []byte {
+	min := [3]int64{math.MaxInt64, math.MaxInt64, math.MaxInt64}
+	max := [3]int64{math.MinInt64, math.MinInt64, math.MinInt64}
+	for _, list := range positions {
+		for _, pos := range list {
+			coords := w.quantize(pos)
+			for i := 0; i < w.dims(); i++ {
+				if coords[i] < min[i] {
+					min[i] = coords[i]
+				}
+				if coords[i] > max[i] {
+					max[i] = coords[i]
+				}
+			}
+		}
+	}
+
+	var data []byte
+	for i := 0; i < w.dims(); i++ {
+		data = binary.AppendVarint(data, min[i])
+		data = binary.AppendVarint(data, max[i]-min[i])
+	}
+	return data
+}
+
+func (w *twkbWriter) quantize(pos Position) [3]int64 {
+	coords := [3]int64{
+		int64(math.Round(pos.lng * w.scale)),
+		int64(math.Round(pos.lat * w.scale)),
+	}
+	if w.hasZ {
+		coords[2] = int64(math.Round(*pos.elevation * w.zScale))
+	}
+	return coords
+}
+
+func (w *twkbWriter) dims() int {
+	if w.hasZ {
+		return 3
+	}
+	return 2
+}
+
+type twkbReader struct {
+	data []byte
+	pos  int
+
+	scale  float64
+	zScale float64
+	hasZ   bool
+	hasM   bool
+	prev   [4]int64
+}
+
+func (r *twkbReader) geometry() (Geometry, []int64, error) {
+	if len(r.data)-r.pos < 2 {
+		return nil, nil, fmt.Errorf("unexpected end of data")
+	}
+
+	typ := uint32(r.data[r.pos] & 0x0F)
+	r.scale = math.Pow10(int(unzigzag(uint64(r.data[r.pos] >> 4))))
+	metadata := r.data[r.pos+1]
+	r.pos += 2
+
+	r.hasZ, r.hasM = false, false
+	r.zScale = 1
+	r.prev = [4]int64{}
+
+	if metadata&twkbExtendedPrecision != 0 {
+		if r.pos == len(r.data) {
+			return nil, nil, fmt.Errorf("unexpected end of data")
+		}
+		ext := r.data[r.pos]
+		r.pos++
+
+		r.hasZ = ext&1 != 0
+		r.hasM = ext&2 != 0
+		r.zScale = math.Pow10(int(ext >> 2 & 0x07))
+	}
+
+	if metadata&twkbSize != 0 {
+		size, err := r.uvarint()
+		if err != nil {
+			return nil, nil, err
+		} else if size > uint64(len(r.data)-r.pos) {
+			return nil, nil, fmt.Errorf("size %d exceeds remaining data", size)
+		}
+	}
+
+	if metadata&twkbEmpty != 0 {
+		return twkbEmptyGeometry(typ)
+	}
+
+	if metadata&twkbBoundingBox != 0 {
+		dims := r.dims()
+		if r.hasM {
+			dims++
+		}
+
+		for i := 0; i < 2*dims; i++ {
+			if _, err := r.varint(); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+
+	readIDs := func(n int) ([]int64, error) {
+		if metadata&twkbIDList == 0 {
+			return nil, nil
+		}
+
+		ids := make([]int64, n)
+		for i := range ids {
+			var err error
+			if ids[i], err = r.varint(); err != nil {
+				return nil, err
+			}
+		}
+		return ids, nil
+	}
+
+	switch typ {
+	case wkbPoint:
+		pos, err := r.position()
+		if err != nil {
+			return nil, nil, err
+		}
+		return NewPointFromPosition(pos), nil, nil
+
+	case wkbLineString:
+		positions, err := r.positions()
+		if err != nil {
+			return nil, nil, err
+		}
+		return &LineString{positions: positions}, nil, nil
+
+	case wkbPolygon:
+		rings, err := r.rings()
+		if err != nil {
+			return nil, nil, err
+		}
+		return NewPolygon(rings...), nil, nil
+
+	case wkbMultiPoint:
+		n, err := r.count()
+		if err != nil {
+			return nil, nil, err
+		}
+		ids, err := readIDs(n)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		positions := make([]Position, n)
+		for i := range positions {
+			if positions[i], err = r.position(); err != nil {
+				return nil, nil, err
+			}
+		}
+		return NewMultiPoint(positions...), ids, nil
+
+	case wkbMultiLineString:
+		n, err := r.count()
+		if err != nil {
+			return nil, nil, err
+		}
+		ids, err := readIDs(n)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		lines := make([][]Position, n)
+		for i := range lines {
+			if lines[i], err = r.positions(); err != nil {
+				return nil, nil, err
+			}
+		}
+		return NewMultiLineString(lines...), ids, nil
+
+	case wkbMultiPolygon:
+		n, err := r.count()
+		if err != nil {
+			return nil, nil, err
+		}
+		ids, err := readIDs(n)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		polygons := make([][][]Position, n)
+		for i := range polygons {
+			if polygons[i], err = r.rings(); err != nil {
+				return nil, nil, err
+			}
+		}
+		return NewMultiPolygon(polygons...), ids, nil
+
+	case wkbGeometryCollection:
+		n, err := r.count()
+		if err != nil {
+			return nil, nil, err
+		}
+		ids, err := readIDs(n)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		geometries := make([]Geometry, n)
+		for i := range geometries {
+			if geometries[i], _, err = r.geometry(); err != nil {
+				return nil, nil, err
+			}
+		}
+		return NewGeometryCollection(geometries...), ids, nil
+
+	default:
+		return nil, nil, fmt.Errorf("unsupported geometry type '%d'", typ)
+	}
+}
+
+func twkbEmptyGeometry(typ uint32) (Geometry, []int64, error) {
+	switch typ {
+	case wkbPoint:
+		return nil, nil, errEmptyPoint
+	case wkbLineString:
+		return &LineString{}, nil, nil
+	case wkbPolygon:
+		return NewPolygon(), nil, nil
+	case wkbMultiPoint:
+		return NewMultiPoint(), nil, nil
+	case wkbMultiLineString:
+		return NewMultiLineString(), nil, nil
+	case wkbMultiPolygon:
+		return NewMultiPolygon(), nil, nil
+	case wkbGeometryCollection:
+		return NewGeometryCollection(), nil, nil
+	default:
+		return nil, nil, fmt.Errorf("unsupported geometry type '%d'", typ)
+	}
+}
+
+func (r *twkbReader) rings() ([][]Position, error) {
+	n, err := r.count()
+	if err != nil {
+		return nil, err
+	}
+
+	rings := make([][]Position, n)
+	for i := range rings {
+		if rings[i], err = r.positions(); err != nil {
+			return nil, err
+		}
+	}
+	return rings, nil
+}
+
+func (r *twkbReader) positions() ([]Position, error) {
+	n, err := r.count()
+	if err != nil {
+		return nil, err
+	}
+
+	positions := make([]Position, n)
+	for i := range positions {
+		if positions[i], err = r.position(); err != nil {
+			return nil, err
+		}
+	}
+	return positions, nil
+}
+
+func (r *twkbReader) position() (Position, error) {
+	n := r.dims()
+	if r.hasM {
+		n++
+	}
+
+	for i := 0; i < n; i++ {
+		delta, err := r.varint()
+		if err != nil {
+			return Position{}, err
+		}
+		r.prev[i] += delta
+	}
+
+	lng := float64(r.prev[0]) / r.scale
+	lat := float64(r.prev[1]) / r.scale
+	if r.hasZ {
+		return MakePositionWithElevation(lat, lng, float64(r.prev[2])/r.zScale), nil
+	}
+	return MakePosition(lat, lng), nil
+}
+
+// count reads the number of elements that follow, each of which occupies at least 1 byte.
+func (r *twkbReader) count() (int, error) {
+	n, err := r.uvarint()
+	if err != nil {
+		return 0, err
+	} else if n > uint64(len(r.data)-r.pos) {
+		return 0, fmt.Errorf("count %d exceeds remaining data", n)
+	}
+	return int(n), nil
+}
+
+func (r *twkbReader) dims() int {
+	if r.hasZ {
+		return 3
+	}
+	return 2
+}
+
+func (r *twkbReader) uvarint() (uint64, error) {
+	v, n := binary.Uvarint(r.data[r.pos:])
+	if n <= 0 {
+		return 0, fmt.Errorf("invalid varint")
+	}
+	r.pos += n
+	return v, nil
+}
+
+func (r *twkbReader) varint() (int64, error) {
+	v, n := binary.Varint(r.data[r.pos:])
+	if n <= 0 {
+		return 0, fmt.Errorf("invalid varint")
+	}
+	r.pos += n
+	return v, nil
+}
+
+func zigzag(v int64) uint64 {
+	return uint64(v<<1) ^ uint64(v>>63)
+}
+
+func unzigzag(v uint64) int64 {
+	return int64(v>>1) ^ -int64(v&1)
+}
@@ -0,0 +1,133 @@
+package geojson_test
+
+import (
+	"encoding/hex"
+	"testing"
+
+	geojson "github.com/everystreet/go-geojson/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTWKB(t *testing.T) {
+	tests := []struct {
+		name     string
+		geometry geojson.Geometry
+		opts     geojson.TWKBOptions
+		twkb     string
+	}{
+		{
+			name:     "point",
+			geometry: geojson.NewPoint(2, 1),
+			twkb:     "01000204",
+		},
+		{
+			name: "linestring",
+			geometry: geojson.NewLineString(
+				geojson.MakePosition(1, 1),
+				geojson.MakePosition(5, 5),
+			),
+			twkb: "02000202020808",
+		},
+		{
+			name: "linestring with bounding box and size",
+			geometry: geojson.NewLineString(
+				geojson.MakePosition(1, 1),
+				geojson.MakePosition(5, 5),
+			),
+			opts: geojson.TWKBOptions{BoundingBox: true, Size: true},
+			twkb: "020309020802080202020808",
+		},
+		{
+			name: "linestring with precision",
+			geometry: geojson.NewLineString(
+				geojson.MakePosition(45.46, 9.18),
+				geojson.MakePosition(45.47, 9.19),
+			),
+			opts: geojson.TWKBOptions{Precision: 2},
+			twkb: "420002ac0e84470202",
+		},
+		{
+			name:     "point with elevation",
+			geometry: geojson.NewPointWithElevation(2, 1, 3.5),
+			opts:     geojson.TWKBOptions{ZPrecision: 1},
+			twkb:     "010805020446",
+		},
+		{
+			name: "polygon",
+			geometry: geojson.NewPolygon(
+				[]geojson.Position{
+					geojson.MakePosition(0, 0),
+					geojson.MakePosition(1, 0),
+					geojson.MakePosition(1, 1),
+					geojson.MakePosition(0, 0),
+				},
+			),
+			twkb: "030001040000000202000101",
+		},
+		{
+			name: "multipoint with ids",
+			geometry: geojson.NewMultiPoint(
+				geojson.MakePosition(0, 0),
+				geojson.MakePosition(1, 1),
+			),
+			opts: geojson.TWKBOptions{IDs: []int64{5, -3}},
+			twkb: "040402" + "0a05" + "0000" + "0202",
+		},
+		{
+			name:     "empty linestring",
+			geometry: &geojson.LineString{},
+			twkb:     "0210",
+		},
+		{
+			name: "geometry collection",
+			geometry: geojson.NewGeometryCollection(
+				geojson.NewPoint(2, 1),
+				geojson.NewMultiPolygon(),
+			),
+			twkb: "070002" + "01000204" + "0610",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := geojson.MarshalTWKB(tt.geometry, tt.opts)
+			require.NoError(t, err)
+			require.Equal(t, tt.twkb, hex.EncodeToString(data))
+
+			geo, ids, err := geojson.UnmarshalTWKB(data)
+			require.NoError(t, err)
+			require.Equal(t, tt.geometry, geo)
+			require.Equal(t, tt.opts.IDs, ids)
+		})
+	}
+}
+
+func TestTWKBErrors(t *testing.T) {
+	t.Run("precision", func(t *testing.T) {
+		_, err := geojson.MarshalTWKB(geojson.NewPoint(1, 2), geojson.TWKBOptions{Precision: 8})
+		require.Error(t, err)
+	})
+
+	t.Run("ids", func(t *testing.T) {
+		_, err := geojson.MarshalTWKB(
+			geojson.NewMultiPoint(geojson.MakePosition(1, 2)),
+			geojson.TWKBOptions{IDs: []int64{1, 2}},
+		)
+		require.Error(t, err)
+	})
+
+	for name, data := range map[string]string{
+		"truncated":    "0200020202",
+		"unknown type": "0900",
+		"empty point":  "0110",
+		"huge count":   "0200ff7f",
+	} {
+		t.Run(name, func(t *testing.T) {
+			b, err := hex.DecodeString(data)
+			require.NoError(t, err)
+
+			_, _, err = geojson.UnmarshalTWKB(b)
+			require.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,457 @@
+package geojson
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// WKB geometry type codes.
+const (
+	wkbPoint              uint32 = 1
+	wkbLineString         uint32 = 2
+	wkbPolygon            uint32 = 3
+	wkbMultiPoint         uint32 = 4
+	wkbMultiLineString    uint32 = 5
+	wkbMultiPolygon       uint32 = 6
+	wkbGeometryCollection uint32 = 7
+
+	ewkbZFlag    uint32 = 0x80000000
+	ewkbMFlag    uint32 = 0x40000000
+	ewkbSRIDFlag uint32 = 0x20000000
+)
+
+// MarshalWKB returns the ISO Well-Known Binary encoding of the geometry, using the specified byte order.
+// Positions with elevation are encoded using the Z variant of each geometry type,
+// so all positions, including those of the members of a GeometryCollection, must have the same dimension.
+func MarshalWKB(geo Geometry, order binary.ByteOrder) ([]byte, error) {
+	w, err := newWKBWriter(order, false)
+	if err != nil {
+		return nil, err
+	}
+	return w.geometry(geo, 0)
+}
+
+// MarshalEWKB returns the Extended Well-Known Binary encoding of the geometry, as used by PostGIS.
+// The SRID is omitted if it is 0.
+func MarshalEWKB(geo Geometry, order binary.ByteOrder, srid uint32) ([]byte, error) {
+	w, err := newWKBWriter(order, true)
+	if err != nil {
+		return nil, err
+	}
+	return w.geometry(geo, srid)
+}
+
+// UnmarshalWKB parses the ISO Well-Known Binary or Extended Well-Known Binary encoded geometry.
+// Any SRID is discarded, as are M values.
+func UnmarshalWKB(data []byte) (Geometry, error) {
+	geo, _, err := UnmarshalEWKB(data)
+	return geo, err
+}
+
+// UnmarshalEWKB parses the ISO Well-Known Binary or Extended Well-Known Binary encoded geometry,
+// and returns it along with its SRID, which is 0 if not present. M values are discarded.
+func UnmarshalEWKB(data []byte) (Geometry, uint32, error) {
+	r := wkbReader{data: data}
+	geo, srid, err := r.geometry()
+	if err != nil {
+		return nil, 0, err
+	} else if r.pos != len(r.data) {
+		return nil, 0, fmt.Errorf("unexpected %d bytes after geometry", len(r.data)-r.pos)
+	}
+	return geo, srid, nil
+}
+
+type wkbWriter struct {
+	order    binary.ByteOrder
+	orderTag byte
+	extended bool
+	buf      []byte
+}
+
+func newWKBWriter(order binary.ByteOrder, extended bool) (*wkbWriter, error) {
+	w := wkbWriter{
+		order:    order,
+		extended: extended,
+	}
+
+	switch order {
+	case binary.BigEndian:
+		w.orderTag = 0
+	case binary.LittleEndian:
+		w.orderTag = 1
+	default:
+		return nil, fmt.Errorf("unsupported byte order '%v'", order)
+	}
+	return &w, nil
+}
+
+func (w *wkbWriter) geometry(geo Geometry, srid uint32) ([]byte, error) {
+	// The positions of a collection's members are included, as they must have the same dimension as the collection.
+	var positions []Position
+	eachPosition(geo, func(pos Position) {
+		positions = append(positions, pos)
+	})
+
+	hasZ, _, err := dimension([][]Position{positions})
+	if err != nil {
+		return nil, err
+	} else if err := w.write(geo, srid, hasZ); err != nil {
+		return nil, err
+	}
+	return w.buf, nil
+}
+
+func (w *wkbWriter) write(geo Geometry, srid uint32, hasZ bool) error {
+	var typ uint32
+	switch geo.(type) {
+	case *Point:
+		typ = wkbPoint
+	case *MultiPoint:
+		typ = wkbMultiPoint
+	case *LineString:
+		typ = wkbLineString
+	case *MultiLineString:
+		typ = wkbMultiLineString
+	case *Polygon:
+		typ = wkbPolygon
+	case *MultiPolygon:
+		typ = wkbMultiPolygon
+	case *GeometryCollection:
+		typ = wkbGeometryCollection
+	default:
+		return fmt.Errorf("unsupported geometry type '%T'", geo)
+	}
+
+	w.header(typ, hasZ, srid)
+
+	switch g := geo.(type) {
+	case *Point:
+		w.position(g.pos, hasZ)
+	case *MultiPoint:
+		w.uint32(uint32(len(g.positions)))
+		for _, pos := range g.positions {
+			w.header(wkbPoint, hasZ, 0)
+			w.position(pos, hasZ)
+		}
+	case *LineString:
+		w.positions(g.positions, hasZ)
+	case *MultiLineString:
+		w.uint32(uint32(len(g.lines)))
+		for _, line := range g.lines {
+			w.header(wkbLineString, hasZ, 0)
+			w.positions(line, hasZ)
+		}
+	case *Polygon:
+		w.rings(g.rings, hasZ)
+	case *MultiPolygon:
+		w.uint32(uint32(len(g.polygons)))
+		for _, polygon := range g.polygons {
+			w.header(wkbPolygon, hasZ, 0)
+			w.rings(polygon, hasZ)
+		}
+	case *GeometryCollection:
+		w.uint32(uint32(len(g.geometries)))
+		for _, geo := range g.geometries {
+			if err := w.write(geo, 0, hasZ); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (w *wkbWriter) header(typ uint32, hasZ bool, srid uint32) {
+	w.buf = append(w.buf, w.orderTag)
+
+	switch {
+	case w.extended && hasZ:
+		typ |= ewkbZFlag
+	case hasZ:
+		typ += 1000
+	}
+
+	if w.extended && srid != 0 {
+		w.uint32(typ | ewkbSRIDFlag)
+		w.uint32(srid)
+	} else {
+		w.uint32(typ)
+	}
+}
+
+func (w *wkbWriter) rings(rings [][]Position, hasZ bool) {
+	w.uint32(uint32(len(rings)))
+	for _, ring := range rings {
+		w.positions(ring, hasZ)
+	}
+}
+
+func (w *wkbWriter) positions(positions []Position, hasZ bool) {
+	w.uint32(uint32(len(positions)))
+	for _, pos := range positions {
+		w.position(pos, hasZ)
+	}
+}
+
+func (w *wkbWriter) position(pos Position, hasZ bool) {
+	w.float64(pos.lng)
+	w.float64(pos.lat)
+	if hasZ {
+		w.float64(*pos.elevation)
+	}
+}
+
+func (w *wkbWriter) uint32(v uint32) {
+	var b [4]byte
+	w.order.PutUint32(b[:], v)
+	w.buf = append(w.buf, b[:]...)
+}
+
+func (w *wkbWriter) float64(v float64) {
+	var b [8]byte
+	w.order.PutUint64(b[:], math.Float64bits(v))
+	w.buf = append(w.buf, b[:]...)
+}
+
+type wkbReader struct {
+	data []byte
+	pos  int
+}
+
+type wkbHeader struct {
+	order binary.ByteOrder
+	typ   uint32
+	hasZ  bool
+	hasM  bool
+}
+
+func (r *wkbReader) geometry() (Geometry, uint32, error) {
+	header, srid, err := r.header()
+	if err != nil {
+		return nil, 0, err
+	}
+
+	var geo Geometry
+	switch header.typ {
+	case wkbPoint:
+		pos, err := r.position(header)
+		if err != nil {
+			return nil, 0, err
+		} else if math.IsNaN(pos.lat) {
+			return nil, 0, errEmptyPoint
+		}
+		geo = NewPointFromPosition(pos)
+
+	case wkbLineString:
+		positions, err := r.positions(header)
+		if err != nil {
+			return nil, 0, err
+		}
+		geo = &LineString{positions: positions}
+
+	case wkbPolygon:
+		rings, err := r.rings(header)
+		if err != nil {
+			return nil, 0, err
+		}
+		geo = NewPolygon(rings...)
+
+	case wkbMultiPoint, wkbMultiLineString, wkbMultiPolygon, wkbGeometryCollection:
+		n, err := r.count(header, 5)
+		if err != nil {
+			return nil, 0, err
+		}
+
+		geometries := make([]Geometry, n)
+		for i := range geometries {
+			if geometries[i], _, err = r.geometry(); err != nil {
+				return nil, 0, err
+			}
+		}
+
+		if geo, err = wkbCollect(header.typ, geometries); err != nil {
+			return nil, 0, err
+		}
+
+	default:
+		return nil, 0, fmt.Errorf("unsupported geometry type '%d'", header.typ)
+	}
+	return geo, srid, nil
+}
+
+func wkbCollect(typ uint32, geometries []Geometry) (Geometry, error) {
+	switch typ {
+	case wkbMultiPoint:
+		positions := make([]Position, len(geometries))
+		for i, geo := range geometries {
+			point, ok := geo.(*Point)
+			if !ok {
+				return nil, fmt.Errorf("MultiPoint contains '%s'", geo.Type())
+			}
+			positions[i] = point.pos
+		}
+		return NewMultiPoint(positions...), nil
+
+	case wkbMultiLineString:
+		lines := make([][]Position, len(geometries))
+		for i, geo := range geometries {
+			line, ok := geo.(*LineString)
+			if !ok {
+				return nil, fmt.Errorf("MultiLineString contains '%s'", geo.Type())
+			}
+			lines[i] = line.positions
+		}
+		return NewMultiLineString(lines...), nil
+
+	case wkbMultiPolygon:
+		polygons := make([][][]Position, len(geometries))
+		for i, geo := range geometries {
+			polygon, ok := geo.(*Polygon)
+			if !ok {
+				return nil, fmt.Errorf("MultiPolygon contains '%s'", geo.Type())
+			}
+			polygons[i] = polygon.rings
+		}
+		return NewMultiPolygon(polygons...), nil
+
+	default:
+		return NewGeometryCollection(geometries...), nil
+	}
+}
+
+func (r *wkbReader) header() (wkbHeader, uint32, error) {
+	if r.pos >= len(r.data) {
+		return wkbHeader{}, 0, fmt.Errorf("unexpected end of data")
+	}
+
+	var header wkbHeader
+	switch r.data[r.pos] {
+	case 0:
+		header.order = binary.BigEndian
+	case 1:
+		header.order = binary.LittleEndian
+	default:
+		return wkbHeader{}, 0, fmt.Errorf("invalid byte order '%d'", r.data[r.pos])
+	}
+	r.pos++
+
+	typ, err := r.uint32(header.order)
+	if err != nil {
+		return wkbHeader{}, 0, err
+	}
+
+	var srid uint32
+	if typ&ewkbSRIDFlag != 0 {
+		if srid, err = r.uint32(header.order); err != nil {
+			return wkbHeader{}, 0, err
+		}
+	}
+
+	header.hasZ = typ&ewkbZFlag != 0
+	header.hasM = typ&ewkbMFlag != 0
+	typ &^= ewkbZFlag | ewkbMFlag | ewkbSRIDFlag
+
+	switch typ / 1000 {
+	case 1:
+		header.hasZ = true
+	case 2:
+		header.hasM = true
+	case 3:
+		header.hasZ = true
+		header.hasM = true
+	}
+	header.typ = typ % 1000
+
+	return header, srid, nil
+}
+
+func (r *wkbReader) rings(header wkbHeader) ([][]Position, error) {
+	n, err := r.count(header, 4)
+	if err != nil {
+		return nil, err
+	}
+
+	rings := make([][]Position, n)
+	for i := range rings {
+		if rings[i], err = r.positions(header); err != nil {
+			return nil, err
+		}
+	}
+	return rings, nil
+}
+
+func (r *wkbReader) positions(header wkbHeader) ([]Position, error) {
+	size := 16
+	if header.hasZ {
+		size += 8
+	}
+	if header.hasM {
+		size += 8
+	}
+
+	n, err := r.count(header, size)
+	if err != nil {
+		return nil, err
+	}
+
+	positions := make([]Position, n)
+	for i := range positions {
+		if positions[i], err = r.position(header); err != nil {
+			return nil, err
+		}
+	}
+	return positions, nil
+}
+
+func (r *wkbReader) position(header wkbHeader) (Position, error) {
+	n := 2
+	if header.hasZ {
+		n++
+	}
+	if header.hasM {
+		n++
+	}
+
+	coords := make([]float64, n)
+	for i := range coords {
+		v, err := r.uint64(header.order)
+		if err != nil {
+			return Position{}, err
+		}
+		coords[i] = math.Float64frombits(v)
+	}
+
+	if header.hasZ {
+		return MakePositionWithElevation(coords[1], coords[0], coords[2]), nil
+	}
+	return MakePosition(coords[1], coords[0]), nil
+}
+
+// count reads the number of elements that follow, checking that enough data remains for each of them to be at least minSize bytes.
+func (r *wkbReader) count(header wkbHeader, minSize int) (int, error) {
+	n, err := r.uint32(header.order)
+	if err != nil {
+		return 0, err
+	} else if uint64(n)*uint64(minSize) > uint64(len(r.data)-r.pos) {
+		return 0, fmt.Errorf("count %d exceeds remaining data", n)
+	}
+	return int(n), nil
+}
+
+func (r *wkbReader) uint32(order binary.ByteOrder) (uint32, error) {
+	if len(r.data)-r.pos < 4 {
+		return 0, fmt.Errorf("unexpected end of data")
+	}
+	v := order.Uint32(r.data[r.pos:])
+	r.pos += 4
+	return v, nil
+}
+
+func (r *wkbReader) uint64(order binary.ByteOrder) (uint64, error) {
+	if len(r.data)-r.pos < 8 {
+		return 0, fmt.Errorf("unexpected end of data")
+	}
+	v := order.Uint64(r.data[r.pos:])
+	r.pos += 8
+	return v, nil
+}
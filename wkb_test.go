@@ -0,0 +1,159 @@
+package geojson_test
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"testing"
+
+	geojson "github.com/everystreet/go-geojson/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWKB(t *testing.T) {
+	geometries := map[string]geojson.Geometry{
+		"point":                geojson.NewPoint(45.4642035, 9.189982),
+		"point with elevation": geojson.NewPointWithElevation(45.5, 9.25, 125),
+		"multipoint": geojson.NewMultiPoint(
+			geojson.MakePosition(12, 34),
+			geojson.MakePosition(56, 78),
+		),
+		"linestring": geojson.NewLineString(
+			geojson.MakePositionWithElevation(12, 34, 1),
+			geojson.MakePositionWithElevation(56, 78, 2),
+		),
+		"multilinestring": geojson.NewMultiLineString(
+			[]geojson.Position{
+				geojson.MakePosition(12, 34),
+				geojson.MakePosition(56, 78),
+			},
+			[]geojson.Position{
+				geojson.MakePosition(23, 45),
+				geojson.MakePosition(67, 89),
+			},
+		),
+		"polygon": geojson.NewPolygon(
+			[]geojson.Position{
+				geojson.MakePosition(7, 7),
+				geojson.MakePosition(4, 8),
+				geojson.MakePosition(3, 4),
+				geojson.MakePosition(7, 7),
+			},
+		),
+		"multipolygon": geojson.NewMultiPolygon(
+			[][]geojson.Position{
+				{
+					geojson.MakePosition(7, 7),
+					geojson.MakePosition(4, 8),
+					geojson.MakePosition(3, 4),
+					geojson.MakePosition(7, 7),
+				},
+			},
+		),
+		"geometry collection": geojson.NewGeometryCollection(
+			geojson.NewPoint(1, 2),
+			geojson.NewLineString(geojson.MakePosition(3, 4), geojson.MakePosition(5, 6)),
+		),
+		"geometry collection with elevation": geojson.NewGeometryCollection(
+			geojson.NewPointWithElevation(1, 2, 3),
+			geojson.NewGeometryCollection(
+				geojson.NewLineString(geojson.MakePositionWithElevation(3, 4, 5), geojson.MakePositionWithElevation(5, 6, 7)),
+			),
+		),
+	}
+
+	for name, geometry := range geometries {
+		t.Run(name, func(t *testing.T) {
+			for _, order := range []binary.ByteOrder{binary.LittleEndian, binary.BigEndian} {
+				data, err := geojson.MarshalWKB(geometry, order)
+				require.NoError(t, err)
+
+				geo, err := geojson.UnmarshalWKB(data)
+				require.NoError(t, err)
+				require.Equal(t, geometry, geo)
+
+				data, err = geojson.MarshalEWKB(geometry, order, 4326)
+				require.NoError(t, err)
+
+				geo, srid, err := geojson.UnmarshalEWKB(data)
+				require.NoError(t, err)
+				require.Equal(t, geometry, geo)
+				require.Equal(t, uint32(4326), srid)
+			}
+		})
+	}
+}
+
+func TestWKBEncoding(t *testing.T) {
+	data, err := geojson.MarshalWKB(geojson.NewPoint(2, 1), binary.LittleEndian)
+	require.NoError(t, err)
+	require.Equal(t, "0101000000000000000000f03f0000000000000040", hex.EncodeToString(data))
+
+	data, err = geojson.MarshalWKB(geojson.NewPointWithElevation(2, 1, 3), binary.BigEndian)
+	require.NoError(t, err)
+	require.Equal(t, "00000003e93ff000000000000040000000000000004008000000000000", hex.EncodeToString(data))
+
+	data, err = geojson.MarshalEWKB(geojson.NewPoint(2, 1), binary.LittleEndian, 4326)
+	require.NoError(t, err)
+	require.Equal(t, "0101000020e6100000000000000000f03f0000000000000040", hex.EncodeToString(data))
+
+	data, err = geojson.MarshalEWKB(geojson.NewPointWithElevation(2, 1, 3), binary.LittleEndian, 0)
+	require.NoError(t, err)
+	require.Equal(t, "0101000080000000000000f03f00000000000000400000000000000840", hex.EncodeToString(data))
+
+	// The collection has the same dimension as its members.
+	collection := geojson.NewGeometryCollection(geojson.NewPointWithElevation(2, 1, 3))
+	data, err = geojson.MarshalWKB(collection, binary.LittleEndian)
+	require.NoError(t, err)
+	require.Equal(t, "01ef0300000100000001e9030000000000000000f03f00000000000000400000000000000840", hex.EncodeToString(data))
+
+	data, err = geojson.MarshalEWKB(collection, binary.LittleEndian, 4326)
+	require.NoError(t, err)
+	require.Equal(t, "01070000a0e6100000010000000101000080000000000000f03f00000000000000400000000000000840", hex.EncodeToString(data))
+}
+
+func TestUnmarshalWKBWithMeasure(t *testing.T) {
+	// POINT ZM (1 2 3 4) in ISO WKB.
+	data, err := hex.DecodeString("01b90b0000000000000000f03f000000000000004000000000000008400000000000001040")
+	require.NoError(t, err)
+
+	geo, err := geojson.UnmarshalWKB(data)
+	require.NoError(t, err)
+	require.Equal(t, geojson.NewPointWithElevation(2, 1, 3), geo)
+}
+
+func TestUnmarshalWKBErrors(t *testing.T) {
+	tests := map[string]string{
+		"empty":         "",
+		"byte order":    "02",
+		"truncated":     "0101000000000000000000f03f",
+		"trailing data": "0101000000000000000000f03f000000000000004000",
+		"unknown type":  "0109000000",
+		"empty point":   "0101000000000000000000f87f000000000000f87f",
+		"huge count":    "0102000000ffffffff",
+		"mixed multi":   "010400000001000000010200000000000000",
+	}
+
+	for name, data := range tests {
+		t.Run(name, func(t *testing.T) {
+			b, err := hex.DecodeString(data)
+			require.NoError(t, err)
+
+			_, err = geojson.UnmarshalWKB(b)
+			require.Error(t, err)
+		})
+	}
+}
+
+func TestMarshalWKBMixedDimensions(t *testing.T) {
+	_, err := geojson.MarshalWKB(geojson.NewLineString(
+		geojson.MakePosition(12, 34),
+		geojson.MakePositionWithElevation(56, 78, 1),
+	), binary.LittleEndian)
+	require.Error(t, err)
+
+	_, err = geojson.MarshalEWKB(geojson.NewGeometryCollection(
+		geojson.NewPoint(1, 2),
+		geojson.NewGeometryCollection(geojson.NewPointWithElevation(3, 4, 5)),
+	), binary.LittleEndian, 0)
+	require.Error(t, err)
+}
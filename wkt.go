@@ -0,0 +1,425 @@
+package geojson
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// WKTSyntaxError describes a problem with Well-Known Text input.
+type WKTSyntaxError struct {
+	Offset int
+	Msg    string
+}
+
+func (e *WKTSyntaxError) Error() string {
+	return fmt.Sprintf("%s at offset %d", e.Msg, e.Offset)
+}
+
+// MarshalWKT returns the Well-Known Text encoding of the geometry.
+func MarshalWKT(geo Geometry) (string, error) {
+	var b strings.Builder
+	if err := writeWKT(&b, geo); err != nil {
+		return "", err
+	}
+	return b.String(), nil
+}
+
+// UnmarshalWKT parses the Well-Known Text encoded geometry.
+func UnmarshalWKT(text string) (Geometry, error) {
+	p := wktParser{text: text}
+	geo, err := p.geometry()
+	if err != nil {
+		return nil, err
+	}
+
+	if p.skipSpace(); p.pos != len(p.text) {
+		return nil, p.errorf("unexpected '%c'", p.text[p.pos])
+	}
+	return geo, nil
+}
+
+func writeWKT(b *strings.Builder, geo Geometry) error {
+	var positions [][]Position
+	var write func()
+
+	switch g := geo.(type) {
+	case *Point:
+		positions = [][]Position{{g.pos}}
+		write = func() { writeWKTPosition(b, g.pos) }
+	case *MultiPoint:
+		positions = [][]Position{g.positions}
+		write = func() { writeWKTPositions(b, g.positions, true) }
+	case *LineString:
+		positions = [][]Position{g.positions}
+		write = func() { writeWKTPositions(b, g.positions, false) }
+	case *MultiLineString:
+		positions = g.lines
+		write = func() { writeWKTRings(b, g.lines) }
+	case *Polygon:
+		positions = g.rings
+		write = func() { writeWKTRings(b, g.rings) }
+	case *MultiPolygon:
+		for _, polygon := range g.polygons {
+			positions = append(positions, polygon...)
+		}
+		write = func() {
+			writeWKTList(b, len(g.polygons), func(i int) { writeWKTRings(b, g.polygons[i]) })
+		}
+	case *GeometryCollection:
+		b.WriteString("GEOMETRYCOLLECTION")
+		if len(g.geometries) == 0 {
+			b.WriteString(" EMPTY")
+			return nil
+		}
+
+		b.WriteString(" (")
+		for i, geo := range g.geometries {
+			if i > 0 {
+				b.WriteString(", ")
+			}
+			if err := writeWKT(b, geo); err != nil {
+				return err
+			}
+		}
+		b.WriteByte(')')
+		return nil
+	default:
+		return fmt.Errorf("unsupported geometry type '%T'", geo)
+	}
+
+	b.WriteString(strings.ToUpper(string(geo.Type())))
+
+	hasZ, empty, err := dimension(positions)
+	if err != nil {
+		return err
+	} else if empty {
+		b.WriteString(" EMPTY")
+		return nil
+	} else if hasZ {
+		b.WriteString(" Z")
+	}
+
+	b.WriteByte(' ')
+	write()
+	return nil
+}
+
+func writeWKTRings(b *strings.Builder, rings [][]Position) {
+	writeWKTList(b, len(rings), func(i int) { writeWKTPositions(b, rings[i], false) })
+}
+
+func writeWKTPositions(b *strings.Builder, positions []Position, parenthesize bool) {
+	if len(positions) == 0 {
+		b.WriteString("EMPTY")
+		return
+	}
+
+	writeWKTList(b, len(positions), func(i int) {
+		if parenthesize {
+			writeWKTPosition(b, positions[i])
+		} else {
+			writeWKTCoords(b, positions[i])
+		}
+	})
+}
+
+func writeWKTList(b *strings.Builder, n int, write func(int)) {
+	if n == 0 {
+		b.WriteString("EMPTY")
+		return
+	}
+
+	b.WriteByte('(')
+	for i := 0; i < n; i++ {
+		if i > 0 {
+			b.WriteString(", ")
+		}
+		write(i)
+	}
+	b.WriteByte(')')
+}
+
+func writeWKTPosition(b *strings.Builder, pos Position) {
+	b.WriteByte('(')
+	writeWKTCoords(b, pos)
+	b.WriteByte(')')
+}
+
+func writeWKTCoords(b *strings.Builder, pos Position) {
+	b.WriteString(strconv.FormatFloat(pos.lng, 'f', -1, 64))
+	b.WriteByte(' ')
+	b.WriteString(strconv.FormatFloat(pos.lat, 'f', -1, 64))
+	if pos.elevation != nil {
+		b.WriteByte(' ')
+		b.WriteString(strconv.FormatFloat(*pos.elevation, 'f', -1, 64))
+	}
+}
+
+type wktParser struct {
+	text string
+	pos  int
+}
+
+func (p *wktParser) geometry() (Geometry, error) {
+	p.skipSpace()
+	start := p.pos
+	tag := strings.ToUpper(p.word())
+	if tag == "" {
+		return nil, p.errorf("expecting geometry type")
+	}
+
+	var hasZ bool
+	p.skipSpace()
+	switch dim := strings.ToUpper(p.peekWord()); dim {
+	case "Z":
+		p.word()
+		hasZ = true
+	case "M", "ZM":
+		return nil, p.errorf("unsupported dimension '%s'", dim)
+	}
+
+	empty := strings.ToUpper(p.peekWord()) == "EMPTY"
+	if empty {
+		p.word()
+	}
+
+	switch tag {
+	case "POINT":
+		if empty {
+			return nil, &WKTSyntaxError{Offset: start, Msg: "empty point can't be represented"}
+		}
+		pos, err := p.parenthesized(func() (Position, error) { return p.coords(hasZ) })
+		if err != nil {
+			return nil, err
+		}
+		return NewPointFromPosition(pos), nil
+
+	case "MULTIPOINT":
+		if empty {
+			return NewMultiPoint(), nil
+		}
+		positions, err := parseWKTList(p, func() (Position, error) {
+			if p.skipSpace(); p.peek() == '(' {
+				return p.parenthesized(func() (Position, error) { return p.coords(hasZ) })
+			}
+			return p.coords(hasZ)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return NewMultiPoint(positions...), nil
+
+	case "LINESTRING":
+		if empty {
+			return &LineString{}, nil
+		}
+		positions, err := p.positions(hasZ)
+		if err != nil {
+			return nil, err
+		}
+		return &LineString{positions: positions}, nil
+
+	case "MULTILINESTRING":
+		if empty {
+			return NewMultiLineString(), nil
+		}
+		lines, err := p.rings(hasZ)
+		if err != nil {
+			return nil, err
+		}
+		return NewMultiLineString(lines...), nil
+
+	case "POLYGON":
+		if empty {
+			return NewPolygon(), nil
+		}
+		rings, err := p.rings(hasZ)
+		if err != nil {
+			return nil, err
+		}
+		return NewPolygon(rings...), nil
+
+	case "MULTIPOLYGON":
+		if empty {
+			return NewMultiPolygon(), nil
+		}
+		polygons, err := parseWKTList(p, func() ([][]Position, error) {
+			if p.emptyKeyword() {
+				return [][]Position{}, nil
+			}
+			return p.rings(hasZ)
+		})
+		if err != nil {
+			return nil, err
+		}
+		return NewMultiPolygon(polygons...), nil
+
+	case "GEOMETRYCOLLECTION":
+		if empty {
+			return NewGeometryCollection(), nil
+		}
+		geometries, err := parseWKTList(p, p.geometry)
+		if err != nil {
+			return nil, err
+		}
+		return NewGeometryCollection(geometries...), nil
+
+	default:
+		return nil, &WKTSyntaxError{Offset: start, Msg: fmt.Sprintf("unknown geometry type '%s'", tag)}
+	}
+}
+
+func (p *wktParser) rings(hasZ bool) ([][]Position, error) {
+	return parseWKTList(p, func() ([]Position, error) {
+		if p.emptyKeyword() {
+			return []Position{}, nil
+		}
+		return p.positions(hasZ)
+	})
+}
+
+func (p *wktParser) positions(hasZ bool) ([]Position, error) {
+	return parseWKTList(p, func() (Position, error) { return p.coords(hasZ) })
+}
+
+func (p *wktParser) coords(hasZ bool) (Position, error) {
+	var nums []float64
+	for len(nums) < 3 {
+		if p.skipSpace(); len(nums) == 2 && !isWKTNumberStart(p.peek()) {
+			break
+		}
+
+		num, err := p.number()
+		if err != nil {
+			return Position{}, err
+		}
+		nums = append(nums, num)
+	}
+
+	switch {
+	case hasZ && len(nums) != 3:
+		return Position{}, p.errorf("expecting 3 coordinates")
+	case len(nums) == 3:
+		return MakePositionWithElevation(nums[1], nums[0], nums[2]), nil
+	default:
+		return MakePosition(nums[1], nums[0]), nil
+	}
+}
+
+func (p *wktParser) number() (float64, error) {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.text) && isWKTNumberChar(p.text[p.pos]) {
+		p.pos++
+	}
+
+	if start == p.pos {
+		if p.pos == len(p.text) {
+			return 0, p.errorf("unexpected end of input")
+		}
+		return 0, p.errorf("expecting number but found '%c'", p.text[p.pos])
+	}
+
+	num, err := strconv.ParseFloat(p.text[start:p.pos], 64)
+	if err != nil {
+		return 0, &WKTSyntaxError{Offset: start, Msg: fmt.Sprintf("invalid number '%s'", p.text[start:p.pos])}
+	}
+	return num, nil
+}
+
+func (p *wktParser) emptyKeyword() bool {
+	if strings.ToUpper(p.peekWord()) == "EMPTY" {
+		p.word()
+		return true
+	}
+	return false
+}
+
+func (p *wktParser) word() string {
+	p.skipSpace()
+	start := p.pos
+	for p.pos < len(p.text) && isWKTLetter(p.text[p.pos]) {
+		p.pos++
+	}
+	return p.text[start:p.pos]
+}
+
+func (p *wktParser) peekWord() string {
+	pos := p.pos
+	word := p.word()
+	p.pos = pos
+	return word
+}
+
+func (p *wktParser) expect(c byte) error {
+	if p.skipSpace(); p.pos == len(p.text) {
+		return p.errorf("unexpected end of input, expecting '%c'", c)
+	} else if p.text[p.pos] != c {
+		return p.errorf("unexpected '%c', expecting '%c'", p.text[p.pos], c)
+	}
+	p.pos++
+	return nil
+}
+
+func (p *wktParser) peek() byte {
+	if p.pos == len(p.text) {
+		return 0
+	}
+	return p.text[p.pos]
+}
+
+func (p *wktParser) skipSpace() {
+	for p.pos < len(p.text) && strings.IndexByte(" \t\r\n", p.text[p.pos]) != -1 {
+		p.pos++
+	}
+}
+
+func (p *wktParser) errorf(format string, args ...interface{}) error {
+	return &WKTSyntaxError{Offset: p.pos, Msg: fmt.Sprintf(format, args...)}
+}
+
+func (p *wktParser) parenthesized(parse func() (Position, error)) (Position, error) {
+	if err := p.expect('('); err != nil {
+		return Position{}, err
+	}
+
+	pos, err := parse()
+	if err != nil {
+		return Position{}, err
+	}
+	return pos, p.expect(')')
+}
+
+func parseWKTList[T any](p *wktParser, parse func() (T, error)) ([]T, error) {
+	if err := p.expect('('); err != nil {
+		return nil, err
+	}
+
+	var list []T
+	for {
+		item, err := parse()
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, item)
+
+		if p.skipSpace(); p.peek() != ',' {
+			break
+		}
+		p.pos++
+	}
+	return list, p.expect(')')
+}
+
+func isWKTLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isWKTNumberStart(c byte) bool {
+	return (c >= '0' && c <= '9') || c == '-' || c == '+' || c == '.'
+}
+
+func isWKTNumberChar(c byte) bool {
+	return isWKTNumberStart(c) || c == 'e' || c == 'E'
+}
@@ -0,0 +1,186 @@
+package geojson_test
+
+import (
+	"errors"
+	"testing"
+
+	geojson "github.com/everystreet/go-geojson/v3"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWKT(t *testing.T) {
+	tests := []struct {
+		name     string
+		geometry geojson.Geometry
+		wkt      string
+	}{
+		{
+			name:     "point",
+			geometry: geojson.NewPoint(45.4642035, 9.189982),
+			wkt:      "POINT (9.189982 45.4642035)",
+		},
+		{
+			name:     "point with elevation",
+			geometry: geojson.NewPointWithElevation(45.5, 9.25, 125),
+			wkt:      "POINT Z (9.25 45.5 125)",
+		},
+		{
+			name: "multipoint",
+			geometry: geojson.NewMultiPoint(
+				geojson.MakePosition(12, 34),
+				geojson.MakePosition(56, 78),
+			),
+			wkt: "MULTIPOINT ((34 12), (78 56))",
+		},
+		{
+			name:     "empty multipoint",
+			geometry: geojson.NewMultiPoint(),
+			wkt:      "MULTIPOINT EMPTY",
+		},
+		{
+			name: "linestring",
+			geometry: geojson.NewLineString(
+				geojson.MakePositionWithElevation(12, 34, 1),
+				geojson.MakePositionWithElevation(56, 78, 2),
+			),
+			wkt: "LINESTRING Z (34 12 1, 78 56 2)",
+		},
+		{
+			name:     "empty linestring",
+			geometry: &geojson.LineString{},
+			wkt:      "LINESTRING EMPTY",
+		},
+		{
+			name: "multilinestring",
+			geometry: geojson.NewMultiLineString(
+				[]geojson.Position{
+					geojson.MakePosition(12, 34),
+					geojson.MakePosition(56, 78),
+				},
+				[]geojson.Position{},
+				[]geojson.Position{
+					geojson.MakePosition(23, 45),
+					geojson.MakePosition(67, 89),
+				},
+			),
+			wkt: "MULTILINESTRING ((34 12, 78 56), EMPTY, (45 23, 89 67))",
+		},
+		{
+			name: "polygon",
+			geometry: geojson.NewPolygon(
+				[]geojson.Position{
+					geojson.MakePosition(7, 7),
+					geojson.MakePosition(4, 8),
+					geojson.MakePosition(3, 4),
+					geojson.MakePosition(7, 7),
+				},
+				[]geojson.Position{
+					geojson.MakePosition(5, 5),
+					geojson.MakePosition(5, 6),
+					geojson.MakePosition(6, 6),
+					geojson.MakePosition(5, 5),
+				},
+			),
+			wkt: "POLYGON ((7 7, 8 4, 4 3, 7 7), (5 5, 6 5, 6 6, 5 5))",
+		},
+		{
+			name:     "empty polygon",
+			geometry: geojson.NewPolygon(),
+			wkt:      "POLYGON EMPTY",
+		},
+		{
+			name: "multipolygon",
+			geometry: geojson.NewMultiPolygon(
+				[][]geojson.Position{
+					{
+						geojson.MakePosition(7, 7),
+						geojson.MakePosition(4, 8),
+						geojson.MakePosition(3, 4),
+						geojson.MakePosition(7, 7),
+					},
+				},
+				[][]geojson.Position{
+					{
+						geojson.MakePosition(1, 1),
+						geojson.MakePosition(1, 2),
+						geojson.MakePosition(2, 2),
+						geojson.MakePosition(1, 1),
+					},
+				},
+			),
+			wkt: "MULTIPOLYGON (((7 7, 8 4, 4 3, 7 7)), ((1 1, 2 1, 2 2, 1 1)))",
+		},
+		{
+			name: "geometry collection",
+			geometry: geojson.NewGeometryCollection(
+				geojson.NewPoint(1, 2),
+				geojson.NewPointWithElevation(3, 4, 5),
+				geojson.NewGeometryCollection(),
+			),
+			wkt: "GEOMETRYCOLLECTION (POINT (2 1), POINT Z (4 3 5), GEOMETRYCOLLECTION EMPTY)",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			wkt, err := geojson.MarshalWKT(tt.geometry)
+			require.NoError(t, err)
+			require.Equal(t, tt.wkt, wkt)
+
+			geo, err := geojson.UnmarshalWKT(wkt)
+			require.NoError(t, err)
+			require.Equal(t, tt.geometry, geo)
+		})
+	}
+}
+
+func TestUnmarshalWKT(t *testing.T) {
+	t.Run("relaxed syntax", func(t *testing.T) {
+		geo, err := geojson.UnmarshalWKT(" multipoint(1 2,3 4 ) ")
+		require.NoError(t, err)
+		require.Equal(t, geojson.NewMultiPoint(
+			geojson.MakePosition(2, 1),
+			geojson.MakePosition(4, 3),
+		), geo)
+	})
+
+	t.Run("implicit elevation", func(t *testing.T) {
+		geo, err := geojson.UnmarshalWKT("POINT (1 2 3)")
+		require.NoError(t, err)
+		require.Equal(t, geojson.NewPointWithElevation(2, 1, 3), geo)
+	})
+
+	errorTests := []struct {
+		name   string
+		wkt    string
+		offset int
+		msg    string
+	}{
+		{"unknown type", "TRIANGLE (1 2)", 0, "unknown geometry type"},
+		{"missing coordinate", "POINT (1)", 8, "expecting number"},
+		{"missing elevation", "POINT Z (1 2)", 12, "expecting 3 coordinates"},
+		{"unclosed", "LINESTRING (1 2, 3 4", 20, "unexpected end of input"},
+		{"trailing", "POINT (1 2) x", 12, "unexpected 'x'"},
+		{"measure", "POINT M (1 2 3)", 6, "unsupported dimension"},
+		{"empty point", "POINT EMPTY", 0, "empty point"},
+	}
+
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := geojson.UnmarshalWKT(tt.wkt)
+
+			var syntaxErr *geojson.WKTSyntaxError
+			require.True(t, errors.As(err, &syntaxErr))
+			require.Equal(t, tt.offset, syntaxErr.Offset)
+			require.Contains(t, syntaxErr.Msg, tt.msg)
+		})
+	}
+}
+
+func TestMarshalWKTMixedDimensions(t *testing.T) {
+	_, err := geojson.MarshalWKT(geojson.NewLineString(
+		geojson.MakePosition(12, 34),
+		geojson.MakePositionWithElevation(56, 78, 1),
+	))
+	require.Error(t, err)
+}